@@ -3,10 +3,18 @@ package main
 import (
 	"context"
 	"fmt"
+	"go-api/internal/alerting"
 	apiv1 "go-api/internal/api/v1"
+	"go-api/internal/audit"
+	"go-api/internal/escalation"
+	"go-api/internal/events"
+	"go-api/internal/notify"
 	"go-api/internal/repositories/postgres"
+	"go-api/internal/webhooks"
+	"go-api/internal/workers"
 	"go-api/pkg/config"
 	"go-api/pkg/database"
+	"go-api/pkg/mail"
 	"go-api/pkg/util"
 	"log/slog"
 	"net/http"
@@ -42,10 +50,39 @@ func main() {
 	util.RegisterCustomValidator(validate)
 
 	repository := postgres.NewRepository(db)
-	handlerV1 := apiv1.NewHandler(validate, repository)
+	broker := events.NewBroker()
+	dispatcher := webhooks.NewDispatcher(repository, &http.Client{Timeout: 10 * time.Second})
+	handlerV1 := apiv1.NewHandler(validate, repository, broker, dispatcher)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	go workers.Every(workerCtx, "expire-device-commands", time.Minute, workers.ExpireDeviceCommands(repository, broker))
+	go workers.Every(workerCtx, "evaluate-alerts", time.Minute, workers.EvaluateAlerts(alerting.NewEvaluator(repository, broker)))
+	go workers.Every(workerCtx, "deliver-webhooks", 15*time.Second, workers.DeliverWebhooks(dispatcher))
+	go workers.Every(workerCtx, "purge-trash", time.Hour, workers.PurgeTrash(repository, conf.TrashRetention))
+	go dispatcher.Run(workerCtx, broker)
+
+	var notifier *notify.EmailNotifier
+	if conf.SMTPHost != "" {
+		sender := mail.NewSMTP(conf.SMTPHost, conf.SMTPPort, conf.SMTPUser, conf.SMTPPass)
+		notifier, err = notify.NewEmailNotifier(repository, sender, conf.SMTPFrom, conf.SMTPBatchWindow)
+		if err != nil {
+			panic(err)
+		}
+
+		go notifier.Run(workerCtx, broker)
+		if len(conf.SMTPReportTo) > 0 {
+			go workers.Every(workerCtx, "send-alert-report", 10*time.Minute, workers.SendAlertReport(notifier, conf.SMTPReportTo, conf.SMTPReportHour))
+		}
+	}
+
+	escalator := escalation.NewEscalator(repository, dispatcher, notifier)
+	go workers.Every(workerCtx, "escalate-alerts", 15*time.Second, workers.EscalateAlerts(escalator))
 
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
+	r.Use(audit.Middleware)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(time.Second * 60))
@@ -66,7 +103,7 @@ func main() {
 	go func() {
 		slog.Info("Starting HTTP server...", "port", conf.Port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			slog.Error("ListenAndServe: %v", err)
+			slog.Error("ListenAndServe failed", slog.Any("err", err))
 		}
 	}()
 
@@ -77,9 +114,11 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	stopWorkers()
+
 	slog.Info("Shutting down HTTP server...")
 	if err := server.Shutdown(ctx); err != nil {
-		slog.Error("Server shutdown failed: %v", err)
+		slog.Error("Server shutdown failed", slog.Any("err", err))
 	}
 
 	slog.Info("HTTP server gracefully stopped.")
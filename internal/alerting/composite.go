@@ -0,0 +1,254 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/condition"
+	"go-api/pkg/util"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MAX_DRY_RUN_READINGS bounds the readings a dry run replays.
+const MAX_DRY_RUN_READINGS = 100000
+
+// EvaluateComposite evaluates the enabled composite rules with the latest readings of their sensors, for every
+// device when the rule is on the same device. The value of a composite alert is 1 while the condition holds
+// and 0 otherwise, devices in a maintenance window are left alone.
+func (e *Evaluator) EvaluateComposite(ctx context.Context, now time.Time) error {
+	rules, err := e.repo.GetEnabledAlertRules(ctx, entities.ALERT_CONDITION_COMPOSITE)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		c, err := condition.Parse(rule.Expression)
+		if err != nil {
+			continue
+		}
+
+		values, err := e.repo.GetConditionValues(ctx, conditionSensors(c), now)
+		if err != nil {
+			return err
+		}
+
+		scopes := scopeValues(c, values)
+		for _, deviceID := range sortedKeys(scopes) {
+			if deviceID != "" {
+				maintenance, err := e.repo.IsDeviceInMaintenance(ctx, deviceID, now)
+				if err != nil {
+					return err
+				}
+				if maintenance {
+					continue
+				}
+			}
+
+			value := 0.0
+			if c.Eval(refValues(c, scopes[deviceID])) {
+				value = 1
+			}
+
+			changes := []entities.Alert{}
+			_, err = e.repo.UpdateCompositeAlert(ctx, rule.ID, deviceID, func(alert *entities.Alert) ([]*entities.AlertEvent, error) {
+				status := alert.Status
+				Evaluate(rule, alert, value, now)
+				if alert.Status != status {
+					changes = append(changes, *alert)
+				}
+				return timeline(changes), nil
+			})
+			if err != nil {
+				return err
+			}
+
+			e.publish(changes)
+		}
+	}
+
+	return nil
+}
+
+// dryRunScope is the state of a condition for one device during a dry run.
+type dryRunScope struct {
+	since  *time.Time
+	firing *entities.AlertRuleFiring
+}
+
+// DryRun replays the readings recorded from from until to through a condition and returns when it would have
+// fired. The condition starts from the latest readings before from and is evaluated at every recorded time,
+// an occurrence fires once the condition held for its for duration.
+func (e *Evaluator) DryRun(ctx context.Context, c *condition.Condition, from, to time.Time) (*entities.AlertRuleDryRun, error) {
+	sensors := conditionSensors(c)
+
+	seed, err := e.repo.GetConditionValues(ctx, sensors, from)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := e.repo.GetConditionValueHistory(ctx, sensors, from, to, MAX_DRY_RUN_READINGS+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > MAX_DRY_RUN_READINGS {
+		return nil, util.NewErrInvalidRequest(fmt.Sprintf("more than %d readings to replay, narrow the time range", MAX_DRY_RUN_READINGS))
+	}
+
+	result := &entities.AlertRuleDryRun{
+		Expression: c.String(),
+		From:       from,
+		To:         to,
+		Readings:   len(history),
+		Firings:    []*entities.AlertRuleFiring{},
+	}
+
+	latest := map[string]*entities.ConditionValue{}
+	for _, v := range seed {
+		latest[v.SensorID] = v
+	}
+	scopes := map[string]*dryRunScope{}
+
+	// fireDue fires the occurrences that held for the for duration by the given time
+	fireDue := func(at time.Time) {
+		for _, deviceID := range sortedKeys(scopes) {
+			s := scopes[deviceID]
+			if s.since == nil || s.firing != nil || s.since.Add(c.For).After(at) {
+				continue
+			}
+			s.firing = &entities.AlertRuleFiring{
+				DeviceID:     deviceID,
+				PendingSince: *s.since,
+				FiredAt:      s.since.Add(c.For),
+			}
+			result.Firings = append(result.Firings, s.firing)
+		}
+	}
+
+	evaluate := func(at time.Time, deviceIDs map[string]bool) {
+		values := []*entities.ConditionValue{}
+		for _, v := range latest {
+			values = append(values, v)
+		}
+
+		for deviceID, scopeValues := range scopeValues(c, values) {
+			if deviceIDs != nil && !deviceIDs[deviceID] {
+				continue
+			}
+			s, ok := scopes[deviceID]
+			if !ok {
+				s = &dryRunScope{}
+				scopes[deviceID] = s
+			}
+
+			switch {
+			case c.Eval(refValues(c, scopeValues)):
+				if s.since == nil {
+					since := at
+					s.since = &since
+				}
+			case s.firing != nil:
+				resolvedAt := at
+				s.firing.ResolvedAt = &resolvedAt
+				s.firing = nil
+				s.since = nil
+			default:
+				s.since = nil
+			}
+		}
+		fireDue(at)
+	}
+
+	evaluate(from, nil)
+	for i := 0; i < len(history); {
+		at := history[i].RecordedAt
+		fireDue(at)
+
+		changed := map[string]bool{}
+		for ; i < len(history) && history[i].RecordedAt.Equal(at); i++ {
+			v := history[i]
+			if previous, ok := latest[v.SensorID]; ok {
+				changed[scope(c, previous)] = true
+			}
+			latest[v.SensorID] = v
+			changed[scope(c, v)] = true
+		}
+		evaluate(at, changed)
+	}
+	fireDue(to)
+
+	sort.SliceStable(result.Firings, func(i, j int) bool {
+		return result.Firings[i].FiredAt.Before(result.Firings[j].FiredAt)
+	})
+
+	return result, nil
+}
+
+// conditionSensors returns the selection of the sensors of a condition's operands.
+func conditionSensors(c *condition.Condition) entities.ConditionSensors {
+	sensors := entities.ConditionSensors{IDs: []string{}, Names: []string{}, Types: []string{}}
+	for _, ref := range c.Refs() {
+		switch ref.Kind {
+		case condition.REF_SENSOR_ID:
+			sensors.IDs = append(sensors.IDs, ref.Value)
+		case condition.REF_SENSOR_NAME:
+			sensors.Names = append(sensors.Names, ref.Value)
+		case condition.REF_SENSOR_TYPE:
+			sensors.Types = append(sensors.Types, ref.Value)
+		}
+	}
+	return sensors
+}
+
+// scope returns the device a value is evaluated for, empty when the condition is not on the same device.
+func scope(c *condition.Condition, v *entities.ConditionValue) string {
+	if c.SameDevice {
+		return v.DeviceID
+	}
+	return ""
+}
+
+// scopeValues groups values by the device they are evaluated for.
+func scopeValues(c *condition.Condition, values []*entities.ConditionValue) map[string][]*entities.ConditionValue {
+	scopes := map[string][]*entities.ConditionValue{}
+	for _, v := range values {
+		deviceID := scope(c, v)
+		scopes[deviceID] = append(scopes[deviceID], v)
+	}
+	return scopes
+}
+
+// refValues returns the values every operand of the condition stands for.
+func refValues(c *condition.Condition, values []*entities.ConditionValue) map[condition.Ref][]float64 {
+	result := map[condition.Ref][]float64{}
+	for _, ref := range c.Refs() {
+		for _, v := range values {
+			if matches(ref, v) {
+				result[ref] = append(result[ref], v.Value)
+			}
+		}
+	}
+	return result
+}
+
+func matches(ref condition.Ref, v *entities.ConditionValue) bool {
+	switch ref.Kind {
+	case condition.REF_SENSOR_ID:
+		return strings.EqualFold(v.SensorID, ref.Value)
+	case condition.REF_SENSOR_NAME:
+		return strings.ToLower(v.SensorName) == ref.Value
+	case condition.REF_SENSOR_TYPE:
+		return string(v.SensorType) == ref.Value
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,269 @@
+package alerting
+
+import (
+	"context"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/internal/repositories"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// WINDOW_PAGE_SIZE is the number of readings loaded per query for the windows of rate of change rules.
+const WINDOW_PAGE_SIZE = 1000
+
+// statusEvents maps the alert statuses that are announced to their event type.
+var statusEvents = map[entities.AlertStatus]string{
+	entities.ALERT_STATUS_PENDING:  events.ALERT_PENDING,
+	entities.ALERT_STATUS_FIRING:   events.ALERT_FIRING,
+	entities.ALERT_STATUS_RESOLVED: events.ALERT_RESOLVED,
+}
+
+// Evaluator runs the alert rules of sensors against their readings and publishes alert status changes.
+type Evaluator struct {
+	repo   repositories.IRepository
+	broker *events.Broker
+}
+
+func NewEvaluator(repo repositories.IRepository, broker *events.Broker) *Evaluator {
+	return &Evaluator{
+		repo:   repo,
+		broker: broker,
+	}
+}
+
+// EvaluateReadings evaluates every enabled rule of the reading sensors, reading by reading in recorded order.
+// Readings recorded during maintenance and readings older than the last evaluated one are skipped.
+// The readings are already stored, so a failure is logged rather than returned.
+func (e *Evaluator) EvaluateReadings(ctx context.Context, readings []*entities.SensorReading) {
+	err := e.evaluateReadings(ctx, readings)
+	if err != nil {
+		slog.Error(
+			"Failed to EvaluateReadings",
+			slog.Any("err", err),
+		)
+	}
+}
+
+func (e *Evaluator) evaluateReadings(ctx context.Context, readings []*entities.SensorReading) error {
+	bySensor := map[string][]*entities.SensorReading{}
+	sensorIDs := []string{}
+	for _, v := range readings {
+		if v.Maintenance {
+			continue
+		}
+		if _, ok := bySensor[v.SensorID]; !ok {
+			sensorIDs = append(sensorIDs, v.SensorID)
+		}
+		bySensor[v.SensorID] = append(bySensor[v.SensorID], v)
+	}
+
+	for _, sensorID := range sensorIDs {
+		sensorReadings := bySensor[sensorID]
+		sort.SliceStable(sensorReadings, func(i, j int) bool {
+			return sensorReadings[i].RecordedAt.Before(sensorReadings[j].RecordedAt)
+		})
+
+		rules, err := e.repo.GetSensorAlertRules(ctx, sensorID)
+		if err != nil {
+			return err
+		}
+
+		for _, rule := range rules {
+			err := e.evaluateRule(ctx, rule, sensorReadings)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *Evaluator) evaluateRule(ctx context.Context, rule *entities.AlertRule, readings []*entities.SensorReading) error {
+	latest := readings[len(readings)-1]
+	changes := []entities.Alert{}
+
+	values := map[*entities.SensorReading]float64{}
+	switch rule.Condition {
+	case entities.ALERT_CONDITION_RATE_OF_CHANGE:
+		var err error
+		values, err = e.changes(ctx, rule, readings)
+		if err != nil {
+			return err
+		}
+	case entities.ALERT_CONDITION_ANOMALY:
+		// readings the sensor's anomaly detector did not score are left out
+		for _, v := range readings {
+			if v.AnomalyScore != nil {
+				values[v] = *v.AnomalyScore
+			}
+		}
+	default:
+		for _, v := range readings {
+			values[v] = v.Value
+		}
+	}
+
+	_, err := e.repo.UpdateAlert(ctx, rule.ID, latest.SensorID, latest.DeviceID, func(alert *entities.Alert) ([]*entities.AlertEvent, error) {
+		for _, v := range readings {
+			if alert.LastEvaluatedAt != nil && !v.RecordedAt.After(*alert.LastEvaluatedAt) {
+				continue
+			}
+			value, ok := values[v]
+			if !ok {
+				continue
+			}
+
+			status := alert.Status
+			if rule.Condition == entities.ALERT_CONDITION_NO_DATA {
+				ResolveNoData(alert, value, v.RecordedAt)
+			} else {
+				Evaluate(rule, alert, value, v.RecordedAt)
+			}
+			if alert.Status != status {
+				changes = append(changes, *alert)
+			}
+		}
+		return timeline(changes), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	e.publish(changes)
+
+	return nil
+}
+
+// changes returns the change of every reading from the earliest reading of the rule's window before it.
+// Readings without an earlier reading in the window have no change and are left out. The readings are of one
+// sensor in order of recorded_at, the windows of all of them are loaded at once.
+func (e *Evaluator) changes(ctx context.Context, rule *entities.AlertRule, readings []*entities.SensorReading) (map[*entities.SensorReading]float64, error) {
+	changes := map[*entities.SensorReading]float64{}
+	if len(readings) == 0 {
+		return changes, nil
+	}
+
+	window := rule.WindowDuration()
+	from := readings[0].RecordedAt.Add(-window)
+	to := readings[len(readings)-1].RecordedAt
+
+	earlier := []*entities.SensorReading{}
+	for {
+		page, _, err := e.repo.GetSensorReadingList(ctx, entities.GetSensorReadingListParams{
+			SensorID:           readings[0].SensorID,
+			From:               &from,
+			To:                 &to,
+			ExcludeMaintenance: true,
+			Sort:               "recorded_at",
+			Limit:              WINDOW_PAGE_SIZE,
+			Offset:             len(earlier),
+		})
+		if err != nil {
+			return nil, err
+		}
+		earlier = append(earlier, page...)
+		if len(page) < WINDOW_PAGE_SIZE {
+			break
+		}
+	}
+
+	// windows start later for later readings, so the earliest reading of a window never moves back
+	i := 0
+	for _, v := range readings {
+		start := v.RecordedAt.Add(-window)
+		for i < len(earlier) && earlier[i].RecordedAt.Before(start) {
+			i++
+		}
+		if i == len(earlier) || !earlier[i].RecordedAt.Before(v.RecordedAt) {
+			continue
+		}
+
+		changes[v] = v.Value - earlier[i].Value
+	}
+
+	return changes, nil
+}
+
+// EvaluateNoData fires the alerts of the enabled no data rules for sensors that sent nothing for the
+// rule's for seconds. Sensors of devices in a maintenance window are left alone.
+func (e *Evaluator) EvaluateNoData(ctx context.Context, now time.Time) error {
+	rules, err := e.repo.GetEnabledAlertRules(ctx, entities.ALERT_CONDITION_NO_DATA)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		sensors, err := e.repo.GetAlertRuleSensors(ctx, rule)
+		if err != nil {
+			return err
+		}
+
+		for _, sensor := range sensors {
+			silentSince := sensor.CreatedAt
+			if sensor.LastRecordedAt != nil {
+				silentSince = *sensor.LastRecordedAt
+			}
+			if now.Sub(silentSince) < rule.ForDuration() {
+				continue
+			}
+
+			maintenance, err := e.repo.IsDeviceInMaintenance(ctx, sensor.DeviceID, now)
+			if err != nil {
+				return err
+			}
+			if maintenance {
+				continue
+			}
+
+			changes := []entities.Alert{}
+			_, err = e.repo.UpdateAlert(ctx, rule.ID, sensor.SensorID, sensor.DeviceID, func(alert *entities.Alert) ([]*entities.AlertEvent, error) {
+				status := alert.Status
+				EvaluateNoData(rule, alert, silentSince, now)
+				if alert.Status != status {
+					changes = append(changes, *alert)
+				}
+				return timeline(changes), nil
+			})
+			if err != nil {
+				return err
+			}
+
+			e.publish(changes)
+		}
+	}
+
+	return nil
+}
+
+// timeline returns the timeline entries of the status changes of an alert.
+func timeline(changes []entities.Alert) []*entities.AlertEvent {
+	events := []*entities.AlertEvent{}
+	for _, v := range changes {
+		events = append(events, &entities.AlertEvent{
+			Type:   entities.AlertEventType(v.Status),
+			Actor:  entities.ALERT_ACTOR_SYSTEM,
+			Status: v.Status,
+			Value:  v.Value,
+		})
+	}
+	return events
+}
+
+func (e *Evaluator) publish(alerts []entities.Alert) {
+	for _, v := range alerts {
+		eventType, ok := statusEvents[v.Status]
+		if !ok {
+			continue
+		}
+
+		alert := v
+		e.broker.Publish(events.Event{
+			Type:       eventType,
+			ResourceID: alert.ID,
+			Data:       &alert,
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package alerting
+
+import (
+	"context"
+	"go-api/internal/entities"
+	"go-api/internal/repositories"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeRepository lists the readings it holds and counts the queries, the other methods of the interface are
+// not used by the tests and panic.
+type fakeRepository struct {
+	repositories.IRepository
+
+	readings []*entities.SensorReading
+	queries  int
+}
+
+func (r *fakeRepository) GetSensorReadingList(ctx context.Context, params entities.GetSensorReadingListParams) ([]*entities.SensorReading, int64, error) {
+	r.queries++
+
+	matches := []*entities.SensorReading{}
+	for _, v := range r.readings {
+		if v.SensorID != params.SensorID || (params.ExcludeMaintenance && v.Maintenance) {
+			continue
+		}
+		if v.RecordedAt.Before(*params.From) || !v.RecordedAt.Before(*params.To) {
+			continue
+		}
+		matches = append(matches, v)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].RecordedAt.Before(matches[j].RecordedAt) })
+
+	total := int64(len(matches))
+	if params.Offset >= len(matches) {
+		return nil, total, nil
+	}
+	matches = matches[params.Offset:]
+	if len(matches) > params.Limit {
+		matches = matches[:params.Limit]
+	}
+
+	return matches, total, nil
+}
+
+func TestChanges(t *testing.T) {
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	reading := func(minutes int, value float64, maintenance bool) *entities.SensorReading {
+		return &entities.SensorReading{
+			SensorID:    "sensor-1",
+			Value:       value,
+			Maintenance: maintenance,
+			RecordedAt:  start.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+
+	stored := []*entities.SensorReading{
+		reading(0, 10, false),
+		reading(1, 99, true),
+		reading(5, 12, false),
+		reading(12, 20, false),
+		reading(20, 17, false),
+		reading(40, 30, false),
+	}
+	repo := &fakeRepository{readings: append(stored, &entities.SensorReading{
+		SensorID:   "sensor-2",
+		Value:      100,
+		RecordedAt: start.Add(15 * time.Minute),
+	})}
+
+	// the batch is stored before it is evaluated
+	batch := []*entities.SensorReading{stored[3], stored[4], stored[5]}
+	rule := &entities.AlertRule{Window: 600}
+
+	changes, err := NewEvaluator(repo, nil).changes(context.Background(), rule, batch)
+	if err != nil {
+		t.Fatalf("changes() error = %v", err)
+	}
+
+	if repo.queries != 1 {
+		t.Errorf("changes() ran %d queries, want 1", repo.queries)
+	}
+
+	want := map[*entities.SensorReading]float64{
+		// from the reading at 5m, the one at 0m is out of the window and the one at 1m is under maintenance
+		stored[3]: 8,
+		// from the reading at 12m
+		stored[4]: -3,
+		// the reading at 40m has no earlier reading in its window
+	}
+	if len(changes) != len(want) {
+		t.Errorf("changes() = %d changes, want %d", len(changes), len(want))
+	}
+	for v, change := range want {
+		if got, ok := changes[v]; !ok || got != change {
+			t.Errorf("change at %s = %v (%v), want %v", v.RecordedAt.Format(time.Kitchen), got, ok, change)
+		}
+	}
+}
+
+func TestChangesPages(t *testing.T) {
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	repo := &fakeRepository{}
+	for i := 0; i <= 2*WINDOW_PAGE_SIZE; i++ {
+		repo.readings = append(repo.readings, &entities.SensorReading{
+			SensorID:   "sensor-1",
+			Value:      float64(i),
+			RecordedAt: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	// a window of a second more than the stored readings span, so every reading starts from the first one
+	rule := &entities.AlertRule{Window: 2*WINDOW_PAGE_SIZE + 1}
+	batch := []*entities.SensorReading{repo.readings[WINDOW_PAGE_SIZE+1], repo.readings[2*WINDOW_PAGE_SIZE]}
+
+	changes, err := NewEvaluator(repo, nil).changes(context.Background(), rule, batch)
+	if err != nil {
+		t.Fatalf("changes() error = %v", err)
+	}
+
+	if repo.queries != 3 {
+		t.Errorf("changes() ran %d queries, want 3", repo.queries)
+	}
+	for _, v := range batch {
+		if changes[v] != v.Value {
+			t.Errorf("change at %s = %v, want %v", v.RecordedAt.Format(time.TimeOnly), changes[v], v.Value)
+		}
+	}
+}
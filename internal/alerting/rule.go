@@ -0,0 +1,95 @@
+package alerting
+
+import (
+	"go-api/internal/entities"
+	"time"
+)
+
+// Evaluate moves the alert of a threshold rule on with a reading recorded at the given time.
+//
+//	ok/resolved -> pending  the value crosses the threshold
+//	pending     -> firing   the value is still past the threshold after the rule's for seconds
+//	pending     -> ok       the value is back within the threshold before that
+//	firing      -> resolved the value is back past the clear threshold
+//
+// A rule without a for duration fires on the first reading past the threshold.
+func Evaluate(rule *entities.AlertRule, alert *entities.Alert, value float64, at time.Time) {
+	alert.Value = value
+	alert.LastEvaluatedAt = &at
+
+	breached := rule.Operator.Breached(value, rule.Threshold)
+
+	switch alert.Status {
+	case entities.ALERT_STATUS_FIRING:
+		if rule.Operator.Cleared(value, rule.ClearThreshold) {
+			alert.Status = entities.ALERT_STATUS_RESOLVED
+			alert.ResolvedAt = &at
+			alert.ResolvedBy = entities.ALERT_ACTOR_SYSTEM
+			alert.PendingSince = nil
+		}
+
+	case entities.ALERT_STATUS_PENDING:
+		if !breached {
+			alert.Status = entities.ALERT_STATUS_OK
+			alert.PendingSince = nil
+			return
+		}
+		if alert.PendingSince == nil {
+			alert.PendingSince = &at
+		}
+		if at.Sub(*alert.PendingSince) >= rule.ForDuration() {
+			alert.Status = entities.ALERT_STATUS_FIRING
+			alert.FiredAt = &at
+		}
+
+	default:
+		if !breached {
+			return
+		}
+		reopen(alert)
+		alert.Status = entities.ALERT_STATUS_PENDING
+		alert.PendingSince = &at
+		if rule.ForDuration() == 0 {
+			alert.Status = entities.ALERT_STATUS_FIRING
+			alert.FiredAt = &at
+		}
+	}
+}
+
+// EvaluateNoData moves the alert of a no data rule on at the given time, silentSince being the time of the
+// sensor's latest reading. The alert fires once the sensor has been silent for the rule's for seconds.
+// It resolves with the next reading, see ResolveNoData.
+func EvaluateNoData(rule *entities.AlertRule, alert *entities.Alert, silentSince, now time.Time) {
+	if alert.Status == entities.ALERT_STATUS_FIRING || now.Sub(silentSince) < rule.ForDuration() {
+		return
+	}
+
+	firedAt := silentSince.Add(rule.ForDuration())
+	reopen(alert)
+	alert.Status = entities.ALERT_STATUS_FIRING
+	alert.PendingSince = &silentSince
+	alert.FiredAt = &firedAt
+}
+
+// ResolveNoData resolves the alert of a no data rule with a reading recorded at the given time.
+func ResolveNoData(alert *entities.Alert, value float64, at time.Time) {
+	alert.Value = value
+	alert.LastEvaluatedAt = &at
+
+	if alert.Status == entities.ALERT_STATUS_FIRING {
+		alert.Status = entities.ALERT_STATUS_RESOLVED
+		alert.ResolvedAt = &at
+		alert.ResolvedBy = entities.ALERT_ACTOR_SYSTEM
+		alert.PendingSince = nil
+	}
+}
+
+// reopen clears what belonged to the previous occurrence of an alert, its acknowledgement and resolution.
+// The assignee and a snooze are kept.
+func reopen(alert *entities.Alert) {
+	alert.FiredAt = nil
+	alert.ResolvedAt = nil
+	alert.ResolvedBy = ""
+	alert.AcknowledgedAt = nil
+	alert.AcknowledgedBy = ""
+}
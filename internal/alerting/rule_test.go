@@ -0,0 +1,126 @@
+package alerting
+
+import (
+	"go-api/internal/entities"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	type reading struct {
+		seconds int
+		value   float64
+		status  entities.AlertStatus
+	}
+
+	tests := []struct {
+		name     string
+		rule     *entities.AlertRule
+		readings []reading
+		firedAt  int
+	}{
+		{
+			name: "ok to pending to firing to resolved",
+			rule: &entities.AlertRule{Operator: entities.ALERT_OPERATOR_GT, Threshold: 30, ClearThreshold: 30, For: 60},
+			readings: []reading{
+				{0, 20, entities.ALERT_STATUS_OK},
+				{10, 31, entities.ALERT_STATUS_PENDING},
+				{70, 32, entities.ALERT_STATUS_FIRING},
+				{80, 29, entities.ALERT_STATUS_RESOLVED},
+			},
+			firedAt: 70,
+		},
+		{
+			name: "pending until the for duration has passed",
+			rule: &entities.AlertRule{Operator: entities.ALERT_OPERATOR_GT, Threshold: 30, ClearThreshold: 30, For: 60},
+			readings: []reading{
+				{0, 31, entities.ALERT_STATUS_PENDING},
+				{30, 35, entities.ALERT_STATUS_PENDING},
+				{59, 35, entities.ALERT_STATUS_PENDING},
+				{60, 35, entities.ALERT_STATUS_FIRING},
+			},
+			firedAt: 60,
+		},
+		{
+			name: "back to ok within the for duration",
+			rule: &entities.AlertRule{Operator: entities.ALERT_OPERATOR_GT, Threshold: 30, ClearThreshold: 30, For: 60},
+			readings: []reading{
+				{0, 31, entities.ALERT_STATUS_PENDING},
+				{30, 30, entities.ALERT_STATUS_OK},
+				{40, 31, entities.ALERT_STATUS_PENDING},
+				// the for duration starts again from the new crossing
+				{90, 31, entities.ALERT_STATUS_PENDING},
+				{100, 31, entities.ALERT_STATUS_FIRING},
+			},
+			firedAt: 100,
+		},
+		{
+			name: "firing until past the clear threshold",
+			rule: &entities.AlertRule{Operator: entities.ALERT_OPERATOR_GT, Threshold: 30, ClearThreshold: 25},
+			readings: []reading{
+				{0, 31, entities.ALERT_STATUS_FIRING},
+				{10, 28, entities.ALERT_STATUS_FIRING},
+				{20, 25, entities.ALERT_STATUS_FIRING},
+				{30, 24, entities.ALERT_STATUS_RESOLVED},
+			},
+			firedAt: 0,
+		},
+		{
+			name: "clear threshold of a lower bound",
+			rule: &entities.AlertRule{Operator: entities.ALERT_OPERATOR_LTE, Threshold: 5, ClearThreshold: 8},
+			readings: []reading{
+				{0, 5, entities.ALERT_STATUS_FIRING},
+				{10, 7, entities.ALERT_STATUS_FIRING},
+				{20, 9, entities.ALERT_STATUS_RESOLVED},
+			},
+			firedAt: 0,
+		},
+		{
+			name: "no for duration fires on the first reading",
+			rule: &entities.AlertRule{Operator: entities.ALERT_OPERATOR_GTE, Threshold: 30, ClearThreshold: 30},
+			readings: []reading{
+				{0, 29, entities.ALERT_STATUS_OK},
+				{10, 30, entities.ALERT_STATUS_FIRING},
+			},
+			firedAt: 10,
+		},
+		{
+			name: "resolved fires again",
+			rule: &entities.AlertRule{Operator: entities.ALERT_OPERATOR_GT, Threshold: 30, ClearThreshold: 30},
+			readings: []reading{
+				{0, 31, entities.ALERT_STATUS_FIRING},
+				{10, 29, entities.ALERT_STATUS_RESOLVED},
+				{20, 29, entities.ALERT_STATUS_RESOLVED},
+				{30, 31, entities.ALERT_STATUS_FIRING},
+			},
+			firedAt: 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &entities.Alert{Status: entities.ALERT_STATUS_OK}
+			for _, v := range tt.readings {
+				at := start.Add(time.Duration(v.seconds) * time.Second)
+				Evaluate(tt.rule, alert, v.value, at)
+
+				if alert.Status != v.status {
+					t.Fatalf("Evaluate() at %ds = %v, want %v", v.seconds, alert.Status, v.status)
+				}
+				if alert.LastEvaluatedAt == nil || !alert.LastEvaluatedAt.Equal(at) {
+					t.Errorf("Evaluate() at %ds LastEvaluatedAt = %v, want %v", v.seconds, alert.LastEvaluatedAt, at)
+				}
+			}
+
+			firedAt := start.Add(time.Duration(tt.firedAt) * time.Second)
+			if alert.FiredAt == nil || !alert.FiredAt.Equal(firedAt) {
+				t.Errorf("FiredAt = %v, want %v", alert.FiredAt, firedAt)
+			}
+			if (alert.Status == entities.ALERT_STATUS_RESOLVED) != (alert.ResolvedAt != nil) {
+				t.Errorf("ResolvedAt = %v, want it set only when resolved", alert.ResolvedAt)
+			}
+		})
+	}
+}
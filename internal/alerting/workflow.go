@@ -0,0 +1,92 @@
+package alerting
+
+import (
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"time"
+)
+
+// Acknowledge marks a pending or firing alert as taken care of by user.
+func Acknowledge(alert *entities.Alert, user, note string, at time.Time) (*entities.AlertEvent, error) {
+	if !alert.Active() {
+		return nil, util.NewErrConflict("only pending or firing alerts can be acknowledged")
+	}
+	if alert.AcknowledgedAt != nil {
+		return nil, util.NewErrConflict("alert is already acknowledged")
+	}
+
+	alert.AcknowledgedAt = &at
+	alert.AcknowledgedBy = user
+
+	return event(alert, entities.ALERT_EVENT_ACKNOWLEDGED, user, note), nil
+}
+
+// Assign gives the alert to assignee, an empty assignee unassigns it.
+func Assign(alert *entities.Alert, user, assignee, note string) (*entities.AlertEvent, error) {
+	if alert.Assignee == assignee {
+		return nil, util.NewErrConflict("alert is already assigned to the user")
+	}
+
+	alert.Assignee = assignee
+
+	eventType := entities.ALERT_EVENT_ASSIGNED
+	if assignee == "" {
+		eventType = entities.ALERT_EVENT_UNASSIGNED
+	}
+	return event(alert, eventType, user, note), nil
+}
+
+// Comment adds a note of user to the timeline of the alert.
+func Comment(alert *entities.Alert, user, note string) *entities.AlertEvent {
+	return event(alert, entities.ALERT_EVENT_COMMENTED, user, note)
+}
+
+// Snooze silences the notifications of the alert until the given time, its status keeps following the readings.
+func Snooze(alert *entities.Alert, user, note string, until, at time.Time) (*entities.AlertEvent, error) {
+	if !until.After(at) {
+		return nil, util.NewErrInvalidRequest("snooze must end in the future")
+	}
+
+	alert.SnoozedUntil = &until
+
+	return event(alert, entities.ALERT_EVENT_SNOOZED, user, note), nil
+}
+
+// Unsnooze ends the snooze of the alert.
+func Unsnooze(alert *entities.Alert, user, note string, at time.Time) (*entities.AlertEvent, error) {
+	if !alert.Snoozed(at) {
+		return nil, util.NewErrConflict("alert is not snoozed")
+	}
+
+	alert.SnoozedUntil = nil
+
+	return event(alert, entities.ALERT_EVENT_UNSNOOZED, user, note), nil
+}
+
+// Resolve closes a pending or firing alert by hand. It goes pending again with the next reading
+// that crosses the threshold.
+func Resolve(alert *entities.Alert, user, note string, at time.Time) (*entities.AlertEvent, error) {
+	if !alert.Active() {
+		return nil, util.NewErrConflict("only pending or firing alerts can be resolved")
+	}
+
+	alert.Status = entities.ALERT_STATUS_RESOLVED
+	alert.ResolvedAt = &at
+	alert.ResolvedBy = user
+	alert.PendingSince = nil
+
+	return event(alert, entities.ALERT_EVENT_RESOLVED, user, note), nil
+}
+
+func event(alert *entities.Alert, eventType entities.AlertEventType, user, note string) *entities.AlertEvent {
+	return &entities.AlertEvent{
+		AlertID:      alert.ID,
+		Type:         eventType,
+		Actor:        user,
+		Note:         note,
+		Status:       alert.Status,
+		Value:        alert.Value,
+		Assignee:     alert.Assignee,
+		SnoozedUntil: alert.SnoozedUntil,
+	}
+}
@@ -0,0 +1,117 @@
+package anomaly
+
+import (
+	"context"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/internal/repositories"
+	"log/slog"
+	"sort"
+)
+
+// Detector runs the anomaly detectors of sensors on their readings and publishes the flagged readings.
+type Detector struct {
+	repo   repositories.IRepository
+	broker *events.Broker
+}
+
+func NewDetector(repo repositories.IRepository, broker *events.Broker) *Detector {
+	return &Detector{
+		repo:   repo,
+		broker: broker,
+	}
+}
+
+// DetectReadings scores the readings of sensors with an enabled detector in recorded order, sets their
+// AnomalyScore and stores the flagged ones. Readings recorded during maintenance and readings older than
+// the last scored one are skipped. The readings are already stored, so a failure is logged rather than returned.
+func (d *Detector) DetectReadings(ctx context.Context, readings []*entities.SensorReading) {
+	err := d.detectReadings(ctx, readings)
+	if err != nil {
+		slog.Error(
+			"Failed to DetectReadings",
+			slog.Any("err", err),
+		)
+	}
+}
+
+func (d *Detector) detectReadings(ctx context.Context, readings []*entities.SensorReading) error {
+	bySensor := map[string][]*entities.SensorReading{}
+	sensorIDs := []string{}
+	for _, v := range readings {
+		if v.Maintenance {
+			continue
+		}
+		if _, ok := bySensor[v.SensorID]; !ok {
+			sensorIDs = append(sensorIDs, v.SensorID)
+		}
+		bySensor[v.SensorID] = append(bySensor[v.SensorID], v)
+	}
+	if len(sensorIDs) == 0 {
+		return nil
+	}
+
+	detected, err := d.repo.GetAnomalyDetectorSensorIDs(ctx, sensorIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, sensorID := range detected {
+		sensorReadings := bySensor[sensorID]
+		sort.SliceStable(sensorReadings, func(i, j int) bool {
+			return sensorReadings[i].RecordedAt.Before(sensorReadings[j].RecordedAt)
+		})
+
+		anomalies, err := d.repo.UpdateAnomalyDetector(ctx, sensorID, func(detector *entities.AnomalyDetector) []*entities.SensorAnomaly {
+			return detect(detector, sensorReadings)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, v := range anomalies {
+			d.broker.Publish(events.Event{
+				Type:       events.SENSOR_ANOMALY,
+				ResourceID: v.SensorID,
+				Data:       v,
+			})
+		}
+	}
+
+	return nil
+}
+
+// detect scores the readings of one sensor, in recorded order, and returns the flagged ones.
+func detect(detector *entities.AnomalyDetector, readings []*entities.SensorReading) []*entities.SensorAnomaly {
+	anomalies := []*entities.SensorAnomaly{}
+
+	for _, v := range readings {
+		if detector.LastRecordedAt != nil && !v.RecordedAt.After(*detector.LastRecordedAt) {
+			continue
+		}
+		recordedAt := v.RecordedAt
+		detector.LastRecordedAt = &recordedAt
+
+		score, ok := Score(detector, v.Value, v.RecordedAt)
+		if !ok {
+			continue
+		}
+		v.AnomalyScore = &score.Score
+
+		if score.Anomalous {
+			anomalies = append(anomalies, &entities.SensorAnomaly{
+				ReadingID:  v.ID,
+				SensorID:   v.SensorID,
+				DeviceID:   v.DeviceID,
+				Value:      v.Value,
+				Expected:   score.Expected,
+				Score:      score.Score,
+				ZScore:     score.ZScore,
+				EWMAScore:  score.EWMAScore,
+				RecordedAt: v.RecordedAt,
+			})
+		}
+	}
+
+	return anomalies
+}
@@ -0,0 +1,57 @@
+package anomaly
+
+import (
+	"go-api/internal/entities"
+	"testing"
+	"time"
+)
+
+func TestDetect(t *testing.T) {
+	last := start.Add(10 * time.Minute)
+	detector := &entities.AnomalyDetector{Sensitivity: 3, Alpha: 0.3, Window: 4, LastRecordedAt: &last}
+
+	reading := func(minutes int, value float64) *entities.SensorReading {
+		return &entities.SensorReading{
+			ID:         int64(minutes),
+			SensorID:   "sensor-1",
+			Value:      value,
+			RecordedAt: start.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+	readings := []*entities.SensorReading{
+		// not newer than the last scored reading
+		reading(5, 100),
+		reading(10, 100),
+		// warm-up
+		reading(11, 9),
+		reading(12, 11),
+		reading(13, 9),
+		reading(14, 11),
+		// scored
+		reading(15, 10),
+		reading(16, 50),
+	}
+
+	anomalies := detect(detector, readings)
+
+	if detector.Model.Count != 6 {
+		t.Errorf("Model.Count = %d, want 6 learned readings", detector.Model.Count)
+	}
+	if want := readings[len(readings)-1].RecordedAt; detector.LastRecordedAt == nil || !detector.LastRecordedAt.Equal(want) {
+		t.Errorf("LastRecordedAt = %v, want %v", detector.LastRecordedAt, want)
+	}
+
+	for i, v := range readings {
+		scored := i >= 6
+		if (v.AnomalyScore != nil) != scored {
+			t.Errorf("reading at %dm AnomalyScore = %v, want scored %v", v.ID, v.AnomalyScore, scored)
+		}
+	}
+
+	if len(anomalies) != 1 {
+		t.Fatalf("detect() = %d anomalies, want 1", len(anomalies))
+	}
+	if anomalies[0].ReadingID != 16 || anomalies[0].Value != 50 || anomalies[0].Score != *readings[7].AnomalyScore {
+		t.Errorf("detect() anomaly = %+v, want the reading at 16m", anomalies[0])
+	}
+}
@@ -0,0 +1,106 @@
+package anomaly
+
+import (
+	"go-api/internal/entities"
+	"math"
+	"time"
+)
+
+// MAX_SCORE caps the scores, a value moving off a model without any spread, such as a constant signal, gets it.
+const MAX_SCORE = 100
+
+const hoursPerDay = 24
+
+// Score scores a value recorded at the given time against the detector's model and then learns it.
+// Values are only scored once the model learned a window of them, ok is false before.
+//
+// The model is an additive Holt-Winters without trend: a seasonal detector keeps an offset for every UTC hour
+// and removes it from the value before it is compared to the level. The z-score compares the value to the
+// mean and standard deviation of the last window of values, the EWMA score to the level and the exponentially
+// weighted standard deviation. Flagged values are learned too, so the model follows a lasting change.
+func Score(detector *entities.AnomalyDetector, value float64, at time.Time) (score entities.AnomalyScore, ok bool) {
+	model := &detector.Model
+
+	hour := at.UTC().Hour()
+	offset := 0.0
+	if detector.Seasonal {
+		if len(model.Hours) != hoursPerDay {
+			model.Hours = make([]float64, hoursPerDay)
+		}
+		offset = model.Hours[hour]
+	} else {
+		model.Hours = nil
+	}
+	x := value - offset
+
+	if detector.Window > 0 && len(model.Recent) >= detector.Window {
+		mean, std := meanStd(model.Recent[len(model.Recent)-detector.Window:])
+
+		score.Expected = model.Level + offset
+		score.ZScore = deviation(x, mean, std)
+		score.EWMAScore = deviation(x, model.Level, math.Sqrt(model.Variance))
+		score.Score = math.Max(math.Abs(score.ZScore), math.Abs(score.EWMAScore))
+		score.Anomalous = score.Score >= detector.Sensitivity
+		ok = true
+	}
+
+	learn(detector, x, value, hour)
+
+	return score, ok
+}
+
+func learn(detector *entities.AnomalyDetector, x, value float64, hour int) {
+	model := &detector.Model
+	alpha := detector.Alpha
+
+	if model.Count == 0 {
+		model.Level = x
+	} else {
+		diff := x - model.Level
+		model.Level += alpha * diff
+		model.Variance = (1 - alpha) * (model.Variance + alpha*diff*diff)
+	}
+	model.Count++
+
+	if detector.Seasonal {
+		model.Hours[hour] += alpha * (value - model.Level - model.Hours[hour])
+	}
+
+	model.Recent = append(model.Recent, x)
+	if len(model.Recent) > detector.Window {
+		model.Recent = append([]float64{}, model.Recent[len(model.Recent)-detector.Window:]...)
+	}
+}
+
+func meanStd(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance)
+}
+
+// deviation returns the signed distance of x from mean in standard deviations, within MAX_SCORE.
+func deviation(x, mean, std float64) float64 {
+	diff := x - mean
+	if std < 1e-9 {
+		switch {
+		case math.Abs(diff) < 1e-9:
+			return 0
+		case diff > 0:
+			return MAX_SCORE
+		default:
+			return -MAX_SCORE
+		}
+	}
+
+	return math.Max(-MAX_SCORE, math.Min(MAX_SCORE, diff/std))
+}
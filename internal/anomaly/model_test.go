@@ -0,0 +1,151 @@
+package anomaly
+
+import (
+	"go-api/internal/entities"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// alternating returns base plus and minus one in turn, a signal with a standard deviation of one.
+func alternating(i int, base float64) float64 {
+	if i%2 == 0 {
+		return base - 1
+	}
+	return base + 1
+}
+
+func TestScoreWarmUp(t *testing.T) {
+	detector := &entities.AnomalyDetector{Sensitivity: 3, Alpha: 0.3, Window: 5}
+
+	for i := 0; i < detector.Window; i++ {
+		if _, ok := Score(detector, 10, start.Add(time.Duration(i)*time.Minute)); ok {
+			t.Fatalf("Score() of value %d ok = true, want false before a window of values", i+1)
+		}
+	}
+	if detector.Model.Count != int64(detector.Window) {
+		t.Errorf("Model.Count = %d, want %d", detector.Model.Count, detector.Window)
+	}
+
+	score, ok := Score(detector, 10, start.Add(time.Hour))
+	if !ok {
+		t.Fatalf("Score() after a window of values ok = false, want true")
+	}
+	if score.Score != 0 || score.Anomalous {
+		t.Errorf("Score() of the learned value = %v (anomalous %v), want 0", score.Score, score.Anomalous)
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		signal    func(i int) float64
+		value     float64
+		score     float64
+		anomalous bool
+	}{
+		{
+			name:      "constant signal is capped",
+			signal:    func(i int) float64 { return 10 },
+			value:     10.5,
+			score:     MAX_SCORE,
+			anomalous: true,
+		},
+		{
+			name:      "constant signal below",
+			signal:    func(i int) float64 { return 10 },
+			value:     9,
+			score:     MAX_SCORE,
+			anomalous: true,
+		},
+		{
+			name:      "within the spread",
+			signal:    func(i int) float64 { return alternating(i, 10) },
+			value:     11,
+			anomalous: false,
+		},
+		{
+			name:      "spike above sensitivity",
+			signal:    func(i int) float64 { return alternating(i, 10) },
+			value:     20,
+			anomalous: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := &entities.AnomalyDetector{Sensitivity: 3, Alpha: 0.3, Window: 20}
+			for i := 0; i < 100; i++ {
+				Score(detector, tt.signal(i), start.Add(time.Duration(i)*time.Minute))
+			}
+
+			score, ok := Score(detector, tt.value, start.Add(2*time.Hour))
+			if !ok {
+				t.Fatalf("Score() ok = false, want true")
+			}
+			if score.Anomalous != tt.anomalous {
+				t.Errorf("Score() = %v, anomalous %v, want %v", score.Score, score.Anomalous, tt.anomalous)
+			}
+			if tt.score != 0 && score.Score != tt.score {
+				t.Errorf("Score() = %v, want %v", score.Score, tt.score)
+			}
+			if score.Score > MAX_SCORE {
+				t.Errorf("Score() = %v, want at most %v", score.Score, MAX_SCORE)
+			}
+		})
+	}
+}
+
+func TestScoreSeasonal(t *testing.T) {
+	// ten degrees more at noon every day, with a spread of one around it that moves from hour to hour and day to day
+	signal := func(i int, at time.Time) float64 {
+		if at.Hour() == 12 {
+			return alternating(i+i/hoursPerDay, 30)
+		}
+		return alternating(i+i/hoursPerDay, 20)
+	}
+
+	trained := func(seasonal bool) *entities.AnomalyDetector {
+		detector := &entities.AnomalyDetector{Sensitivity: 3, Alpha: 0.3, Window: 48, Seasonal: seasonal}
+		for i := 0; i < 30*hoursPerDay; i++ {
+			at := start.Add(time.Duration(i) * time.Hour)
+			Score(detector, signal(i, at), at)
+		}
+		return detector
+	}
+
+	noon := start.Add(40*24*time.Hour + 12*time.Hour)
+	night := start.Add(40*24*time.Hour + 3*time.Hour)
+
+	tests := []struct {
+		name      string
+		seasonal  bool
+		value     float64
+		at        time.Time
+		anomalous bool
+	}{
+		{name: "usual noon value", seasonal: true, value: 30, at: noon, anomalous: false},
+		{name: "noon value at night", seasonal: true, value: 30, at: night, anomalous: true},
+		{name: "usual night value", seasonal: true, value: 20, at: night, anomalous: false},
+		{name: "night value at noon", seasonal: true, value: 20, at: noon, anomalous: true},
+		{name: "usual noon value without seasonality", seasonal: false, value: 30, at: noon, anomalous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := trained(tt.seasonal)
+			if tt.seasonal && len(detector.Model.Hours) != hoursPerDay {
+				t.Fatalf("Model.Hours = %d offsets, want %d", len(detector.Model.Hours), hoursPerDay)
+			}
+
+			score, ok := Score(detector, tt.value, tt.at)
+			if !ok {
+				t.Fatalf("Score() ok = false, want true")
+			}
+			if score.Anomalous != tt.anomalous {
+				t.Errorf("Score() = %v (expected %v), anomalous %v, want %v", score.Score, score.Expected, score.Anomalous, tt.anomalous)
+			}
+		})
+	}
+}
@@ -0,0 +1,843 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-api/internal/alerting"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/condition"
+	"go-api/pkg/util"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// MAX_DRY_RUN_RANGE bounds the time range a dry run replays.
+const MAX_DRY_RUN_RANGE = 31 * 24 * time.Hour
+
+// CreateAlertRule create alert rule handler
+// @Summary			Create Alert Rule.
+// @Description		Add a rule on a sensor or on every sensor of a type, evaluated as readings arrive.
+// @Description		threshold (default): an alert goes pending when a reading crosses the threshold and fires when the condition held for `for` seconds.
+// @Description		A firing alert resolves when a reading is back past clear_threshold (default the threshold).
+// @Description		rate_of_change: the same with the change of the value from the earliest reading of the last `window` seconds, e.g. `<` -10 for a drop of more than 10.
+// @Description		no_data: fires when the sensor sent no reading for `for` seconds, checked every minute outside maintenance windows, and resolves with the next reading.
+// @Description		anomaly: the same with the anomaly score of the readings, fires when the score is at or above the threshold. The sensor needs an anomaly detector.
+// @Description		composite: `expression` combines sensors by name, `type:x` or `{id}`, e.g. `soil_moisture < 20 AND type:temperature > 30 for 15m on the same device`.
+// @Description		It is evaluated every minute with the latest readings, per device with `on the same device`, and has no sensor_id or sensor_type.
+// @Description		notify_emails receive the firing and resolved alerts of the rule by email when SMTP is configured.
+// @Description		escalation_policy_id escalates the firing alerts of the rule with an escalation policy until they are acknowledged.
+// @Tags			Alert Rules
+// @Accept			json
+// @Produce			json
+// @Param 			json		body		entities.CreateUpdateAlertRulePayload	true	"Alert rule data"
+// @Success			201			{object}	util.Response{data=entities.AlertRule}
+// @Failure			400			{object}	util.Response
+// @Failure			404			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/alert-rules [post]
+func (h *Handler) CreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateAlertRulePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateAlertRulePayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	if body.EscalationPolicyID != nil {
+		_, err = h.repo.GetEscalationPolicy(ctx, *body.EscalationPolicyID)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+	}
+
+	ruleID, err := h.repo.CreateAlertRule(ctx, alertRule(body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetAlertRule(ctx, ruleID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateAlertRule update alert rule handler
+// @Summary			Update Alert Rule.
+// @Description		Update an Alert Rule. Its alerts keep their state and follow the new rule from the next reading on.
+// @Tags			Alert Rules
+// @Accept			json
+// @Param 			rule_id		path	string									true	"Alert Rule ID"
+// @Param 			json		body	entities.CreateUpdateAlertRulePayload	true	"Alert rule data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.AlertRule}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/alert-rules/{rule_id} [put]
+func (h *Handler) UpdateAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	ruleID := chi.URLParam(r, "rule_id")
+	if ruleID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("alert rule not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateAlertRulePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateAlertRulePayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetAlertRule(ctx, ruleID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	if body.EscalationPolicyID != nil {
+		_, err = h.repo.GetEscalationPolicy(ctx, *body.EscalationPolicyID)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+	}
+
+	err = h.repo.UpdateAlertRule(ctx, ruleID, alertRule(body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetAlertRule(ctx, ruleID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteAlertRule delete alert rule handler
+// @Summary			Delete Alert Rule.
+// @Description		Delete an Alert Rule with its alerts.
+// @Tags			Alert Rules
+// @Param			rule_id			path			string	 true	"Alert Rule ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/alert-rules/{rule_id} [delete]
+func (h *Handler) DeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	ruleID := chi.URLParam(r, "rule_id")
+	if ruleID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("alert rule not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetAlertRule(ctx, ruleID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.DeleteAlertRule(ctx, ruleID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetAlertRule get alert rule handler
+// @Summary			Get Alert Rule by ID.
+// @Description		Get Alert Rule by ID.
+// @Tags			Alert Rules
+// @Param			rule_id			path			string	 true	"Alert Rule ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.AlertRule}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/alert-rules/{rule_id} [get]
+func (h *Handler) GetAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	ruleID := chi.URLParam(r, "rule_id")
+	if ruleID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("alert rule not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetAlertRule(ctx, ruleID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DryRunAlertRule dry run alert rule handler
+// @Summary			Dry Run Alert Rule.
+// @Description		Replay the readings recorded from `from` until `to` (default now, at most 31 days later) through a composite condition
+// @Description		and return when it would have fired, per device for a condition on the same device. Nothing is stored or notified.
+// @Tags			Alert Rules
+// @Accept			json
+// @Produce			json
+// @Param 			json		body		entities.AlertRuleDryRunPayload	true	"Dry run data"
+// @Success			200			{object}	util.Response{data=entities.AlertRuleDryRun}
+// @Failure			400			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/alert-rules/dry-run [post]
+func (h *Handler) DryRunAlertRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.AlertRuleDryRunPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(util.ParseValidatorErr(err)))
+		return
+	}
+
+	c, errs := validateCondition(body.Expression)
+	from := body.From.UTC()
+	to := time.Now().UTC()
+	if body.To != nil {
+		to = body.To.UTC()
+	}
+	if !to.After(from) {
+		errs = append(errs, "To must be after From")
+	}
+	if to.Sub(from) > MAX_DRY_RUN_RANGE {
+		errs = append(errs, "To must be at most 31 days after From")
+	}
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	result, err := h.alerts.DryRun(ctx, c, from, to)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetAlertRuleList get alert rule list handler
+// @Summary			Get list of Alert Rules.
+// @Description		Get list of Alert Rules.
+// @Tags			Alert Rules
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/severity/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching rules by name or description"
+// @Param			condition		query			string	 false	"Rules of the condition (threshold/no_data/rate_of_change/anomaly/composite)"	example(no_data)
+// @Param			sensor_id		query			string	 false	"Rules of the sensor"
+// @Param			sensor_type		query			string	 false	"Rules of the sensor type"									example(temperature)
+// @Param			severity		query			string	 false	"Rules of the severity (info/warning/critical)"			example(critical)
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.AlertRule}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/alert-rules [get]
+func (h *Handler) GetAlertRuleList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetAlertRuleListParams{
+		Condition:  q.Get("condition"),
+		SensorID:   q.Get("sensor_id"),
+		SensorType: q.Get("sensor_type"),
+		Severity:   q.Get("severity"),
+		Search:     q.Get("search"),
+		Sort:       q.Get("sort"),
+		Limit:      count,
+		Offset:     (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetAlertRuleList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetAlert get alert handler
+// @Summary			Get Alert by ID.
+// @Description		Get the state of an alert rule for one sensor.
+// @Tags			Alerts
+// @Param			alert_id		path			string	 true	"Alert ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.Alert}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/alerts/{alert_id} [get]
+func (h *Handler) GetAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	alertID := chi.URLParam(r, "alert_id")
+	if alertID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("alert not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetAlert(ctx, alertID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetAlertList get alert list handler
+// @Summary			Get list of Alerts.
+// @Description		Get the alert state of rule and sensor pairs, the active (pending and firing) ones by default.
+// @Tags			Alerts
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: status/fired_at/pending_since/last_evaluated_at/acknowledged_at/snoozed_until/created_at/updated_at). For desc order, use prefix '-'"	example(-fired_at)
+// @Param			status			query			string	 false	"Comma separated statuses (ok/pending/firing/resolved, default pending,firing)"	example(firing)
+// @Param			severity		query			string	 false	"Alerts of rules with the severity (info/warning/critical)"	example(critical)
+// @Param			rule_id			query			string	 false	"Alerts of the rule"
+// @Param			sensor_id		query			string	 false	"Alerts of the sensor"
+// @Param			device_id		query			string	 false	"Alerts of the device"
+// @Param			assignee		query			string	 false	"Alerts assigned to the user"
+// @Param			acknowledged	query			bool	 false	"Acknowledged (true) or unacknowledged (false) alerts"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.Alert}
+// @Failure			400				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/alerts [get]
+func (h *Handler) GetAlertList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	statuses, errs := alertStatuses(q.Get("status"))
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	params := entities.GetAlertListParams{
+		Statuses: statuses,
+		Severity: q.Get("severity"),
+		RuleID:   q.Get("rule_id"),
+		SensorID: q.Get("sensor_id"),
+		DeviceID: q.Get("device_id"),
+		Assignee: q.Get("assignee"),
+		Sort:     q.Get("sort"),
+		Limit:    count,
+		Offset:   (page - 1) * count,
+	}
+	if acknowledged := q.Get("acknowledged"); acknowledged != "" {
+		isAcknowledged := acknowledged == "true"
+		params.Acknowledged = &isAcknowledged
+	}
+
+	results, total, err := h.repo.GetAlertList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// AcknowledgeAlert acknowledge alert handler
+// @Summary			Acknowledge Alert.
+// @Description		Mark a pending or firing alert as taken care of. The acknowledgement is cleared when the alert goes pending again.
+// @Tags			Alerts
+// @Accept			json
+// @Produce			json
+// @Param			alert_id		path		string						true	"Alert ID"
+// @Param 			json			body		entities.AlertActionPayload	true	"Acknowledgement"
+// @Success			200				{object}	util.Response{data=entities.Alert}
+// @Failure			400				{object}	util.Response
+// @Failure			404				{object}	util.Response
+// @Failure			409				{object}	util.Response
+// @Failure			500				{object}	util.Response
+// @Router	/v1/alerts/{alert_id}/ack [post]
+func (h *Handler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	var body entities.AlertActionPayload
+	h.updateAlert(w, r, &body, events.ALERT_ACKNOWLEDGED, func(alert *entities.Alert) (*entities.AlertEvent, error) {
+		return alerting.Acknowledge(alert, body.User, body.Note, time.Now().UTC())
+	})
+}
+
+// AssignAlert assign alert handler
+// @Summary			Assign Alert.
+// @Description		Assign an alert to a user, an empty assignee unassigns it.
+// @Tags			Alerts
+// @Accept			json
+// @Produce			json
+// @Param			alert_id		path		string						true	"Alert ID"
+// @Param 			json			body		entities.AssignAlertPayload	true	"Assignment"
+// @Success			200				{object}	util.Response{data=entities.Alert}
+// @Failure			400				{object}	util.Response
+// @Failure			404				{object}	util.Response
+// @Failure			409				{object}	util.Response
+// @Failure			500				{object}	util.Response
+// @Router	/v1/alerts/{alert_id}/assign [post]
+func (h *Handler) AssignAlert(w http.ResponseWriter, r *http.Request) {
+	var body entities.AssignAlertPayload
+	h.updateAlert(w, r, &body, events.ALERT_ASSIGNED, func(alert *entities.Alert) (*entities.AlertEvent, error) {
+		return alerting.Assign(alert, body.User, body.Assignee, body.Note)
+	})
+}
+
+// CommentAlert comment alert handler
+// @Summary			Comment on Alert.
+// @Description		Add a comment to the timeline of an alert.
+// @Tags			Alerts
+// @Accept			json
+// @Produce			json
+// @Param			alert_id		path		string							true	"Alert ID"
+// @Param 			json			body		entities.AlertCommentPayload	true	"Comment"
+// @Success			200				{object}	util.Response{data=entities.Alert}
+// @Failure			400				{object}	util.Response
+// @Failure			404				{object}	util.Response
+// @Failure			500				{object}	util.Response
+// @Router	/v1/alerts/{alert_id}/comments [post]
+func (h *Handler) CommentAlert(w http.ResponseWriter, r *http.Request) {
+	var body entities.AlertCommentPayload
+	h.updateAlert(w, r, &body, events.ALERT_COMMENTED, func(alert *entities.Alert) (*entities.AlertEvent, error) {
+		return alerting.Comment(alert, body.User, body.Note), nil
+	})
+}
+
+// SnoozeAlert snooze alert handler
+// @Summary			Snooze Alert.
+// @Description		Silence the notifications of an alert until a time. Its status keeps following the readings.
+// @Tags			Alerts
+// @Accept			json
+// @Produce			json
+// @Param			alert_id		path		string						true	"Alert ID"
+// @Param 			json			body		entities.SnoozeAlertPayload	true	"Snooze"
+// @Success			200				{object}	util.Response{data=entities.Alert}
+// @Failure			400				{object}	util.Response
+// @Failure			404				{object}	util.Response
+// @Failure			500				{object}	util.Response
+// @Router	/v1/alerts/{alert_id}/snooze [post]
+func (h *Handler) SnoozeAlert(w http.ResponseWriter, r *http.Request) {
+	var body entities.SnoozeAlertPayload
+	h.updateAlert(w, r, &body, events.ALERT_SNOOZED, func(alert *entities.Alert) (*entities.AlertEvent, error) {
+		return alerting.Snooze(alert, body.User, body.Note, body.Until, time.Now().UTC())
+	})
+}
+
+// UnsnoozeAlert unsnooze alert handler
+// @Summary			Unsnooze Alert.
+// @Description		End the snooze of an alert.
+// @Tags			Alerts
+// @Accept			json
+// @Produce			json
+// @Param			alert_id		path		string						true	"Alert ID"
+// @Param 			json			body		entities.AlertActionPayload	true	"Unsnooze"
+// @Success			200				{object}	util.Response{data=entities.Alert}
+// @Failure			400				{object}	util.Response
+// @Failure			404				{object}	util.Response
+// @Failure			409				{object}	util.Response
+// @Failure			500				{object}	util.Response
+// @Router	/v1/alerts/{alert_id}/unsnooze [post]
+func (h *Handler) UnsnoozeAlert(w http.ResponseWriter, r *http.Request) {
+	var body entities.AlertActionPayload
+	h.updateAlert(w, r, &body, events.ALERT_SNOOZED, func(alert *entities.Alert) (*entities.AlertEvent, error) {
+		return alerting.Unsnooze(alert, body.User, body.Note, time.Now().UTC())
+	})
+}
+
+// ResolveAlert resolve alert handler
+// @Summary			Resolve Alert.
+// @Description		Resolve a pending or firing alert by hand with a note. It goes pending again with the next reading crossing the threshold.
+// @Tags			Alerts
+// @Accept			json
+// @Produce			json
+// @Param			alert_id		path		string						true	"Alert ID"
+// @Param 			json			body		entities.AlertActionPayload	true	"Resolution"
+// @Success			200				{object}	util.Response{data=entities.Alert}
+// @Failure			400				{object}	util.Response
+// @Failure			404				{object}	util.Response
+// @Failure			409				{object}	util.Response
+// @Failure			500				{object}	util.Response
+// @Router	/v1/alerts/{alert_id}/resolve [post]
+func (h *Handler) ResolveAlert(w http.ResponseWriter, r *http.Request) {
+	var body entities.AlertActionPayload
+	h.updateAlert(w, r, &body, events.ALERT_RESOLVED, func(alert *entities.Alert) (*entities.AlertEvent, error) {
+		return alerting.Resolve(alert, body.User, body.Note, time.Now().UTC())
+	})
+}
+
+// updateAlert decodes and validates the body of an alert action, applies the action and publishes the
+// changed alert.
+func (h *Handler) updateAlert(w http.ResponseWriter, r *http.Request, body any, eventType string, action func(alert *entities.Alert) (*entities.AlertEvent, error)) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	alertID := chi.URLParam(r, "alert_id")
+	if alertID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("alert not found", nil))
+		return
+	}
+
+	err := json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	result, err := h.repo.UpdateAlertByID(ctx, alertID, func(alert *entities.Alert) ([]*entities.AlertEvent, error) {
+		event, err := action(alert)
+		if err != nil {
+			return nil, err
+		}
+		return []*entities.AlertEvent{event}, nil
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	h.broker.Publish(events.Event{
+		Type:       eventType,
+		ResourceID: result.ID,
+		Data:       result,
+	})
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetAlertTimeline get alert timeline handler
+// @Summary			Get the timeline of an Alert.
+// @Description		Get the status changes and user actions of an alert. Entries are never changed or removed.
+// @Tags			Alerts
+// @Param			alert_id		path			string	 true	"Alert ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: type/created_at). For desc order, use prefix '-'"	example(created_at)
+// @Param			type			query			string	 false	"Entries of the type (ok/pending/firing/resolved/acknowledged/assigned/unassigned/commented/snoozed/unsnoozed)"	example(commented)
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.AlertEvent}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/alerts/{alert_id}/timeline [get]
+func (h *Handler) GetAlertTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	alertID := chi.URLParam(r, "alert_id")
+	if alertID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("alert not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetAlert(ctx, alertID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetAlertEventListParams{
+		AlertID: alertID,
+		Type:    q.Get("type"),
+		Sort:    q.Get("sort"),
+		Limit:   count,
+		Offset:  (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetAlertEventList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+func (h *Handler) validateAlertRulePayload(body entities.CreateUpdateAlertRulePayload) []string {
+	err := h.validate.Struct(body)
+	if err != nil {
+		return util.ParseValidatorErr(err)
+	}
+
+	errs := []string{}
+	if body.Condition == entities.ALERT_CONDITION_COMPOSITE {
+		if body.SensorID != nil || body.SensorType != nil {
+			errs = append(errs, "SensorID and SensorType are not used by a composite rule")
+		}
+		if body.Expression == "" {
+			errs = append(errs, "Expression is required for a composite rule")
+		} else {
+			_, conditionErrs := validateCondition(body.Expression)
+			errs = append(errs, conditionErrs...)
+		}
+		return errs
+	}
+	if body.SensorID == nil && body.SensorType == nil {
+		errs = append(errs, "SensorID or SensorType is required")
+	}
+	if body.Expression != "" {
+		errs = append(errs, "Expression is only used by a composite rule")
+	}
+
+	switch body.Condition {
+	case "", entities.ALERT_CONDITION_THRESHOLD:
+		if body.Operator == "" {
+			errs = append(errs, "Operator is required for a threshold rule")
+		}
+	case entities.ALERT_CONDITION_RATE_OF_CHANGE:
+		if body.Operator == "" {
+			errs = append(errs, "Operator is required for a rate_of_change rule")
+		}
+		if body.Window == 0 {
+			errs = append(errs, "Window is required for a rate_of_change rule")
+		}
+	case entities.ALERT_CONDITION_NO_DATA:
+		if body.For == 0 {
+			errs = append(errs, "For is required for a no_data rule")
+		}
+	case entities.ALERT_CONDITION_ANOMALY:
+		if body.Threshold <= 0 {
+			errs = append(errs, "Threshold must be above 0 for an anomaly rule")
+		}
+		if body.ClearThreshold != nil && *body.ClearThreshold > body.Threshold {
+			errs = append(errs, "ClearThreshold must not be above the threshold for an anomaly rule")
+		}
+	}
+
+	if body.ClearThreshold != nil && body.Operator != "" && body.Condition != entities.ALERT_CONDITION_ANOMALY {
+		if body.Operator.Upper() && *body.ClearThreshold > body.Threshold {
+			errs = append(errs, fmt.Sprintf("ClearThreshold must not be above the threshold for %s", body.Operator))
+		}
+		if !body.Operator.Upper() && *body.ClearThreshold < body.Threshold {
+			errs = append(errs, fmt.Sprintf("ClearThreshold must not be below the threshold for %s", body.Operator))
+		}
+	}
+
+	return errs
+}
+
+// validateCondition parses the expression of a composite condition and reports syntax errors, unknown
+// sensor types and a for duration above a day.
+func validateCondition(expression string) (*condition.Condition, []string) {
+	c, err := condition.Parse(expression)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("expression: %s", err.Error())}
+	}
+
+	errs := []string{}
+	for _, ref := range c.Refs() {
+		if ref.Kind != condition.REF_SENSOR_TYPE {
+			continue
+		}
+		known := false
+		for _, v := range entities.SensorTypesName {
+			if v["slug"] == ref.Value {
+				known = true
+			}
+		}
+		if !known {
+			errs = append(errs, fmt.Sprintf("expression references unknown sensor type %s", ref.Value))
+		}
+	}
+	if c.For > 24*time.Hour {
+		errs = append(errs, "expression for duration must not be above 24h")
+	}
+
+	return c, errs
+}
+
+// alertRule fills in the defaults of a rule, a threshold condition, the clear threshold is the threshold
+// and rules are enabled. Anomaly rules fire on scores at or above the threshold. Composite rules fire on
+// the value 1 of a holding condition, after the for duration of their expression.
+func alertRule(body entities.CreateUpdateAlertRulePayload) entities.AlertRule {
+	rule := entities.AlertRule{
+		Name:               body.Name,
+		Description:        body.Description,
+		SensorID:           body.SensorID,
+		SensorType:         body.SensorType,
+		Condition:          body.Condition,
+		Operator:           body.Operator,
+		Threshold:          body.Threshold,
+		ClearThreshold:     body.Threshold,
+		For:                body.For,
+		Window:             body.Window,
+		Severity:           body.Severity,
+		NotifyEmails:       body.NotifyEmails,
+		Expression:         body.Expression,
+		EscalationPolicyID: body.EscalationPolicyID,
+		Enabled:            true,
+	}
+
+	if rule.Condition == "" {
+		rule.Condition = entities.ALERT_CONDITION_THRESHOLD
+	}
+	if rule.Condition == entities.ALERT_CONDITION_ANOMALY {
+		rule.Operator = entities.ALERT_OPERATOR_GTE
+	}
+	if rule.Condition == entities.ALERT_CONDITION_COMPOSITE {
+		c, err := condition.Parse(body.Expression)
+		if err == nil {
+			rule.For = int(c.For.Seconds())
+		}
+		rule.Operator = entities.ALERT_OPERATOR_GTE
+		rule.Threshold = 1
+		rule.ClearThreshold = 1
+		rule.Window = 0
+		body.ClearThreshold = nil
+	}
+	if rule.NotifyEmails == nil {
+		rule.NotifyEmails = []string{}
+	}
+	if body.ClearThreshold != nil {
+		rule.ClearThreshold = *body.ClearThreshold
+	}
+	if body.Enabled != nil {
+		rule.Enabled = *body.Enabled
+	}
+
+	return rule
+}
+
+// alertStatuses parses a comma separated status filter, the active statuses when it is empty.
+func alertStatuses(statusStr string) ([]entities.AlertStatus, []string) {
+	if statusStr == "" {
+		return []entities.AlertStatus{entities.ALERT_STATUS_PENDING, entities.ALERT_STATUS_FIRING}, nil
+	}
+
+	statuses := []entities.AlertStatus{}
+	errs := []string{}
+	for _, v := range strings.Split(statusStr, ",") {
+		status := entities.AlertStatus(strings.TrimSpace(v))
+		switch status {
+		case entities.ALERT_STATUS_OK, entities.ALERT_STATUS_PENDING, entities.ALERT_STATUS_FIRING, entities.ALERT_STATUS_RESOLVED:
+			statuses = append(statuses, status)
+		default:
+			errs = append(errs, fmt.Sprintf("status must be one of ok, pending, firing, resolved: %s", status))
+		}
+	}
+
+	return statuses, errs
+}
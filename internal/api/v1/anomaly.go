@@ -0,0 +1,321 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// PutAnomalyDetector put anomaly detector handler
+// @Summary			Put Sensor Anomaly Detector.
+// @Description		Create or update the anomaly detector of a sensor. Every reading is scored by its distance, in standard deviations,
+// @Description		from the rolling mean of the last `window` readings (z-score) and from their exponentially weighted moving average (EWMA).
+// @Description		A reading is flagged and stored as an anomaly when the larger score reaches `sensitivity` (default 3).
+// @Description		`alpha` (default 0.1) is the weight of a new reading in the EWMA, `window` (default 60) the number of readings of the z-score.
+// @Description		Readings are scored once `window` readings were learned. A `seasonal` detector learns the offset of every UTC hour.
+// @Description		The learned model is kept unless `reset` is set or `seasonal` changes.
+// @Tags			Sensor Anomalies
+// @Accept			json
+// @Produce			json
+// @Param 			sensor_id	path		string									true	"Sensor ID"
+// @Param 			json		body		entities.PutAnomalyDetectorPayload	true	"Anomaly detector"
+// @Success			200			{object}	util.Response{data=entities.AnomalyDetector}
+// @Failure			400			{object}	util.Response
+// @Failure			404			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/sensors/{sensor_id}/anomaly-detector [put]
+func (h *Handler) PutAnomalyDetector(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	var body entities.PutAnomalyDetectorPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.PutAnomalyDetector(ctx, anomalyDetector(sensorID, body), body.Reset)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetAnomalyDetector(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteAnomalyDetector delete anomaly detector handler
+// @Summary			Delete Sensor Anomaly Detector.
+// @Description		Stop anomaly detection on a sensor and drop the learned model, the stored anomalies are kept.
+// @Tags			Sensor Anomalies
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/anomaly-detector [delete]
+func (h *Handler) DeleteAnomalyDetector(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("anomaly detector not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetAnomalyDetector(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.DeleteAnomalyDetector(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetAnomalyDetector get anomaly detector handler
+// @Summary			Get Sensor Anomaly Detector.
+// @Description		Get the anomaly detector of a sensor with its learned model.
+// @Tags			Sensor Anomalies
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.AnomalyDetector}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/anomaly-detector [get]
+func (h *Handler) GetAnomalyDetector(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("anomaly detector not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetAnomalyDetector(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetSensorAnomalyList get sensor anomaly list handler
+// @Summary			Get list of Anomalies of a Sensor.
+// @Description		Get the readings of a sensor flagged by its anomaly detector, newest first by default.
+// @Tags			Sensor Anomalies
+// @Produce			json
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: recorded_at/score). For desc order, use prefix '-'"	example(-score)
+// @Param			from			query			string	 false	"Readings recorded at or after (RFC3339)"					example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Readings recorded before (RFC3339)"						example(2024-03-02T00:00:00Z)
+// @Param			min_score		query			number	 false	"Anomalies with a score at or above"						example(4)
+// @Success			200 			{object}		util.Response{data=[]entities.SensorAnomaly}
+// @Failure			400				{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/anomalies [get]
+func (h *Handler) GetSensorAnomalyList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params, errs := sensorAnomalyListParams(q)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+	params.SensorID = sensorID
+	params.Limit = count
+	params.Offset = (page - 1) * count
+
+	_, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	results, total, err := h.repo.GetSensorAnomalyList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetAnomalyList get anomaly list handler
+// @Summary			Get list of Anomalies.
+// @Description		Get the readings flagged by the anomaly detectors of every sensor, newest first by default.
+// @Tags			Sensor Anomalies
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: recorded_at/score). For desc order, use prefix '-'"	example(-score)
+// @Param			sensor_id		query			string	 false	"Anomalies of the sensor"
+// @Param			device_id		query			string	 false	"Anomalies of readings recorded on the device"
+// @Param			from			query			string	 false	"Readings recorded at or after (RFC3339)"					example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Readings recorded before (RFC3339)"						example(2024-03-02T00:00:00Z)
+// @Param			min_score		query			number	 false	"Anomalies with a score at or above"						example(4)
+// @Success			200 			{object}		util.Response{data=[]entities.SensorAnomaly}
+// @Failure			400				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/anomalies [get]
+func (h *Handler) GetAnomalyList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params, errs := sensorAnomalyListParams(q)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+	params.SensorID = q.Get("sensor_id")
+	params.DeviceID = q.Get("device_id")
+	params.Limit = count
+	params.Offset = (page - 1) * count
+
+	results, total, err := h.repo.GetSensorAnomalyList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// sensorAnomalyListParams parses the sort, from, to and min_score query params of the anomaly lists.
+func sensorAnomalyListParams(q url.Values) (entities.GetSensorAnomalyListParams, []string) {
+	params := entities.GetSensorAnomalyListParams{
+		Sort: q.Get("sort"),
+	}
+
+	from, to, errs := parseTimeRange(q.Get("from"), q.Get("to"))
+	params.From = from
+	params.To = to
+
+	if v := q.Get("min_score"); v != "" {
+		minScore, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("min_score is not a number: %s", v))
+		} else {
+			params.MinScore = &minScore
+		}
+	}
+
+	return params, errs
+}
+
+// anomalyDetector fills in the defaults of a detector, which is enabled with a sensitivity of 3,
+// an alpha of 0.1 and a window of 60 readings.
+func anomalyDetector(sensorID string, body entities.PutAnomalyDetectorPayload) entities.AnomalyDetector {
+	detector := entities.AnomalyDetector{
+		SensorID:    sensorID,
+		Enabled:     true,
+		Sensitivity: entities.ANOMALY_DEFAULT_SENSITIVITY,
+		Alpha:       entities.ANOMALY_DEFAULT_ALPHA,
+		Window:      entities.ANOMALY_DEFAULT_WINDOW,
+		Seasonal:    body.Seasonal,
+	}
+
+	if body.Enabled != nil {
+		detector.Enabled = *body.Enabled
+	}
+	if body.Sensitivity != nil {
+		detector.Sensitivity = *body.Sensitivity
+	}
+	if body.Alpha != nil {
+		detector.Alpha = *body.Alpha
+	}
+	if body.Window != nil {
+		detector.Window = *body.Window
+	}
+
+	return detector
+}
@@ -1,21 +1,49 @@
 package v1
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-api/internal/alerting"
+	"go-api/internal/anomaly"
+	"go-api/internal/entities"
+	"go-api/internal/events"
 	"go-api/internal/repositories"
+	"go-api/internal/webhooks"
+	"go-api/pkg/util"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 )
 
+// MAX_LONG_POLL_WAIT keeps long-poll requests below the router timeout.
+const MAX_LONG_POLL_WAIT = 50 * time.Second
+
+const CONTENT_TYPE_GEO_JSON = "application/geo+json"
+
 type Handler struct {
-	repo     repositories.IRepository
-	validate *validator.Validate
+	repo      repositories.IRepository
+	validate  *validator.Validate
+	broker    *events.Broker
+	alerts    *alerting.Evaluator
+	anomalies *anomaly.Detector
+	webhooks  *webhooks.Dispatcher
 }
 
-func NewHandler(validate *validator.Validate, repo repositories.IRepository) *Handler {
+func NewHandler(validate *validator.Validate, repo repositories.IRepository, broker *events.Broker, dispatcher *webhooks.Dispatcher) *Handler {
 	return &Handler{
-		repo:     repo,
-		validate: validate,
+		repo:      repo,
+		validate:  validate,
+		broker:    broker,
+		alerts:    alerting.NewEvaluator(repo, broker),
+		anomalies: anomaly.NewDetector(repo, broker),
+		webhooks:  dispatcher,
 	}
 }
 
@@ -28,6 +56,22 @@ func (h *Handler) Routes() *chi.Mux {
 		r.Delete("/{device_id}", h.DeleteDevice)
 		r.Get("/", h.GetDeviceList)
 		r.Get("/{device_id}", h.GetDevice)
+		r.Post("/{device_id}/clone", h.CloneDevice)
+		r.Post("/{device_id}/restore", h.RestoreDevice)
+		r.Get("/{device_id}/audit", h.GetDeviceAuditList)
+		r.Get("/{device_id}/maintenance-windows", h.GetDeviceMaintenanceWindows)
+		r.Get("/{device_id}/readings", h.GetDeviceReadingList)
+
+		r.Get("/{device_id}/shadow", h.GetDeviceShadow)
+		r.Get("/{device_id}/shadow/delta", h.GetDeviceShadowDelta)
+		r.Put("/{device_id}/shadow/desired", h.UpdateDeviceShadowDesired)
+		r.Put("/{device_id}/shadow/reported", h.UpdateDeviceShadowReported)
+
+		r.Post("/{device_id}/commands", h.CreateDeviceCommand)
+		r.Get("/{device_id}/commands", h.GetDeviceCommandList)
+		r.Get("/{device_id}/commands/pending", h.GetPendingDeviceCommands)
+		r.Get("/{device_id}/commands/{command_id}", h.GetDeviceCommand)
+		r.Post("/{device_id}/commands/{command_id}/ack", h.AckDeviceCommand)
 	})
 
 	r.Route("/sensors", func(r chi.Router) {
@@ -37,7 +81,245 @@ func (h *Handler) Routes() *chi.Mux {
 		r.Delete("/{sensor_id}", h.DeleteSensor)
 		r.Get("/", h.GetSensorList)
 		r.Get("/{sensor_id}", h.GetSensor)
+		r.Post("/{sensor_id}/move", h.MoveSensor)
+		r.Post("/{sensor_id}/restore", h.RestoreSensor)
+		r.Get("/{sensor_id}/audit", h.GetSensorAuditList)
+		r.Get("/{sensor_id}/history", h.GetSensorDeviceHistory)
+
+		r.Post("/{sensor_id}/readings", h.CreateSensorReadings)
+		r.Get("/{sensor_id}/readings", h.GetSensorReadingList)
+
+		r.Post("/{sensor_id}/calibrations", h.CreateSensorCalibration)
+		r.Get("/{sensor_id}/calibrations", h.GetSensorCalibrationList)
+		r.Put("/{sensor_id}/calibrations/{calibration_id}", h.UpdateSensorCalibration)
+		r.Delete("/{sensor_id}/calibrations/{calibration_id}", h.DeleteSensorCalibration)
+
+		r.Put("/{sensor_id}/anomaly-detector", h.PutAnomalyDetector)
+		r.Get("/{sensor_id}/anomaly-detector", h.GetAnomalyDetector)
+		r.Delete("/{sensor_id}/anomaly-detector", h.DeleteAnomalyDetector)
+		r.Get("/{sensor_id}/anomalies", h.GetSensorAnomalyList)
+	})
+
+	r.Route("/groups", func(r chi.Router) {
+		r.Post("/", h.CreateDeviceGroup)
+		r.Put("/{group_id}", h.UpdateDeviceGroup)
+		r.Delete("/{group_id}", h.DeleteDeviceGroup)
+		r.Get("/", h.GetDeviceGroupList)
+		r.Get("/{group_id}", h.GetDeviceGroup)
+
+		r.Post("/{group_id}/devices", h.AddDeviceGroupMembers)
+		r.Delete("/{group_id}/devices/{device_id}", h.RemoveDeviceGroupMember)
+		r.Get("/{group_id}/devices", h.GetDeviceGroupDevices)
+		r.Get("/{group_id}/sensors", h.GetDeviceGroupSensors)
+		r.Put("/{group_id}/status", h.UpdateDeviceGroupStatus)
+		r.Get("/{group_id}/readings/latest", h.GetDeviceGroupLatestReadings)
+	})
+
+	r.Route("/organizations", func(r chi.Router) {
+		r.Post("/", h.CreateOrganization)
+		r.Put("/{organization_id}", h.UpdateOrganization)
+		r.Delete("/{organization_id}", h.DeleteOrganization)
+		r.Get("/", h.GetOrganizationList)
+		r.Get("/{organization_id}", h.GetOrganization)
+	})
+
+	r.Route("/sites", func(r chi.Router) {
+		r.Post("/", h.CreateSite)
+		r.Put("/{site_id}", h.UpdateSite)
+		r.Delete("/{site_id}", h.DeleteSite)
+		r.Get("/", h.GetSiteList)
+		r.Get("/{site_id}", h.GetSite)
+	})
+
+	r.Route("/zones", func(r chi.Router) {
+		r.Post("/", h.CreateZone)
+		r.Put("/{zone_id}", h.UpdateZone)
+		r.Delete("/{zone_id}", h.DeleteZone)
+		r.Get("/", h.GetZoneList)
+		r.Get("/{zone_id}", h.GetZone)
 	})
 
+	r.Route("/templates", func(r chi.Router) {
+		r.Post("/", h.CreateDeviceTemplate)
+		r.Put("/{template_id}", h.UpdateDeviceTemplate)
+		r.Delete("/{template_id}", h.DeleteDeviceTemplate)
+		r.Get("/", h.GetDeviceTemplateList)
+		r.Get("/{template_id}", h.GetDeviceTemplate)
+	})
+
+	r.Route("/maintenance-windows", func(r chi.Router) {
+		r.Post("/", h.CreateMaintenanceWindow)
+		r.Put("/{window_id}", h.UpdateMaintenanceWindow)
+		r.Delete("/{window_id}", h.DeleteMaintenanceWindow)
+		r.Get("/", h.GetMaintenanceWindowList)
+		r.Get("/{window_id}", h.GetMaintenanceWindow)
+	})
+
+	r.Route("/alert-rules", func(r chi.Router) {
+		r.Post("/", h.CreateAlertRule)
+		r.Post("/dry-run", h.DryRunAlertRule)
+		r.Put("/{rule_id}", h.UpdateAlertRule)
+		r.Delete("/{rule_id}", h.DeleteAlertRule)
+		r.Get("/", h.GetAlertRuleList)
+		r.Get("/{rule_id}", h.GetAlertRule)
+	})
+
+	r.Route("/alerts", func(r chi.Router) {
+		r.Get("/", h.GetAlertList)
+		r.Get("/{alert_id}", h.GetAlert)
+		r.Get("/{alert_id}/timeline", h.GetAlertTimeline)
+		r.Post("/{alert_id}/ack", h.AcknowledgeAlert)
+		r.Post("/{alert_id}/assign", h.AssignAlert)
+		r.Post("/{alert_id}/comments", h.CommentAlert)
+		r.Post("/{alert_id}/snooze", h.SnoozeAlert)
+		r.Post("/{alert_id}/unsnooze", h.UnsnoozeAlert)
+		r.Post("/{alert_id}/resolve", h.ResolveAlert)
+		r.Get("/{alert_id}/escalation", h.GetAlertEscalation)
+	})
+
+	r.Route("/escalation-policies", func(r chi.Router) {
+		r.Post("/", h.CreateEscalationPolicy)
+		r.Put("/{policy_id}", h.UpdateEscalationPolicy)
+		r.Delete("/{policy_id}", h.DeleteEscalationPolicy)
+		r.Get("/", h.GetEscalationPolicyList)
+		r.Get("/{policy_id}", h.GetEscalationPolicy)
+		r.Get("/{policy_id}/notifications", h.GetEscalationNotificationList)
+	})
+
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Post("/", h.CreateWebhook)
+		r.Put("/{webhook_id}", h.UpdateWebhook)
+		r.Delete("/{webhook_id}", h.DeleteWebhook)
+		r.Get("/", h.GetWebhookList)
+		r.Get("/{webhook_id}", h.GetWebhook)
+		r.Get("/{webhook_id}/deliveries", h.GetWebhookDeliveryList)
+		r.Get("/{webhook_id}/deliveries/{delivery_id}", h.GetWebhookDelivery)
+		r.Post("/{webhook_id}/deliveries/{delivery_id}/redeliver", h.RedeliverWebhookDelivery)
+	})
+
+	r.Get("/readings/aggregate", h.GetReadingAggregates)
+	r.Get("/anomalies", h.GetAnomalyList)
+	r.Get("/trash", h.GetTrashList)
+	r.Get("/audit", h.GetAuditList)
+
 	return r
 }
+
+// longPollWait parses the wait query param (in seconds) used by long-poll endpoints.
+func longPollWait(waitStr string) time.Duration {
+	seconds, _ := strconv.Atoi(waitStr)
+	if seconds <= 0 {
+		return 0
+	}
+
+	wait := time.Duration(seconds) * time.Second
+	if wait > MAX_LONG_POLL_WAIT {
+		wait = MAX_LONG_POLL_WAIT
+	}
+
+	return wait
+}
+
+// waitForEvent blocks until an event arrives, the wait elapses or the request is cancelled.
+func waitForEvent(ctx context.Context, ch <-chan events.Event, wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ch:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// parseTimeRange parses optional RFC3339 from/to query params.
+func parseTimeRange(fromStr, toStr string) (from, to *time.Time, errs []string) {
+	if fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("from is not a RFC3339 time: %s", fromStr))
+		} else {
+			from = &t
+		}
+	}
+
+	if toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("to is not a RFC3339 time: %s", toStr))
+		} else {
+			to = &t
+		}
+	}
+
+	return from, to, errs
+}
+
+// locationFilter reads the organization_id/site_id/zone_id query params used by list endpoints.
+func locationFilter(q url.Values) entities.LocationFilter {
+	return entities.LocationFilter{
+		OrganizationID: q.Get("organization_id"),
+		SiteID:         q.Get("site_id"),
+		ZoneID:         q.Get("zone_id"),
+	}
+}
+
+// setETag sets the ETag of a response to the version of the device or sensor it returns.
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// ifMatchVersions returns the versions accepted by the If-Match header of the request, nil without the header
+// or for "*". Weak and malformed entity tags never match, so a header with only these accepts no version.
+func ifMatchVersions(r *http.Request) []int {
+	header := strings.Join(r.Header.Values("If-Match"), ",")
+	if strings.TrimSpace(header) == "" {
+		return nil
+	}
+
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil
+		}
+
+		value, ok := strings.CutPrefix(tag, `"`)
+		if !ok {
+			continue
+		}
+		value, ok = strings.CutSuffix(value, `"`)
+		if !ok {
+			continue
+		}
+
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	return versions
+}
+
+func acceptsGeoJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), CONTENT_TYPE_GEO_JSON)
+}
+
+// renderGeoJSON writes v without the response envelope, so it can be used as a map source directly.
+func renderGeoJSON(w http.ResponseWriter, r *http.Request, v any) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, util.NewResponse().Set("failed to encode geojson", nil))
+		return
+	}
+
+	w.Header().Set("Content-Type", CONTENT_TYPE_GEO_JSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf)
+}
@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetDeviceAuditList get device audit log handler
+// @Summary			Get audit log of a Device.
+// @Description		Get the changes of a device, newest first by default. The entries are kept after the device is purged. The claimed actor of an entry is self-reported by the X-User header of its request.
+// @Tags			Audit Log
+// @Produce			json
+// @Param			device_id		path			string	 true	"Device ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: created_at/claimed_actor). For desc order, use prefix '-'"	example(-created_at)
+// @Param			action			query			string	 false	"Action of the changes (value: create/update/delete/restore/purge)"	example(update)
+// @Param			claimed_actor	query			string	 false	"Self-reported actor of the changes (X-User header)"		example(jane)
+// @Param			request_id		query			string	 false	"Request that made the changes"
+// @Param			from			query			string	 false	"Changes made at or after (RFC3339)"						example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Changes made before (RFC3339)"								example(2024-03-02T00:00:00Z)
+// @Success			200 			{object}		util.Response{data=[]entities.AuditEntry}
+// @Failure			400				{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/audit [get]
+func (h *Handler) GetDeviceAuditList(w http.ResponseWriter, r *http.Request) {
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	h.getAuditList(w, r, entities.AUDIT_RESOURCE_DEVICE, deviceID)
+}
+
+// GetSensorAuditList get sensor audit log handler
+// @Summary			Get audit log of a Sensor.
+// @Description		Get the changes of a sensor, newest first by default. The entries are kept after the sensor is purged. The claimed actor of an entry is self-reported by the X-User header of its request.
+// @Tags			Audit Log
+// @Produce			json
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: created_at/claimed_actor). For desc order, use prefix '-'"	example(-created_at)
+// @Param			action			query			string	 false	"Action of the changes (value: create/update/delete/restore/purge)"	example(update)
+// @Param			claimed_actor	query			string	 false	"Self-reported actor of the changes (X-User header)"		example(jane)
+// @Param			request_id		query			string	 false	"Request that made the changes"
+// @Param			from			query			string	 false	"Changes made at or after (RFC3339)"						example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Changes made before (RFC3339)"								example(2024-03-02T00:00:00Z)
+// @Success			200 			{object}		util.Response{data=[]entities.AuditEntry}
+// @Failure			400				{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/audit [get]
+func (h *Handler) GetSensorAuditList(w http.ResponseWriter, r *http.Request) {
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	h.getAuditList(w, r, entities.AUDIT_RESOURCE_SENSOR, sensorID)
+}
+
+// GetAuditList get audit log handler
+// @Summary			Get audit log.
+// @Description		Get the changes of every device and sensor, newest first by default. The claimed actor of a change is the X-User header of its request, anonymous without it, or the system for background work such as purging the trash. The header is not authenticated, so the claimed actor is self-reported by the client and must not be relied on as proof of who made a change.
+// @Tags			Audit Log
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: created_at/claimed_actor). For desc order, use prefix '-'"	example(-created_at)
+// @Param			resource_type	query			string	 false	"Type of the changed resources (value: device/sensor)"		example(device)
+// @Param			resource_id		query			string	 false	"ID of the changed resource"
+// @Param			action			query			string	 false	"Action of the changes (value: create/update/delete/restore/purge)"	example(update)
+// @Param			claimed_actor	query			string	 false	"Self-reported actor of the changes (X-User header)"		example(jane)
+// @Param			request_id		query			string	 false	"Request that made the changes"
+// @Param			from			query			string	 false	"Changes made at or after (RFC3339)"						example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Changes made before (RFC3339)"								example(2024-03-02T00:00:00Z)
+// @Success			200 			{object}		util.Response{data=[]entities.AuditEntry}
+// @Failure			400				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/audit [get]
+func (h *Handler) GetAuditList(w http.ResponseWriter, r *http.Request) {
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+
+	resourceType := entities.AuditResourceType(q.Get("resource_type"))
+	switch resourceType {
+	case "", entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_RESOURCE_SENSOR:
+	default:
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation([]string{fmt.Sprintf("resource_type must be one of device, sensor: %s", resourceType)}))
+		return
+	}
+
+	h.getAuditList(w, r, resourceType, q.Get("resource_id"))
+}
+
+// getAuditList responds with the audit log entries of the resources of the given type and ID, of every
+// resource when they are empty, filtered by the query params.
+func (h *Handler) getAuditList(w http.ResponseWriter, r *http.Request, resourceType entities.AuditResourceType, resourceID string) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params, errs := auditEntryListParams(q)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+	params.ResourceType = resourceType
+	params.ResourceID = resourceID
+	params.Limit = count
+	params.Offset = (page - 1) * count
+
+	results, total, err := h.repo.GetAuditEntryList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// auditEntryListParams parses the sort, action, claimed_actor, request_id, from and to query params of the audit lists.
+func auditEntryListParams(q url.Values) (entities.GetAuditEntryListParams, []string) {
+	params := entities.GetAuditEntryListParams{
+		Action:       entities.AuditAction(q.Get("action")),
+		ClaimedActor: q.Get("claimed_actor"),
+		RequestID:    q.Get("request_id"),
+		Sort:         q.Get("sort"),
+	}
+
+	from, to, errs := parseTimeRange(q.Get("from"), q.Get("to"))
+	params.From = from
+	params.To = to
+
+	switch params.Action {
+	case "", entities.AUDIT_ACTION_CREATE, entities.AUDIT_ACTION_UPDATE, entities.AUDIT_ACTION_DELETE,
+		entities.AUDIT_ACTION_RESTORE, entities.AUDIT_ACTION_PURGE:
+	default:
+		errs = append(errs, fmt.Sprintf("action must be one of create, update, delete, restore, purge: %s", params.Action))
+	}
+
+	return params, errs
+}
@@ -0,0 +1,286 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateSensorCalibration create sensor calibration handler
+// @Summary			Create Sensor Calibration.
+// @Description		Add a calibration (offset, scale, polynomial or multipoint) effective from the given time.
+// @Description		Readings recorded from then until the next calibration are re-derived, raw values are kept.
+// @Tags			Sensor Calibrations
+// @Accept			json
+// @Produce			json
+// @Param 			sensor_id	path		string										true	"Sensor ID"
+// @Param 			json		body		entities.CreateUpdateSensorCalibrationPayload	true	"Calibration data"
+// @Success			201			{object}	util.Response{data=entities.SensorCalibration}
+// @Failure			400			{object}	util.Response
+// @Failure			404			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/sensors/{sensor_id}/calibrations [post]
+func (h *Handler) CreateSensorCalibration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateSensorCalibrationPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateSensorCalibrationPayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	calibrationID, err := h.repo.CreateSensorCalibration(ctx, sensorCalibration(sensorID, body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetSensorCalibration(ctx, calibrationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateSensorCalibration update sensor calibration handler
+// @Summary			Update Sensor Calibration.
+// @Description		Update a calibration, the affected readings are re-derived from their raw values.
+// @Tags			Sensor Calibrations
+// @Accept			json
+// @Param 			sensor_id		path	string										true	"Sensor ID"
+// @Param 			calibration_id	path	string										true	"Calibration ID"
+// @Param 			json			body	entities.CreateUpdateSensorCalibrationPayload	true	"Calibration data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.SensorCalibration}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/sensors/{sensor_id}/calibrations/{calibration_id} [put]
+func (h *Handler) UpdateSensorCalibration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	calibrationID := chi.URLParam(r, "calibration_id")
+	if sensorID == "" || calibrationID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor calibration not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateSensorCalibrationPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateSensorCalibrationPayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	calibration, err := h.repo.GetSensorCalibration(ctx, calibrationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if calibration.SensorID != sensorID {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor calibration not found", nil))
+		return
+	}
+
+	err = h.repo.UpdateSensorCalibration(ctx, calibrationID, sensorCalibration(sensorID, body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetSensorCalibration(ctx, calibrationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteSensorCalibration delete sensor calibration handler
+// @Summary			Delete Sensor Calibration.
+// @Description		Delete a calibration, its readings fall back to the previous calibration or their raw values.
+// @Tags			Sensor Calibrations
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Param			calibration_id	path			string	 true	"Calibration ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/calibrations/{calibration_id} [delete]
+func (h *Handler) DeleteSensorCalibration(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	calibrationID := chi.URLParam(r, "calibration_id")
+	if sensorID == "" || calibrationID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor calibration not found", nil))
+		return
+	}
+
+	calibration, err := h.repo.GetSensorCalibration(ctx, calibrationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if calibration.SensorID != sensorID {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor calibration not found", nil))
+		return
+	}
+
+	err = h.repo.DeleteSensorCalibration(ctx, calibrationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetSensorCalibrationList get sensor calibration list handler
+// @Summary			Get list of Sensor Calibrations.
+// @Description		Get every calibration of a sensor ordered by effective time.
+// @Tags			Sensor Calibrations
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.SensorCalibration}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/calibrations [get]
+func (h *Handler) GetSensorCalibrationList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	results, err := h.repo.GetSensorCalibrationList(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+func (h *Handler) validateSensorCalibrationPayload(body entities.CreateUpdateSensorCalibrationPayload) []string {
+	err := h.validate.Struct(body)
+	if err != nil {
+		return util.ParseValidatorErr(err)
+	}
+
+	errs := []string{}
+	if body.Kind == entities.CALIBRATION_KIND_MULTIPOINT {
+		seen := map[float64]bool{}
+		for _, p := range body.Points {
+			if seen[p.Raw] {
+				errs = append(errs, fmt.Sprintf("Points has a duplicate raw value %v", p.Raw))
+			}
+			seen[p.Raw] = true
+		}
+	}
+
+	return errs
+}
+
+// sensorCalibration keeps only the fields used by the calibration kind.
+func sensorCalibration(sensorID string, body entities.CreateUpdateSensorCalibrationPayload) entities.SensorCalibration {
+	calibration := entities.SensorCalibration{
+		SensorID:      sensorID,
+		Kind:          body.Kind,
+		Description:   body.Description,
+		EffectiveFrom: body.EffectiveFrom,
+	}
+
+	switch body.Kind {
+	case entities.CALIBRATION_KIND_OFFSET:
+		calibration.Offset = body.Offset
+	case entities.CALIBRATION_KIND_SCALE:
+		calibration.Scale = body.Scale
+	case entities.CALIBRATION_KIND_POLYNOMIAL:
+		calibration.Coefficients = body.Coefficients
+	case entities.CALIBRATION_KIND_MULTIPOINT:
+		calibration.Points = body.Points
+	}
+
+	return calibration
+}
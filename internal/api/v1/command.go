@@ -0,0 +1,330 @@
+package v1
+
+import (
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/util"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateDeviceCommand create device command handler
+// @Summary			Enqueue Device Command.
+// @Description		Enqueue a command for a device. The command expires when it is not acknowledged within ttl seconds.
+// @Tags			Device Commands
+// @Accept			json
+// @Produce			json
+// @Param 			device_id	path		string								true	"Device ID"
+// @Param 			json		body		entities.CreateDeviceCommandPayload	true	"Command data"
+// @Success			201			{object}	util.Response{data=entities.DeviceCommand}
+// @Failure			400			{object}	util.Response
+// @Failure			404			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/devices/{device_id}/commands [post]
+func (h *Handler) CreateDeviceCommand(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	var body entities.CreateDeviceCommandPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	commandID, err := h.repo.CreateDeviceCommand(ctx, entities.DeviceCommand{
+		DeviceID:  deviceID,
+		Name:      body.Name,
+		Params:    body.Params,
+		ExpiresAt: time.Now().UTC().Add(time.Duration(body.TTL) * time.Second),
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceCommand(ctx, commandID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	h.broker.Publish(events.Event{
+		Type:       events.DEVICE_COMMAND_CREATED,
+		ResourceID: deviceID,
+		Data:       result,
+	})
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// GetPendingDeviceCommands get pending device commands handler
+// @Summary			Poll Pending Device Commands.
+// @Description		Get the unacknowledged commands of a device and mark them as delivered.
+// @Description		With wait, the request is held until a command is enqueued when there is none.
+// @Tags			Device Commands
+// @Produce			json
+// @Param			device_id		path			string	 true	"Device ID"
+// @Param			wait			query			int		 false	"Long-poll wait in seconds (max 50)"		example(30)
+// @Success			200 			{object}		util.Response{data=[]entities.DeviceCommand}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/commands/pending [get]
+func (h *Handler) GetPendingDeviceCommands(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	wait := longPollWait(r.URL.Query().Get("wait"))
+
+	_, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	// subscribe before reading, so a command enqueued between the read and the wait is not missed
+	created, unsubscribe := h.broker.Subscribe(events.ForResource(events.DEVICE_COMMAND_CREATED, deviceID))
+	defer unsubscribe()
+
+	results, err := h.repo.DeliverDeviceCommands(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	if wait > 0 && len(results) == 0 && waitForEvent(ctx, created, wait) {
+		results, err = h.repo.DeliverDeviceCommands(ctx, deviceID)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// AckDeviceCommand acknowledge device command handler
+// @Summary			Acknowledge Device Command.
+// @Description		Report the result of a delivered command.
+// @Tags			Device Commands
+// @Accept			json
+// @Produce			json
+// @Param 			device_id	path		string								true	"Device ID"
+// @Param 			command_id	path		string								true	"Command ID"
+// @Param 			json		body		entities.AckDeviceCommandPayload	true	"Command result"
+// @Success			200			{object}	util.Response{data=entities.DeviceCommand}
+// @Failure			400			{object}	util.Response
+// @Failure			404			{object}	util.Response
+// @Failure			409			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/devices/{device_id}/commands/{command_id}/ack [post]
+func (h *Handler) AckDeviceCommand(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	commandID := chi.URLParam(r, "command_id")
+	if deviceID == "" || commandID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device command not found", nil))
+		return
+	}
+
+	var body entities.AckDeviceCommandPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	command, err := h.repo.GetDeviceCommand(ctx, commandID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if command.DeviceID != deviceID {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device command not found", nil))
+		return
+	}
+
+	err = h.repo.AcknowledgeDeviceCommand(ctx, commandID, body)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceCommand(ctx, commandID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	h.broker.Publish(events.Event{
+		Type:       events.DEVICE_COMMAND_ACKNOWLEDGED,
+		ResourceID: deviceID,
+		Data:       result,
+	})
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetDeviceCommand get device command handler
+// @Summary			Get device command by command ID.
+// @Description		Get device command by command ID.
+// @Tags			Device Commands
+// @Produce			json
+// @Param			device_id		path			string	 true	"Device ID"
+// @Param			command_id		path			string	 true	"Command ID"
+// @Success			200 			{object}		util.Response{data=entities.DeviceCommand}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/commands/{command_id} [get]
+func (h *Handler) GetDeviceCommand(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	commandID := chi.URLParam(r, "command_id")
+	if deviceID == "" || commandID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device command not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceCommand(ctx, commandID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if result.DeviceID != deviceID {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device command not found", nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetDeviceCommandList get device command list handler
+// @Summary			Get command history of a Device.
+// @Description		Get command history of a Device.
+// @Tags			Device Commands
+// @Produce			json
+// @Param			device_id		path			string	 true	"Device ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/expires_at/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			status			query			string	 false	"Filter by status (pending/delivered/succeeded/failed/expired)"	example(pending)
+// @Success			200 			{object}		util.Response{data=[]entities.DeviceCommand}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/commands [get]
+func (h *Handler) GetDeviceCommandList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetDeviceCommandListParams{
+		DeviceID: deviceID,
+		Status:   q.Get("status"),
+		Sort:     q.Get("sort"),
+		Limit:    count,
+		Offset:   (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetDeviceCommandList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
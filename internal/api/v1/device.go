@@ -2,9 +2,12 @@ package v1
 
 import (
 	"encoding/json"
+	"fmt"
 	"go-api/internal/entities"
 	"go-api/pkg/util"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -13,20 +16,47 @@ import (
 // CreateDevice create device handler
 // @Summary			Create Device.
 // @Description		Create new Device.
+// @Description		With ?template=<slug> the template fields are used as defaults that the body can override,
+// @Description		and the template sensors are created together with the device in one transaction. The response then also has the sensors.
 // @Tags			Devices
 // @Accept			json
 // @Produce			json
-// @Param 			json	body		entities.CreateUpdateDevicePayload	true	"Device data"
-// @Success			201		{object}	util.Response{data=entities.Device}
-// @Failure			400		{object}	util.Response
-// @Failure			500		{object}	util.Response
+// @Param			template	query		string								false	"Device template slug"	example(weather-station-v2)
+// @Param 			json		body		entities.CreateUpdateDevicePayload	true	"Device data"
+// @Success			201			{object}	util.Response{data=entities.Device}
+// @Failure			400			{object}	util.Response
+// @Failure			404			{object}	util.Response
+// @Failure			500			{object}	util.Response
 // @Router	/v1/devices [post]
 func (h *Handler) CreateDevice(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	resp := util.NewResponse()
 
-	var body entities.CreateUpdateDevicePayload
-	err := json.NewDecoder(r.Body).Decode(&body)
+	var (
+		body     entities.CreateUpdateDevicePayload
+		template *entities.DeviceTemplate
+		err      error
+	)
+
+	if slug := r.URL.Query().Get("template"); slug != "" {
+		template, err = h.repo.GetDeviceTemplateBySlug(ctx, slug)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+
+		// decoding the body over the template defaults keeps the fields it does not set
+		body = entities.CreateUpdateDevicePayload{
+			Description: template.Device.Description,
+			Status:      template.Device.Status,
+			Attributes:  template.Device.Attributes,
+			Labels:      template.Device.Labels,
+		}
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&body)
 	if err != nil {
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, resp.Set("invalid data", nil))
@@ -41,11 +71,34 @@ func (h *Handler) CreateDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	deviceID, err := h.repo.CreateDevice(ctx, entities.Device{
+	if body.ZoneID != nil {
+		_, err = h.repo.GetZone(ctx, *body.ZoneID)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+	}
+
+	device := entities.Device{
 		Name:        body.Name,
 		Description: body.Description,
 		Status:      body.Status,
-	})
+		ZoneID:      body.ZoneID,
+		Latitude:    body.Latitude,
+		Longitude:   body.Longitude,
+		Altitude:    body.Altitude,
+		Attributes:  body.Attributes,
+		Labels:      body.Labels,
+	}
+
+	if template != nil {
+		h.createDeviceFromTemplate(w, r, device, template)
+		return
+	}
+
+	deviceID, err := h.repo.CreateDevice(ctx, device)
 	if err != nil {
 		status, msg := util.ErrStatusCode(err)
 		render.Status(r, status)
@@ -61,21 +114,77 @@ func (h *Handler) CreateDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, result.Version)
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, resp.Set("created", result))
 }
 
+// createDeviceFromTemplate creates the device with the sensors of the template and responds with both.
+func (h *Handler) createDeviceFromTemplate(w http.ResponseWriter, r *http.Request, device entities.Device, template *entities.DeviceTemplate) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensors := []entities.Sensor{}
+	for _, v := range template.Sensors {
+		sensors = append(sensors, entities.Sensor{
+			Type:        v.Type,
+			Name:        v.Name,
+			Description: v.Description,
+			Attributes:  v.Attributes,
+			Labels:      v.Labels,
+		})
+	}
+
+	deviceID, err := h.repo.CreateDeviceWithSensors(ctx, device, sensors)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	deviceSensors, _, err := h.repo.GetSensorList(ctx, entities.GetSensorListParams{
+		DeviceID: deviceID,
+		Sort:     "created_at",
+		Limit:    len(sensors),
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if deviceSensors == nil {
+		deviceSensors = []*entities.Sensor{}
+	}
+
+	setETag(w, result.Version)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", entities.DeviceWithSensors{Device: *result, Sensors: deviceSensors}))
+}
+
 // UpdateDevice update device handler
 // @Summary			Update Device.
-// @Description		Update existing Device.
+// @Description		Update existing Device. With If-Match, the update fails with 412 when the device changed since its ETag was read.
 // @Tags			Devices
 // @Accept			json
 // @Param 			device_id	path	string								true	"Device ID" example(01HQSH92SNYQVCBDSD38XNBRYM)
+// @Param 			If-Match	header	string								false	"ETag of the device" example("3")
 // @Param 			json		body	entities.CreateUpdateDevicePayload	true	"Device data"
 // @Produce			json
 // @Success			200		{object}	util.Response{data=entities.Device}
+// @Header			200		{string}	ETag	"Version of the device"
 // @Failure			400		{object}	util.Response
 // @Failure			404		{object}	util.Response
+// @Failure			412		{object}	util.Response
 // @Failure			500		{object}	util.Response
 // @Router	/v1/devices/{device_id} [put]
 func (h *Handler) UpdateDevice(w http.ResponseWriter, r *http.Request) {
@@ -105,11 +214,27 @@ func (h *Handler) UpdateDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if body.ZoneID != nil {
+		_, err = h.repo.GetZone(ctx, *body.ZoneID)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+	}
+
 	err = h.repo.UpdateDevice(ctx, deviceID, entities.Device{
 		Name:        body.Name,
 		Description: body.Description,
 		Status:      body.Status,
-	})
+		ZoneID:      body.ZoneID,
+		Latitude:    body.Latitude,
+		Longitude:   body.Longitude,
+		Altitude:    body.Altitude,
+		Attributes:  body.Attributes,
+		Labels:      body.Labels,
+	}, ifMatchVersions(r))
 	if err != nil {
 		status, msg := util.ErrStatusCode(err)
 		render.Status(r, status)
@@ -125,18 +250,23 @@ func (h *Handler) UpdateDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, result.Version)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp.Set("success", result))
 }
 
 // DeleteDevice delete device handler
 // @Summary			Delete Device.
-// @Description		Delete Device.
+// @Description		Move a device to the trash, it can be restored until it is purged. Devices that still have sensors cannot be deleted, unless cascade deletes their sensors too.
 // @Tags			Devices
 // @Param			device_id		path			string	 true	"Device ID" example(01HQSH92SNYQVCBDSD38XNBRYM)
+// @Param			cascade			query			bool	 false	"Delete the sensors of the device with it"
+// @Param			If-Match		header			string	 false	"ETag of the device, the delete fails with 412 when it changed" example("3")
 // @Produce			json
 // @Success			200 			{object}		util.Response
 // @Failure			404				{object}		util.Response
+// @Failure			409				{object}		util.Response
+// @Failure			412				{object}		util.Response
 // @Failure			500				{object}		util.Response
 // @Router	/v1/devices/{device_id} [delete]
 func (h *Handler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
@@ -150,7 +280,9 @@ func (h *Handler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.repo.DeleteDevice(ctx, deviceID)
+	cascade := r.URL.Query().Get("cascade") == "true"
+
+	err := h.repo.DeleteDevice(ctx, deviceID, cascade, ifMatchVersions(r))
 	if err != nil {
 		status, msg := util.ErrStatusCode(err)
 		render.Status(r, status)
@@ -162,6 +294,49 @@ func (h *Handler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, resp.Set("success", nil))
 }
 
+// RestoreDevice restore device handler
+// @Summary			Restore Device.
+// @Description		Take a device out of the trash with the sensors deleted together with it.
+// @Tags			Devices
+// @Param			device_id		path			string	 true	"Device ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.Device}
+// @Failure			404				{object}		util.Response
+// @Failure			409				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/restore [post]
+func (h *Handler) RestoreDevice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	err := h.repo.RestoreDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	setETag(w, result.Version)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
 // GetDevice get device handler
 // @Summary			Get device by device ID.
 // @Description		Get device by device ID.
@@ -169,6 +344,7 @@ func (h *Handler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
 // @Param			device_id		path			string	 true	"Device ID"
 // @Produce			json
 // @Success			200 			{object}		util.Response{data=entities.Device}
+// @Header			200				{string}		ETag	"Version of the device"
 // @Failure			404				{object}		util.Response
 // @Failure			500				{object}		util.Response
 // @Router	/v1/devices/{device_id} [get]
@@ -191,6 +367,7 @@ func (h *Handler) GetDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, result.Version)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp.Set("success", result))
 }
@@ -198,14 +375,27 @@ func (h *Handler) GetDevice(w http.ResponseWriter, r *http.Request) {
 // GetDeviceList get device list handler
 // @Summary			Get list of Device.
 // @Description		Get list of Device.
+// @Description		Filter by attributes with attr.<key>=<value> query params, e.g. attr.crop=rice.
+// @Description		Send Accept: application/geo+json to get the page as a GeoJSON FeatureCollection.
 // @Tags			Devices
 // @Produce			json
+// @Produce			application/geo+json
 // @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
 // @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
-// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at/distance). For desc order, use prefix '-'"	example(-created_at)
 // @Param			search			query			string	 false	"Keyword for searching device by title or content" 			example(raspi)
+// @Param			attr.crop		query			string	 false	"Filter by attribute value, any attribute key can be used"	example(rice)
+// @Param			selector		query			string	 false	"Label selector (=, !=, in, notin, key, !key)"				example(site=north,env!=test)
+// @Param			organization_id	query			string	 false	"Filter by organization, includes devices of all its sites and zones"
+// @Param			site_id			query			string	 false	"Filter by site, includes devices of all its zones"
+// @Param			zone_id			query			string	 false	"Filter by zone"
+// @Param			near			query			string	 false	"Point as lat,lng, adds distance in meters and orders by it"	example(-6.9147,107.6098)
+// @Param			radius			query			string	 false	"Max distance from near (m or km)"							example(500m)
+// @Param			bbox			query			string	 false	"Bounding box as minLng,minLat,maxLng,maxLat"				example(107.5,-7.0,107.7,-6.8)
 // @Success			200 			{object}		util.Response{data=[]entities.Device}
+// @Failure			400				{object}		util.Response
 // @Failure			500				{object}		util.Response
+// @Success			200 			{object}		entities.GeoJSONFeatureCollection	"GeoJSON representation"
 // @Router	/v1/devices [get]
 func (h *Handler) GetDeviceList(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -214,11 +404,29 @@ func (h *Handler) GetDeviceList(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	page, count := util.Pagination(q.Get("page"), q.Get("count"))
 
+	selector, errs := util.ParseLabelSelector(q.Get("selector"))
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid selector", nil).AddErrValidation(errs))
+		return
+	}
+
+	geo, errs := util.ParseGeoFilter(q.Get("near"), q.Get("radius"), q.Get("bbox"))
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid geo filter", nil).AddErrValidation(errs))
+		return
+	}
+
 	params := entities.GetDeviceListParams{
-		Search: q.Get("search"),
-		Sort:   q.Get("sort"),
-		Limit:  count,
-		Offset: (page - 1) * count,
+		Search:     q.Get("search"),
+		Attributes: util.AttributeFilters(q),
+		Selector:   selector,
+		Location:   locationFilter(q),
+		Geo:        geo,
+		Sort:       q.Get("sort"),
+		Limit:      count,
+		Offset:     (page - 1) * count,
 	}
 
 	results, total, err := h.repo.GetDeviceList(ctx, params)
@@ -229,7 +437,84 @@ func (h *Handler) GetDeviceList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if acceptsGeoJSON(r) {
+		renderGeoJSON(w, r, entities.NewDeviceFeatureCollection(results))
+		return
+	}
+
 	resp.AddMeta(page, count, total)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp.Set("success", results))
 }
+
+// CloneDevice clone device handler
+// @Summary			Clone Device.
+// @Description		Copy a Device with all its sensors, labels and attributes, count times (default 1) in one transaction.
+// @Description		{n} in the name is replaced by the clone number, counting from start (default 1), and is required when count > 1.
+// @Tags			Devices
+// @Accept			json
+// @Param 			device_id	path	string						true	"Device ID"
+// @Param 			json		body	entities.CloneDevicePayload	true	"Clone options"
+// @Produce			json
+// @Success			201		{object}	util.Response{data=[]entities.ClonedDevice}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/devices/{device_id}/clone [post]
+func (h *Handler) CloneDevice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	var body entities.CloneDevicePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	if body.Count == 0 {
+		body.Count = 1
+	}
+	if body.Start == 0 {
+		body.Start = 1
+	}
+	if body.Count > 1 && !strings.Contains(body.Name, entities.CLONE_NAME_COUNTER) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation([]string{
+			fmt.Sprintf("Name must contain %s when Count is greater than 1", entities.CLONE_NAME_COUNTER),
+		}))
+		return
+	}
+
+	names := []string{}
+	for i := 0; i < body.Count; i++ {
+		names = append(names, strings.ReplaceAll(body.Name, entities.CLONE_NAME_COUNTER, strconv.Itoa(body.Start+i)))
+	}
+
+	results, err := h.repo.CloneDevice(ctx, deviceID, names)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", results))
+}
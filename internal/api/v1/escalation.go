@@ -0,0 +1,425 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateEscalationPolicy create escalation policy handler
+// @Summary			Create Escalation Policy.
+// @Description		Add a policy notifying the firing alerts of its rules step by step, until they are acknowledged or resolved.
+// @Description		A step emails its recipients and sends an `alert.escalation` event to its webhooks `delay` seconds after the alert fired,
+// @Description		delays increase from step to step and the last step is repeated every `repeat_interval` seconds (never when 0).
+// @Description		Alerts with the same `group_by` values (default rule) are notified together: the first step waits `group_wait` seconds
+// @Description		for more alerts and a group is notified at most once every `group_interval` seconds. An alert firing again within
+// @Description		`dedup_window` seconds of its last notification resumes its escalation, and `rate_limit` caps the notifications per hour.
+// @Tags			Escalation Policies
+// @Accept			json
+// @Produce			json
+// @Param 			json		body		entities.CreateUpdateEscalationPolicyPayload	true	"Escalation policy data"
+// @Success			201			{object}	util.Response{data=entities.EscalationPolicy}
+// @Failure			400			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/escalation-policies [post]
+func (h *Handler) CreateEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateEscalationPolicyPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs, err := h.validateEscalationPolicyPayload(ctx, body)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	policyID, err := h.repo.CreateEscalationPolicy(ctx, escalationPolicy(body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetEscalationPolicy(ctx, policyID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateEscalationPolicy update escalation policy handler
+// @Summary			Update Escalation Policy.
+// @Description		Update an Escalation Policy. Escalations in progress follow the new steps from their next notification on.
+// @Tags			Escalation Policies
+// @Accept			json
+// @Param 			policy_id	path	string										true	"Escalation Policy ID"
+// @Param 			json		body	entities.CreateUpdateEscalationPolicyPayload	true	"Escalation policy data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.EscalationPolicy}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/escalation-policies/{policy_id} [put]
+func (h *Handler) UpdateEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	policyID := chi.URLParam(r, "policy_id")
+	if policyID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("escalation policy not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateEscalationPolicyPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs, err := h.validateEscalationPolicyPayload(ctx, body)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetEscalationPolicy(ctx, policyID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateEscalationPolicy(ctx, policyID, escalationPolicy(body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetEscalationPolicy(ctx, policyID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteEscalationPolicy delete escalation policy handler
+// @Summary			Delete Escalation Policy.
+// @Description		Delete an Escalation Policy with its escalations and notification log. Its rules are left without a policy.
+// @Tags			Escalation Policies
+// @Param			policy_id		path			string	 true	"Escalation Policy ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/escalation-policies/{policy_id} [delete]
+func (h *Handler) DeleteEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	policyID := chi.URLParam(r, "policy_id")
+	if policyID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("escalation policy not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetEscalationPolicy(ctx, policyID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.DeleteEscalationPolicy(ctx, policyID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetEscalationPolicy get escalation policy handler
+// @Summary			Get Escalation Policy by ID.
+// @Description		Get Escalation Policy by ID.
+// @Tags			Escalation Policies
+// @Param			policy_id		path			string	 true	"Escalation Policy ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.EscalationPolicy}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/escalation-policies/{policy_id} [get]
+func (h *Handler) GetEscalationPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	policyID := chi.URLParam(r, "policy_id")
+	if policyID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("escalation policy not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetEscalationPolicy(ctx, policyID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetEscalationPolicyList get escalation policy list handler
+// @Summary			Get list of Escalation Policies.
+// @Description		Get list of Escalation Policies.
+// @Tags			Escalation Policies
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching policies by name or description"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.EscalationPolicy}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/escalation-policies [get]
+func (h *Handler) GetEscalationPolicyList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetEscalationPolicyListParams{
+		Search: q.Get("search"),
+		Sort:   q.Get("sort"),
+		Limit:  count,
+		Offset: (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetEscalationPolicyList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetEscalationNotificationList get escalation notification list handler
+// @Summary			Get the notification log of an Escalation Policy.
+// @Description		Get the notifications sent by a policy, with the step, the group, the alerts and the channels that failed.
+// @Tags			Escalation Policies
+// @Param			policy_id		path			string	 true	"Escalation Policy ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: step/created_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			alert_id		query			string	 false	"Notifications of the alert"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.EscalationNotification}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/escalation-policies/{policy_id}/notifications [get]
+func (h *Handler) GetEscalationNotificationList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	policyID := chi.URLParam(r, "policy_id")
+	if policyID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("escalation policy not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetEscalationPolicy(ctx, policyID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetEscalationNotificationListParams{
+		PolicyID: policyID,
+		AlertID:  q.Get("alert_id"),
+		Sort:     q.Get("sort"),
+		Limit:    count,
+		Offset:   (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetEscalationNotificationList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetAlertEscalation get alert escalation handler
+// @Summary			Get the Escalation of an Alert.
+// @Description		Get the progress of an alert through the steps of its rule's escalation policy, the last step notified and when the next one is due.
+// @Tags			Alerts
+// @Param			alert_id		path			string	 true	"Alert ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.AlertEscalation}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/alerts/{alert_id}/escalation [get]
+func (h *Handler) GetAlertEscalation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	alertID := chi.URLParam(r, "alert_id")
+	if alertID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("alert not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetAlert(ctx, alertID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetAlertEscalation(ctx, alertID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// validateEscalationPolicyPayload checks the steps of a policy, every step has a channel, delays increase
+// and the webhooks exist.
+func (h *Handler) validateEscalationPolicyPayload(ctx context.Context, body entities.CreateUpdateEscalationPolicyPayload) ([]string, error) {
+	err := h.validate.Struct(body)
+	if err != nil {
+		return util.ParseValidatorErr(err), nil
+	}
+
+	errs := []string{}
+	for i, step := range body.Steps {
+		if len(step.Emails) == 0 && len(step.WebhookIDs) == 0 {
+			errs = append(errs, fmt.Sprintf("Steps[%d] needs emails or webhook_ids", i))
+		}
+		if i > 0 && step.Delay <= body.Steps[i-1].Delay {
+			errs = append(errs, fmt.Sprintf("Steps[%d] delay must be above the delay of the previous step", i))
+		}
+
+		for _, webhookID := range step.WebhookIDs {
+			_, err := h.repo.GetWebhook(ctx, webhookID)
+			if err != nil {
+				if errors.Is(err, util.ErrNotFound) {
+					errs = append(errs, fmt.Sprintf("Steps[%d] references unknown webhook %s", i, webhookID))
+					continue
+				}
+				return nil, err
+			}
+		}
+	}
+
+	return errs, nil
+}
+
+// escalationPolicy fills in the defaults of a policy, alerts grouped by rule when group_by is not given
+// and enabled.
+func escalationPolicy(body entities.CreateUpdateEscalationPolicyPayload) entities.EscalationPolicy {
+	policy := entities.EscalationPolicy{
+		Name:           body.Name,
+		Description:    body.Description,
+		Steps:          body.Steps,
+		RepeatInterval: body.RepeatInterval,
+		GroupBy:        body.GroupBy,
+		GroupWait:      body.GroupWait,
+		GroupInterval:  body.GroupInterval,
+		DedupWindow:    body.DedupWindow,
+		RateLimit:      body.RateLimit,
+		Enabled:        true,
+	}
+
+	for i := range policy.Steps {
+		if policy.Steps[i].Emails == nil {
+			policy.Steps[i].Emails = []string{}
+		}
+		if policy.Steps[i].WebhookIDs == nil {
+			policy.Steps[i].WebhookIDs = []string{}
+		}
+	}
+	if policy.GroupBy == nil {
+		policy.GroupBy = []entities.EscalationGroupBy{entities.ESCALATION_GROUP_BY_RULE}
+	}
+	if body.Enabled != nil {
+		policy.Enabled = *body.Enabled
+	}
+
+	return policy
+}
@@ -0,0 +1,608 @@
+package v1
+
+import (
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateDeviceGroup create device group handler
+// @Summary			Create Device Group.
+// @Description		Create new Device Group. Static groups have explicit members, dynamic groups select devices with a saved filter.
+// @Tags			Device Groups
+// @Accept			json
+// @Produce			json
+// @Param 			json	body		entities.CreateUpdateDeviceGroupPayload	true	"Device group data"
+// @Success			201		{object}	util.Response{data=entities.DeviceGroup}
+// @Failure			400		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/groups [post]
+func (h *Handler) CreateDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateDeviceGroupPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateDeviceGroupPayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	groupID, err := h.repo.CreateDeviceGroup(ctx, entities.DeviceGroup{
+		Name:        body.Name,
+		Description: body.Description,
+		Type:        body.Type,
+		Filter:      body.Filter,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateDeviceGroup update device group handler
+// @Summary			Update Device Group.
+// @Description		Update existing Device Group.
+// @Tags			Device Groups
+// @Accept			json
+// @Param 			group_id	path	string									true	"Device Group ID"
+// @Param 			json		body	entities.CreateUpdateDeviceGroupPayload	true	"Device group data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.DeviceGroup}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/groups/{group_id} [put]
+func (h *Handler) UpdateDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateDeviceGroupPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateDeviceGroupPayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateDeviceGroup(ctx, groupID, entities.DeviceGroup{
+		Name:        body.Name,
+		Description: body.Description,
+		Type:        body.Type,
+		Filter:      body.Filter,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteDeviceGroup delete device group handler
+// @Summary			Delete Device Group.
+// @Description		Delete Device Group. Member devices are kept.
+// @Tags			Device Groups
+// @Param			group_id		path			string	 true	"Device Group ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/groups/{group_id} [delete]
+func (h *Handler) DeleteDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	err := h.repo.DeleteDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetDeviceGroup get device group handler
+// @Summary			Get device group by group ID.
+// @Description		Get device group by group ID.
+// @Tags			Device Groups
+// @Param			group_id		path			string	 true	"Device Group ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.DeviceGroup}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/groups/{group_id} [get]
+func (h *Handler) GetDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetDeviceGroupList get device group list handler
+// @Summary			Get list of Device Group.
+// @Description		Get list of Device Group.
+// @Tags			Device Groups
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching device group by name or description"	example(north)
+// @Success			200 			{object}		util.Response{data=[]entities.DeviceGroup}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/groups [get]
+func (h *Handler) GetDeviceGroupList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetDeviceGroupListParams{
+		Search: q.Get("search"),
+		Sort:   q.Get("sort"),
+		Limit:  count,
+		Offset: (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetDeviceGroupList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// AddDeviceGroupMembers add device group members handler
+// @Summary			Add Devices to a static Device Group.
+// @Description		Add Devices to a static Device Group. Unknown device IDs are ignored.
+// @Tags			Device Groups
+// @Accept			json
+// @Param 			group_id	path	string								true	"Device Group ID"
+// @Param 			json		body	entities.DeviceGroupMembersPayload	true	"Device IDs"
+// @Produce			json
+// @Success			200		{object}	util.Response
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			422		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/groups/{group_id}/devices [post]
+func (h *Handler) AddDeviceGroupMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	var body entities.DeviceGroupMembersPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	group, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if group.Type != entities.DEVICE_GROUP_TYPE_STATIC {
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.JSON(w, r, resp.Set("members of a dynamic group are defined by its filter", nil))
+		return
+	}
+
+	err = h.repo.AddDeviceGroupMembers(ctx, groupID, body.DeviceIDs)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// RemoveDeviceGroupMember remove device group member handler
+// @Summary			Remove Device from a static Device Group.
+// @Description		Remove Device from a static Device Group.
+// @Tags			Device Groups
+// @Param			group_id		path			string	 true	"Device Group ID"
+// @Param			device_id		path			string	 true	"Device ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			422				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/groups/{group_id}/devices/{device_id} [delete]
+func (h *Handler) RemoveDeviceGroupMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	deviceID := chi.URLParam(r, "device_id")
+	if groupID == "" || deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	group, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if group.Type != entities.DEVICE_GROUP_TYPE_STATIC {
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.JSON(w, r, resp.Set("members of a dynamic group are defined by its filter", nil))
+		return
+	}
+
+	err = h.repo.RemoveDeviceGroupMember(ctx, groupID, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetDeviceGroupDevices get device group devices handler
+// @Summary			Get Devices of a Device Group.
+// @Description		Get Devices of a Device Group.
+// @Tags			Device Groups
+// @Produce			json
+// @Param			group_id		path			string	 true	"Device Group ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Success			200 			{object}		util.Response{data=[]entities.Device}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/groups/{group_id}/devices [get]
+func (h *Handler) GetDeviceGroupDevices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	group, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := util.DeviceGroupListParams(group)
+	params.Sort = q.Get("sort")
+	params.Limit = count
+	params.Offset = (page - 1) * count
+
+	results, total, err := h.repo.GetDeviceList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetDeviceGroupSensors get device group sensors handler
+// @Summary			Get Sensors across a Device Group.
+// @Description		Get Sensors of every Device of a Device Group.
+// @Tags			Device Groups
+// @Produce			json
+// @Param			group_id		path			string	 true	"Device Group ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching sensors by name or description"		example(soil)
+// @Success			200 			{object}		util.Response{data=[]entities.Sensor}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/groups/{group_id}/sensors [get]
+func (h *Handler) GetDeviceGroupSensors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	group, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	deviceIDs, err := h.repo.GetDeviceIDList(ctx, util.DeviceGroupListParams(group))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetSensorListParams{
+		DeviceIDs: deviceIDs,
+		Search:    q.Get("search"),
+		Sort:      q.Get("sort"),
+		Limit:     count,
+		Offset:    (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetSensorList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// UpdateDeviceGroupStatus update device group status handler
+// @Summary			Change status of every Device of a Device Group.
+// @Description		Change status of every Device of a Device Group.
+// @Tags			Device Groups
+// @Accept			json
+// @Param 			group_id	path	string								true	"Device Group ID"
+// @Param 			json		body	entities.DeviceGroupStatusPayload	true	"Device status"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.BulkUpdateResult}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/groups/{group_id}/status [put]
+func (h *Handler) UpdateDeviceGroupStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	var body entities.DeviceGroupStatusPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	group, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	deviceIDs, err := h.repo.GetDeviceIDList(ctx, util.DeviceGroupListParams(group))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	updated, err := h.repo.UpdateDevicesStatus(ctx, deviceIDs, body.Status)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", entities.BulkUpdateResult{Updated: updated}))
+}
+
+// GetDeviceGroupLatestReadings get device group latest readings handler
+// @Summary			Get latest readings across a Device Group.
+// @Description		Get the latest reading of every Sensor of every Device of a Device Group.
+// @Tags			Device Groups
+// @Param			group_id		path			string	 true	"Device Group ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.LatestSensorReading}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/groups/{group_id}/readings/latest [get]
+func (h *Handler) GetDeviceGroupLatestReadings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	groupID := chi.URLParam(r, "group_id")
+	if groupID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device group not found", nil))
+		return
+	}
+
+	group, err := h.repo.GetDeviceGroup(ctx, groupID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	deviceIDs, err := h.repo.GetDeviceIDList(ctx, util.DeviceGroupListParams(group))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	results, err := h.repo.GetLatestSensorReadings(ctx, deviceIDs)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// validateDeviceGroupPayload validates the payload and the label selector of a dynamic group filter.
+func (h *Handler) validateDeviceGroupPayload(body entities.CreateUpdateDeviceGroupPayload) []string {
+	err := h.validate.Struct(body)
+	if err != nil {
+		return util.ParseValidatorErr(err)
+	}
+
+	if body.Type == entities.DEVICE_GROUP_TYPE_DYNAMIC {
+		_, errs := util.ParseLabelSelector(body.Filter.Selector)
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,363 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateMaintenanceWindow create maintenance window handler
+// @Summary			Create Maintenance Window.
+// @Description		Create new Maintenance Window for a device or a device group, one-off or recurring (daily/weekly, UTC).
+// @Tags			Maintenance Windows
+// @Accept			json
+// @Produce			json
+// @Param 			json	body		entities.CreateUpdateMaintenanceWindowPayload	true	"Maintenance Window data"
+// @Success			201		{object}	util.Response{data=entities.MaintenanceWindow}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/maintenance-windows [post]
+func (h *Handler) CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateMaintenanceWindowPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateMaintenanceWindowPayload(&body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	err = h.checkMaintenanceWindowTarget(ctx, body)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	windowID, err := h.repo.CreateMaintenanceWindow(ctx, entities.MaintenanceWindow{
+		DeviceID:        body.DeviceID,
+		GroupID:         body.GroupID,
+		Name:            body.Name,
+		Description:     body.Description,
+		StartsAt:        body.StartsAt,
+		EndsAt:          body.EndsAt,
+		Recurrence:      body.Recurrence,
+		RecurrenceUntil: body.RecurrenceUntil,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetMaintenanceWindow(ctx, windowID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateMaintenanceWindow update maintenance window handler
+// @Summary			Update Maintenance Window.
+// @Description		Update existing Maintenance Window. Readings already stored keep their maintenance tag.
+// @Tags			Maintenance Windows
+// @Accept			json
+// @Param 			window_id	path	string							true	"Maintenance Window ID"
+// @Param 			json		body	entities.CreateUpdateMaintenanceWindowPayload	true	"Maintenance Window data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.MaintenanceWindow}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/maintenance-windows/{window_id} [put]
+func (h *Handler) UpdateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	windowID := chi.URLParam(r, "window_id")
+	if windowID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("maintenance window not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateMaintenanceWindowPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateMaintenanceWindowPayload(&body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	err = h.checkMaintenanceWindowTarget(ctx, body)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	_, err = h.repo.GetMaintenanceWindow(ctx, windowID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateMaintenanceWindow(ctx, windowID, entities.MaintenanceWindow{
+		DeviceID:        body.DeviceID,
+		GroupID:         body.GroupID,
+		Name:            body.Name,
+		Description:     body.Description,
+		StartsAt:        body.StartsAt,
+		EndsAt:          body.EndsAt,
+		Recurrence:      body.Recurrence,
+		RecurrenceUntil: body.RecurrenceUntil,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetMaintenanceWindow(ctx, windowID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteMaintenanceWindow delete maintenance window handler
+// @Summary			Delete Maintenance Window.
+// @Description		Delete Maintenance Window. Readings already stored keep their maintenance tag.
+// @Tags			Maintenance Windows
+// @Param			window_id		path			string	 true	"Maintenance Window ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/maintenance-windows/{window_id} [delete]
+func (h *Handler) DeleteMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	windowID := chi.URLParam(r, "window_id")
+	if windowID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("maintenance window not found", nil))
+		return
+	}
+
+	err := h.repo.DeleteMaintenanceWindow(ctx, windowID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetMaintenanceWindow get maintenance window handler
+// @Summary			Get maintenance window by maintenance window ID.
+// @Description		Get maintenance window by maintenance window ID.
+// @Tags			Maintenance Windows
+// @Param			window_id		path			string	 true	"Maintenance Window ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.MaintenanceWindow}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/maintenance-windows/{window_id} [get]
+func (h *Handler) GetMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	windowID := chi.URLParam(r, "window_id")
+	if windowID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("maintenance window not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetMaintenanceWindow(ctx, windowID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetMaintenanceWindowList get maintenance window list handler
+// @Summary			Get list of Maintenance Window.
+// @Description		Get list of Maintenance Window.
+// @Tags			Maintenance Windows
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/starts_at/ends_at/created_at/updated_at). For desc order, use prefix '-'"	example(-starts_at)
+// @Param			device_id		query			string	 false	"Windows of the device itself"
+// @Param			group_id		query			string	 false	"Windows of the device group"
+// @Success			200 			{object}		util.Response{data=[]entities.MaintenanceWindow}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/maintenance-windows [get]
+func (h *Handler) GetMaintenanceWindowList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetMaintenanceWindowListParams{
+		DeviceID: q.Get("device_id"),
+		GroupID:  q.Get("group_id"),
+		Sort:     q.Get("sort"),
+		Limit:    count,
+		Offset:   (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetMaintenanceWindowList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetDeviceMaintenanceWindows get device maintenance windows handler
+// @Summary			Get Maintenance Windows of a Device.
+// @Description		Get the Maintenance Windows of the device and of every group it belongs to, with their active state.
+// @Tags			Maintenance Windows
+// @Param			device_id		path			string	 true	"Device ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.MaintenanceWindow}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/maintenance-windows [get]
+func (h *Handler) GetDeviceMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	results, err := h.repo.GetDeviceMaintenanceWindows(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+func (h *Handler) validateMaintenanceWindowPayload(body *entities.CreateUpdateMaintenanceWindowPayload) []string {
+	err := h.validate.Struct(body)
+	if err != nil {
+		return util.ParseValidatorErr(err)
+	}
+
+	if body.Recurrence == "" {
+		body.Recurrence = entities.MAINTENANCE_RECURRENCE_NONE
+	}
+
+	period := body.Recurrence.Period()
+	if period == 0 {
+		body.RecurrenceUntil = nil
+		return nil
+	}
+
+	errs := []string{}
+	if body.EndsAt.Sub(body.StartsAt) >= period {
+		errs = append(errs, fmt.Sprintf("EndsAt must be less than %s after StartsAt for %s recurrence", period, body.Recurrence))
+	}
+	if body.RecurrenceUntil != nil && !body.RecurrenceUntil.After(body.EndsAt) {
+		errs = append(errs, "RecurrenceUntil must be after EndsAt")
+	}
+
+	return errs
+}
+
+// checkMaintenanceWindowTarget checks the device or group of a window exists.
+func (h *Handler) checkMaintenanceWindowTarget(ctx context.Context, body entities.CreateUpdateMaintenanceWindowPayload) error {
+	if body.DeviceID != nil {
+		_, err := h.repo.GetDevice(ctx, *body.DeviceID)
+		return err
+	}
+
+	_, err := h.repo.GetDeviceGroup(ctx, *body.GroupID)
+	return err
+}
+
+// inMaintenance reports whether any of the windows is active at the given time.
+func inMaintenance(windows []*entities.MaintenanceWindow, at time.Time) bool {
+	for _, window := range windows {
+		if window.ActiveAt(at) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,242 @@
+package v1
+
+import (
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateOrganization create organization handler
+// @Summary			Create Organization.
+// @Description		Create new Organization.
+// @Tags			Organizations
+// @Accept			json
+// @Produce			json
+// @Param 			json	body		entities.CreateUpdateOrganizationPayload	true	"Organization data"
+// @Success			201		{object}	util.Response{data=entities.Organization}
+// @Failure			400		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/organizations [post]
+func (h *Handler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateOrganizationPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	organizationID, err := h.repo.CreateOrganization(ctx, entities.Organization{
+		Name:        body.Name,
+		Description: body.Description,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateOrganization update organization handler
+// @Summary			Update Organization.
+// @Description		Update existing Organization.
+// @Tags			Organizations
+// @Accept			json
+// @Param 			organization_id	path	string							true	"Organization ID"
+// @Param 			json		body	entities.CreateUpdateOrganizationPayload	true	"Organization data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.Organization}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/organizations/{organization_id} [put]
+func (h *Handler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	organizationID := chi.URLParam(r, "organization_id")
+	if organizationID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("organization not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateOrganizationPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateOrganization(ctx, organizationID, entities.Organization{
+		Name:        body.Name,
+		Description: body.Description,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteOrganization delete organization handler
+// @Summary			Delete Organization.
+// @Description		Delete Organization. Organizations that still have sites cannot be deleted.
+// @Tags			Organizations
+// @Param			organization_id		path			string	 true	"Organization ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			409				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/organizations/{organization_id} [delete]
+func (h *Handler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	organizationID := chi.URLParam(r, "organization_id")
+	if organizationID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("organization not found", nil))
+		return
+	}
+
+	err := h.repo.DeleteOrganization(ctx, organizationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetOrganization get organization handler
+// @Summary			Get organization by organization ID.
+// @Description		Get organization by organization ID.
+// @Tags			Organizations
+// @Param			organization_id		path			string	 true	"Organization ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.Organization}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/organizations/{organization_id} [get]
+func (h *Handler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	organizationID := chi.URLParam(r, "organization_id")
+	if organizationID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("organization not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetOrganizationList get organization list handler
+// @Summary			Get list of Organization.
+// @Description		Get list of Organization.
+// @Tags			Organizations
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching organization by name or description"	example(mertani)
+// @Success			200 			{object}		util.Response{data=[]entities.Organization}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/organizations [get]
+func (h *Handler) GetOrganizationList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetOrganizationListParams{
+		Search: q.Get("search"),
+		Sort:   q.Get("sort"),
+		Limit:  count,
+		Offset: (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetOrganizationList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
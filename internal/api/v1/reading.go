@@ -0,0 +1,325 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateSensorReadings create sensor readings handler
+// @Summary			Create Sensor Readings.
+// @Description		Store one or more readings of a sensor. recorded_at defaults to the current time.
+// @Description		Readings recorded during a maintenance window of the sensor device are tagged with maintenance=true.
+// @Description		The sensor calibration in effect at recorded_at is applied, the response has the calibrated values.
+// @Description		Readings of virtual sensors referencing the sensor are computed at the same recorded_at.
+// @Description		Readings can not be sent for virtual sensors.
+// @Description		The readings are scored by the anomaly detectors of their sensors, then the alert rules of the sensor
+// @Description		and of the virtual sensors are evaluated with the new readings.
+// @Tags			Sensor Readings
+// @Accept			json
+// @Produce			json
+// @Param 			sensor_id	path		string								true	"Sensor ID"
+// @Param 			json		body		entities.CreateSensorReadingsPayload	true	"Readings"
+// @Success			201			{object}	util.Response{data=[]entities.SensorReading}
+// @Failure			400			{object}	util.Response
+// @Failure			404			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/sensors/{sensor_id}/readings [post]
+func (h *Handler) CreateSensorReadings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	var body entities.CreateSensorReadingsPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	sensor, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	if sensor.Virtual {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("readings of a virtual sensor are computed from its expression", nil))
+		return
+	}
+
+	windows, err := h.repo.GetDeviceMaintenanceWindows(ctx, sensor.DeviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	nowUTC := time.Now().UTC()
+	readings := []entities.SensorReading{}
+	for _, v := range body.Readings {
+		recordedAt := v.RecordedAt
+		if recordedAt.IsZero() {
+			recordedAt = nowUTC
+		}
+
+		readings = append(readings, entities.SensorReading{
+			SensorID:    sensorID,
+			Value:       v.Value,
+			Maintenance: inMaintenance(windows, recordedAt),
+			RecordedAt:  recordedAt,
+		})
+	}
+
+	results, err := h.repo.CreateSensorReadings(ctx, readings)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	h.anomalies.DetectReadings(ctx, results)
+	h.alerts.EvaluateReadings(ctx, results)
+
+	// results also have the computed readings of virtual sensors, only the sent ones are returned
+	created := []*entities.SensorReading{}
+	for _, v := range results {
+		if v.SensorID == sensorID {
+			created = append(created, v)
+		}
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", created))
+}
+
+// GetSensorReadingList get sensor reading list handler
+// @Summary			Get list of Sensor Readings.
+// @Description		Get list of Sensor Readings.
+// @Tags			Sensor Readings
+// @Produce			json
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: recorded_at/value). For desc order, use prefix '-'"	example(-recorded_at)
+// @Param			from			query			string	 false	"Readings recorded at or after (RFC3339)"					example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Readings recorded before (RFC3339)"						example(2024-03-02T00:00:00Z)
+// @Param			exclude_maintenance	query		bool	 false	"Leave out readings recorded during maintenance"
+// @Param			raw				query			bool	 false	"Return the values as sent instead of the calibrated values"
+// @Success			200 			{object}		util.Response{data=[]entities.SensorReading}
+// @Failure			400				{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/readings [get]
+func (h *Handler) GetSensorReadingList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	from, to, errs := parseTimeRange(q.Get("from"), q.Get("to"))
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	params := entities.GetSensorReadingListParams{
+		SensorID:           sensorID,
+		From:               from,
+		To:                 to,
+		ExcludeMaintenance: q.Get("exclude_maintenance") == "true",
+		Raw:                q.Get("raw") == "true",
+		Sort:               q.Get("sort"),
+		Limit:              count,
+		Offset:             (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetSensorReadingList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetDeviceReadingList get device reading list handler
+// @Summary			Get list of Sensor Readings of a Device.
+// @Description		Get readings recorded on the device. A reading belongs to the device its sensor was attached to when it was recorded.
+// @Tags			Sensor Readings
+// @Produce			json
+// @Param			device_id		path			string	 true	"Device ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: recorded_at/value). For desc order, use prefix '-'"	example(-recorded_at)
+// @Param			from			query			string	 false	"Readings recorded at or after (RFC3339)"					example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Readings recorded before (RFC3339)"						example(2024-03-02T00:00:00Z)
+// @Param			exclude_maintenance	query		bool	 false	"Leave out readings recorded during maintenance"
+// @Param			raw				query			bool	 false	"Return the values as sent instead of the calibrated values"
+// @Success			200 			{object}		util.Response{data=[]entities.SensorReading}
+// @Failure			400				{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/readings [get]
+func (h *Handler) GetDeviceReadingList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	from, to, errs := parseTimeRange(q.Get("from"), q.Get("to"))
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	params := entities.GetSensorReadingListParams{
+		DeviceID:           deviceID,
+		From:               from,
+		To:                 to,
+		ExcludeMaintenance: q.Get("exclude_maintenance") == "true",
+		Raw:                q.Get("raw") == "true",
+		Sort:               q.Get("sort"),
+		Limit:              count,
+		Offset:             (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetSensorReadingList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetReadingAggregates get reading aggregates handler
+// @Summary			Get Sensor Reading aggregates by location.
+// @Description		Roll readings up by zone, site or organization, per sensor type. Readings of devices without a zone are left out.
+// @Tags			Sensor Readings
+// @Produce			json
+// @Param			group_by		query			string	 false	"Location level (value: zone/site/organization, default zone)"	example(site)
+// @Param			organization_id	query			string	 false	"Only readings of devices in the organization"
+// @Param			site_id			query			string	 false	"Only readings of devices in the site"
+// @Param			zone_id			query			string	 false	"Only readings of devices in the zone"
+// @Param			sensor_type		query			string	 false	"Only readings of the sensor type"							example(air_temperature)
+// @Param			from			query			string	 false	"Readings recorded at or after (RFC3339)"					example(2024-03-01T00:00:00Z)
+// @Param			to				query			string	 false	"Readings recorded before (RFC3339)"						example(2024-03-02T00:00:00Z)
+// @Param			exclude_maintenance	query		bool	 false	"Leave out readings recorded during maintenance"
+// @Param			raw				query			bool	 false	"Aggregate the values as sent instead of the calibrated values"
+// @Success			200 			{object}		util.Response{data=[]entities.ReadingAggregate}
+// @Failure			400				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/readings/aggregate [get]
+func (h *Handler) GetReadingAggregates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+
+	from, to, errs := parseTimeRange(q.Get("from"), q.Get("to"))
+
+	groupBy := entities.AggregateGroupBy(q.Get("group_by"))
+	switch groupBy {
+	case "":
+		groupBy = entities.AGGREGATE_GROUP_BY_ZONE
+	case entities.AGGREGATE_GROUP_BY_ZONE, entities.AGGREGATE_GROUP_BY_SITE, entities.AGGREGATE_GROUP_BY_ORGANIZATION:
+	default:
+		errs = append(errs, fmt.Sprintf("group_by must be one of zone, site, organization: %s", groupBy))
+	}
+
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	params := entities.GetReadingAggregateParams{
+		GroupBy:            groupBy,
+		Location:           locationFilter(q),
+		SensorType:         q.Get("sensor_type"),
+		From:               from,
+		To:                 to,
+		ExcludeMaintenance: q.Get("exclude_maintenance") == "true",
+		Raw:                q.Get("raw") == "true",
+	}
+
+	results, err := h.repo.GetReadingAggregates(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
@@ -1,10 +1,15 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"go-api/internal/entities"
+	"go-api/pkg/expr"
 	"go-api/pkg/util"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -27,7 +32,8 @@ func (h *Handler) GetSensorTypes(w http.ResponseWriter, r *http.Request) {
 
 // CreateSensor create sensor handler
 // @Summary			Create Sensor.
-// @Description		Create new Sensor.
+// @Description		Create new Sensor. A sensor with an expression is virtual, its readings are computed from the sensors
+// @Description		referenced in the expression by ID in braces, e.g. ({sensor_id_1} + {sensor_id_2}) / 2.
 // @Tags			Sensors
 // @Accept			json
 // @Param 			json	body		entities.CreateSensorPayload	true	"Sensor data"
@@ -64,11 +70,29 @@ func (h *Handler) CreateSensor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if body.Expression != "" {
+		errs, err := h.validateSensorExpression(ctx, "", body.Expression)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+		if len(errs) > 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+			return
+		}
+	}
+
 	sensorID, err := h.repo.CreateSensor(ctx, entities.Sensor{
 		DeviceID:    body.DeviceID,
 		Type:        body.Type,
 		Name:        body.Name,
 		Description: body.Description,
+		Attributes:  body.Attributes,
+		Labels:      body.Labels,
+		Expression:  body.Expression,
 	})
 	if err != nil {
 		status, msg := util.ErrStatusCode(err)
@@ -85,21 +109,25 @@ func (h *Handler) CreateSensor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, result.Version)
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, resp.Set("created", result))
 }
 
 // UpdateSensor update sensor handler
 // @Summary			Update Sensor.
-// @Description		Update existing Sensor.
+// @Description		Update existing Sensor. The expression is required for virtual sensors and not allowed for physical sensors. With If-Match, the update fails with 412 when the sensor changed since its ETag was read.
 // @Tags			Sensors
 // @Accept			json
 // @Param 			sensor_id	path	string							true	"Sensor ID" 	example(96a5ec77-9012-4bf3-b08e-39ef4c07fcce)
+// @Param 			If-Match	header	string							false	"ETag of the sensor" example("3")
 // @Param 			json		body	entities.UpdateSensorPayload	true	"Sensor data"
 // @Produce			json
 // @Success			200		{object}	util.Response{data=entities.Sensor}
+// @Header			200		{string}	ETag	"Version of the sensor"
 // @Failure			400		{object}	util.Response
 // @Failure			404		{object}	util.Response
+// @Failure			412		{object}	util.Response
 // @Failure			500		{object}	util.Response
 // @Router	/v1/sensors/{sensor_id} [put]
 func (h *Handler) UpdateSensor(w http.ResponseWriter, r *http.Request) {
@@ -129,10 +157,42 @@ func (h *Handler) UpdateSensor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sensor, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	errs := []string{}
+	switch {
+	case sensor.Virtual && body.Expression == "":
+		errs = append(errs, "expression is required for virtual sensors")
+	case !sensor.Virtual && body.Expression != "":
+		errs = append(errs, "expression is not allowed for physical sensors")
+	case sensor.Virtual:
+		errs, err = h.validateSensorExpression(ctx, sensorID, body.Expression)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+	}
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
 	err = h.repo.UpdateSensor(ctx, sensorID, entities.Sensor{
 		Name:        body.Name,
 		Description: body.Description,
-	})
+		Attributes:  body.Attributes,
+		Labels:      body.Labels,
+		Expression:  body.Expression,
+	}, ifMatchVersions(r))
 	if err != nil {
 		status, msg := util.ErrStatusCode(err)
 		render.Status(r, status)
@@ -148,18 +208,22 @@ func (h *Handler) UpdateSensor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, result.Version)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp.Set("success", result))
 }
 
 // DeleteSensor delete sensor handler
 // @Summary			Delete Sensor.
-// @Description		Delete Sensor.
+// @Description		Move a sensor to the trash, it can be restored until it is purged. A sensor referenced by virtual sensors can not be deleted.
 // @Tags			Sensors
 // @Param			sensor_id		path			string	 true	"Sensor ID" example(96a5ec77-9012-4bf3-b08e-39ef4c07fcce)
+// @Param			If-Match		header			string	 false	"ETag of the sensor, the delete fails with 412 when it changed" example("3")
 // @Produce			json
 // @Success			200 			{object}		util.Response
 // @Failure			404				{object}		util.Response
+// @Failure			409				{object}		util.Response
+// @Failure			412				{object}		util.Response
 // @Failure			500				{object}		util.Response
 // @Router	/v1/sensors/{sensor_id} [delete]
 func (h *Handler) DeleteSensor(w http.ResponseWriter, r *http.Request) {
@@ -173,7 +237,7 @@ func (h *Handler) DeleteSensor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.repo.DeleteSensor(ctx, sensorID)
+	err := h.repo.DeleteSensor(ctx, sensorID, ifMatchVersions(r))
 	if err != nil {
 		status, msg := util.ErrStatusCode(err)
 		render.Status(r, status)
@@ -185,6 +249,49 @@ func (h *Handler) DeleteSensor(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, resp.Set("success", nil))
 }
 
+// RestoreSensor restore sensor handler
+// @Summary			Restore Sensor.
+// @Description		Take a sensor out of the trash. Its device must not be in the trash and a virtual sensor needs the sensors of its expression.
+// @Tags			Sensors
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.Sensor}
+// @Failure			404				{object}		util.Response
+// @Failure			409				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/restore [post]
+func (h *Handler) RestoreSensor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	err := h.repo.RestoreSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	setETag(w, result.Version)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
 // GetSensor get sensor handler
 // @Summary			Get sensor by sensor ID.
 // @Description		Get sensor by sensor ID.
@@ -192,6 +299,7 @@ func (h *Handler) DeleteSensor(w http.ResponseWriter, r *http.Request) {
 // @Param			sensor_id		path			string	 true	"Sensor ID"
 // @Produce			json
 // @Success			200 			{object}		util.Response{data=entities.Sensor}
+// @Header			200				{string}		ETag	"Version of the sensor"
 // @Failure			404				{object}		util.Response
 // @Failure			500				{object}		util.Response
 // @Router	/v1/sensors/{sensor_id} [get]
@@ -214,6 +322,7 @@ func (h *Handler) GetSensor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, result.Version)
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp.Set("success", result))
 }
@@ -221,14 +330,22 @@ func (h *Handler) GetSensor(w http.ResponseWriter, r *http.Request) {
 // GetSensorList get sensor list handler
 // @Summary			Get list of Sensor.
 // @Description		Get list of Sensor.
+// @Description		Filter by attributes with attr.<key>=<value> query params, e.g. attr.crop=rice.
 // @Tags			Sensors
 // @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
 // @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
 // @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
 // @Param			device_id		query			string	 false	"Filter sensors by device ID"					 			example(96a5ec77-9012-4bf3-b08e-39ef4c07fcce)
 // @Param			search			query			string	 false	"Keyword for searching sensors by name or description"		example(soil)
+// @Param			attr.crop		query			string	 false	"Filter by attribute value, any attribute key can be used"	example(rice)
+// @Param			selector		query			string	 false	"Label selector (=, !=, in, notin, key, !key)"				example(site=north,env!=test)
+// @Param			organization_id	query			string	 false	"Filter by organization of the sensor device"
+// @Param			site_id			query			string	 false	"Filter by site of the sensor device"
+// @Param			zone_id			query			string	 false	"Filter by zone of the sensor device"
+// @Param			virtual			query			bool	 false	"Only virtual (true) or physical (false) sensors"
 // @Produce			json
 // @Success			200 			{object}		util.Response{data=[]entities.Sensor}
+// @Failure			400				{object}		util.Response
 // @Failure			500				{object}		util.Response
 // @Router	/v1/sensors [get]
 func (h *Handler) GetSensorList(w http.ResponseWriter, r *http.Request) {
@@ -238,12 +355,26 @@ func (h *Handler) GetSensorList(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	page, count := util.Pagination(q.Get("page"), q.Get("count"))
 
+	selector, errs := util.ParseLabelSelector(q.Get("selector"))
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid selector", nil).AddErrValidation(errs))
+		return
+	}
+
 	params := entities.GetSensorListParams{
-		DeviceID: q.Get("device_id"),
-		Search:   q.Get("search"),
-		Sort:     q.Get("sort"),
-		Limit:    count,
-		Offset:   (page - 1) * count,
+		DeviceID:   q.Get("device_id"),
+		Search:     q.Get("search"),
+		Attributes: util.AttributeFilters(q),
+		Selector:   selector,
+		Location:   locationFilter(q),
+		Sort:       q.Get("sort"),
+		Limit:      count,
+		Offset:     (page - 1) * count,
+	}
+	if virtual := q.Get("virtual"); virtual != "" {
+		isVirtual := virtual == "true"
+		params.Virtual = &isVirtual
 	}
 
 	results, total, err := h.repo.GetSensorList(ctx, params)
@@ -258,3 +389,169 @@ func (h *Handler) GetSensorList(w http.ResponseWriter, r *http.Request) {
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, resp.Set("success", results))
 }
+
+// MoveSensor move sensor handler
+// @Summary			Move a Sensor to another Device.
+// @Description		Attach the sensor to another device from moved_at on (default now).
+// @Description		Readings recorded before moved_at stay attributed to the previous device.
+// @Tags			Sensors
+// @Accept			json
+// @Produce			json
+// @Param			sensor_id		path			string						true	"Sensor ID"
+// @Param 			json			body			entities.MoveSensorPayload	true	"Target device"
+// @Success			200 			{object}		util.Response{data=entities.Sensor}
+// @Failure			400				{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/move [post]
+func (h *Handler) MoveSensor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	var body entities.MoveSensorPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	nowUTC := time.Now().UTC()
+	movedAt := nowUTC
+	if body.MovedAt != nil {
+		movedAt = body.MovedAt.UTC()
+	}
+	if movedAt.After(nowUTC) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation([]string{"moved_at must not be in the future"}))
+		return
+	}
+
+	_, err = h.repo.GetDevice(ctx, body.DeviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.MoveSensor(ctx, sensorID, body.DeviceID, movedAt)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	setETag(w, result.Version)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetSensorDeviceHistory get sensor device history handler
+// @Summary			Get Device history of a Sensor.
+// @Description		Get every device the sensor was attached to, the current one first.
+// @Tags			Sensors
+// @Produce			json
+// @Param			sensor_id		path			string	 true	"Sensor ID"
+// @Success			200 			{object}		util.Response{data=[]entities.SensorDeviceAssociation}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sensors/{sensor_id}/history [get]
+func (h *Handler) GetSensorDeviceHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	sensorID := chi.URLParam(r, "sensor_id")
+	if sensorID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("sensor not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetSensor(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	results, err := h.repo.GetSensorDeviceHistory(ctx, sensorID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// validateSensorExpression reports syntax errors, unknown sensor references and reference cycles of the
+// expression of a virtual sensor. sensorID is empty for a new sensor, which can not be part of a cycle yet.
+func (h *Handler) validateSensorExpression(ctx context.Context, sensorID, expression string) ([]string, error) {
+	e, err := expr.Parse(expression)
+	if err != nil {
+		return []string{fmt.Sprintf("expression: %s", err.Error())}, nil
+	}
+
+	refs := e.Refs()
+	if len(refs) == 0 {
+		return []string{"expression must reference at least one sensor"}, nil
+	}
+
+	existing, err := h.repo.GetExistingSensorIDs(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := []string{}
+	found := map[string]bool{}
+	for _, id := range existing {
+		found[id] = true
+	}
+	for _, ref := range refs {
+		if !found[ref] && ref != sensorID {
+			errs = append(errs, fmt.Sprintf("expression references unknown sensor %s", ref))
+		}
+	}
+
+	if sensorID != "" {
+		deps, err := h.repo.GetVirtualSensorRefs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		deps[sensorID] = refs
+
+		if cycle := expr.FindCycle(sensorID, deps); cycle != nil {
+			errs = append(errs, fmt.Sprintf("expression has a reference cycle: %s", strings.Join(cycle, " -> ")))
+		}
+	}
+
+	return errs, nil
+}
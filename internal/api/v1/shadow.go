@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/util"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetDeviceShadow get device shadow handler
+// @Summary			Get device shadow.
+// @Description		Get desired and reported configuration of a device, with the computed delta.
+// @Tags			Device Shadows
+// @Param			device_id		path			string	 true	"Device ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.DeviceShadow}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/shadow [get]
+func (h *Handler) GetDeviceShadow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceShadow(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetDeviceShadowDelta get device shadow delta handler
+// @Summary			Get device shadow delta.
+// @Description		Get the desired configuration the device still has to apply.
+// @Description		With wait, the request is held until the shadow version is newer than the given version.
+// @Tags			Device Shadows
+// @Param			device_id		path			string	 true	"Device ID"
+// @Param			version			query			int		 false	"Last shadow version known by the device"					example(3)
+// @Param			wait			query			int		 false	"Long-poll wait in seconds (max 50)"							example(30)
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.DeviceShadowDelta}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/devices/{device_id}/shadow/delta [get]
+func (h *Handler) GetDeviceShadowDelta(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	q := r.URL.Query()
+	knownVersion, _ := strconv.ParseInt(q.Get("version"), 10, 64)
+	wait := longPollWait(q.Get("wait"))
+
+	_, err := h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	// subscribe before reading, so an update between the read and the wait is not missed
+	changes, unsubscribe := h.broker.Subscribe(events.ForResource(events.DEVICE_SHADOW_UPDATED, deviceID))
+	defer unsubscribe()
+
+	result, err := h.repo.GetDeviceShadow(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	if wait > 0 && result.Version <= knownVersion && waitForEvent(ctx, changes, wait) {
+		result, err = h.repo.GetDeviceShadow(ctx, deviceID)
+		if err != nil {
+			status, msg := util.ErrStatusCode(err)
+			render.Status(r, status)
+			render.JSON(w, r, resp.Set(msg, nil))
+			return
+		}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", entities.DeviceShadowDelta{
+		DeviceID: result.DeviceID,
+		Delta:    result.Delta,
+		Version:  result.Version,
+	}))
+}
+
+// UpdateDeviceShadowDesired update device shadow desired state handler
+// @Summary			Update device shadow desired state.
+// @Description		Merge a partial desired state into the device shadow. Keys set to null are removed.
+// @Description		When version is given, the update is rejected with 412 if the shadow has changed since. Version 0 only updates a shadow that was never written.
+// @Tags			Device Shadows
+// @Accept			json
+// @Param 			device_id	path	string								true	"Device ID" example(01HQSH92SNYQVCBDSD38XNBRYM)
+// @Param 			json		body	entities.UpdateDeviceShadowPayload	true	"Desired state"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.DeviceShadow}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			412		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/devices/{device_id}/shadow/desired [put]
+func (h *Handler) UpdateDeviceShadowDesired(w http.ResponseWriter, r *http.Request) {
+	h.updateDeviceShadow(w, r, entities.SHADOW_SECTION_DESIRED)
+}
+
+// UpdateDeviceShadowReported update device shadow reported state handler
+// @Summary			Update device shadow reported state.
+// @Description		Merge a partial reported state into the device shadow. Keys set to null are removed.
+// @Description		When version is given, the update is rejected with 412 if the shadow has changed since. Version 0 only updates a shadow that was never written.
+// @Tags			Device Shadows
+// @Accept			json
+// @Param 			device_id	path	string								true	"Device ID" example(01HQSH92SNYQVCBDSD38XNBRYM)
+// @Param 			json		body	entities.UpdateDeviceShadowPayload	true	"Reported state"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.DeviceShadow}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			412		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/devices/{device_id}/shadow/reported [put]
+func (h *Handler) UpdateDeviceShadowReported(w http.ResponseWriter, r *http.Request) {
+	h.updateDeviceShadow(w, r, entities.SHADOW_SECTION_REPORTED)
+}
+
+func (h *Handler) updateDeviceShadow(w http.ResponseWriter, r *http.Request, section entities.ShadowSection) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	deviceID := chi.URLParam(r, "device_id")
+	if deviceID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device not found", nil))
+		return
+	}
+
+	var body entities.UpdateDeviceShadowPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetDevice(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateDeviceShadow(ctx, deviceID, section, body)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceShadow(ctx, deviceID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	h.broker.Publish(events.Event{
+		Type:       events.DEVICE_SHADOW_UPDATED,
+		ResourceID: deviceID,
+		Data:       result,
+	})
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
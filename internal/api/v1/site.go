@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateSite create site handler
+// @Summary			Create Site.
+// @Description		Create new Site.
+// @Tags			Sites
+// @Accept			json
+// @Produce			json
+// @Param 			json	body		entities.CreateUpdateSitePayload	true	"Site data"
+// @Success			201		{object}	util.Response{data=entities.Site}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/sites [post]
+func (h *Handler) CreateSite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateSitePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetOrganization(ctx, body.OrganizationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	siteID, err := h.repo.CreateSite(ctx, entities.Site{
+		OrganizationID: body.OrganizationID,
+		Name:           body.Name,
+		Description:    body.Description,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetSite(ctx, siteID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateSite update site handler
+// @Summary			Update Site.
+// @Description		Update existing Site.
+// @Tags			Sites
+// @Accept			json
+// @Param 			site_id	path	string							true	"Site ID"
+// @Param 			json		body	entities.CreateUpdateSitePayload	true	"Site data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.Site}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/sites/{site_id} [put]
+func (h *Handler) UpdateSite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	siteID := chi.URLParam(r, "site_id")
+	if siteID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("site not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateSitePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetSite(ctx, siteID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	_, err = h.repo.GetOrganization(ctx, body.OrganizationID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateSite(ctx, siteID, entities.Site{
+		OrganizationID: body.OrganizationID,
+		Name:           body.Name,
+		Description:    body.Description,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetSite(ctx, siteID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteSite delete site handler
+// @Summary			Delete Site.
+// @Description		Delete Site. Sites that still have zones cannot be deleted.
+// @Tags			Sites
+// @Param			site_id		path			string	 true	"Site ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			409				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sites/{site_id} [delete]
+func (h *Handler) DeleteSite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	siteID := chi.URLParam(r, "site_id")
+	if siteID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("site not found", nil))
+		return
+	}
+
+	err := h.repo.DeleteSite(ctx, siteID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetSite get site handler
+// @Summary			Get site by site ID.
+// @Description		Get site by site ID.
+// @Tags			Sites
+// @Param			site_id		path			string	 true	"Site ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.Site}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sites/{site_id} [get]
+func (h *Handler) GetSite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	siteID := chi.URLParam(r, "site_id")
+	if siteID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("site not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetSite(ctx, siteID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetSiteList get site list handler
+// @Summary			Get list of Site.
+// @Description		Get list of Site.
+// @Tags			Sites
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching site by name or description"	example(north)
+// @Param			organization_id	query			string	 false	"Filter by organization ID"
+// @Success			200 			{object}		util.Response{data=[]entities.Site}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/sites [get]
+func (h *Handler) GetSiteList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetSiteListParams{
+		OrganizationID: q.Get("organization_id"),
+		Search:         q.Get("search"),
+		Sort:           q.Get("sort"),
+		Limit:          count,
+		Offset:         (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetSiteList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
@@ -0,0 +1,249 @@
+package v1
+
+import (
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateDeviceTemplate create device template handler
+// @Summary			Create Device Template.
+// @Description		Create new Device Template with default device fields and a sensor list.
+// @Tags			Device Templates
+// @Accept			json
+// @Produce			json
+// @Param 			json	body		entities.CreateUpdateDeviceTemplatePayload	true	"Device Template data"
+// @Success			201		{object}	util.Response{data=entities.DeviceTemplate}
+// @Failure			400		{object}	util.Response
+// @Failure			409		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/templates [post]
+func (h *Handler) CreateDeviceTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateDeviceTemplatePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	templateID, err := h.repo.CreateDeviceTemplate(ctx, entities.DeviceTemplate{
+		Slug:        body.Slug,
+		Name:        body.Name,
+		Description: body.Description,
+		Device:      body.Device,
+		Sensors:     body.Sensors,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceTemplate(ctx, templateID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateDeviceTemplate update device template handler
+// @Summary			Update Device Template.
+// @Description		Update existing Device Template. Devices created from it are not changed.
+// @Tags			Device Templates
+// @Accept			json
+// @Param 			template_id	path	string							true	"Device Template ID"
+// @Param 			json		body	entities.CreateUpdateDeviceTemplatePayload	true	"Device Template data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.DeviceTemplate}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			409		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/templates/{template_id} [put]
+func (h *Handler) UpdateDeviceTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	templateID := chi.URLParam(r, "template_id")
+	if templateID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device template not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateDeviceTemplatePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetDeviceTemplate(ctx, templateID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateDeviceTemplate(ctx, templateID, entities.DeviceTemplate{
+		Slug:        body.Slug,
+		Name:        body.Name,
+		Description: body.Description,
+		Device:      body.Device,
+		Sensors:     body.Sensors,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceTemplate(ctx, templateID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteDeviceTemplate delete device template handler
+// @Summary			Delete Device Template.
+// @Description		Delete Device Template. Devices created from it are kept.
+// @Tags			Device Templates
+// @Param			template_id		path			string	 true	"Device Template ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/templates/{template_id} [delete]
+func (h *Handler) DeleteDeviceTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	templateID := chi.URLParam(r, "template_id")
+	if templateID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device template not found", nil))
+		return
+	}
+
+	err := h.repo.DeleteDeviceTemplate(ctx, templateID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetDeviceTemplate get device template handler
+// @Summary			Get device template by device template ID.
+// @Description		Get device template by device template ID.
+// @Tags			Device Templates
+// @Param			template_id		path			string	 true	"Device Template ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.DeviceTemplate}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/templates/{template_id} [get]
+func (h *Handler) GetDeviceTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	templateID := chi.URLParam(r, "template_id")
+	if templateID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("device template not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetDeviceTemplate(ctx, templateID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetDeviceTemplateList get device template list handler
+// @Summary			Get list of Device Template.
+// @Description		Get list of Device Template.
+// @Tags			Device Templates
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: slug/name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching device template by slug, name or description"	example(weather)
+// @Success			200 			{object}		util.Response{data=[]entities.DeviceTemplate}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/templates [get]
+func (h *Handler) GetDeviceTemplateList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetDeviceTemplateListParams{
+		Search: q.Get("search"),
+		Sort:   q.Get("sort"),
+		Limit:  count,
+		Offset: (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetDeviceTemplateList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// GetTrashList get trash list handler
+// @Summary			Get list of deleted Devices and Sensors.
+// @Description		Get the devices and sensors in the trash, latest deleted first by default. They can be restored until they are purged after the retention.
+// @Tags			Trash
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: type/name/created_at/deleted_at). For desc order, use prefix '-'"	example(-deleted_at)
+// @Param			type			query			string	 false	"Type of the items (value: device/sensor)"					example(device)
+// @Param			search			query			string	 false	"Keyword for searching items by name"						example(raspi)
+// @Success			200 			{object}		util.Response{data=[]entities.TrashItem}
+// @Failure			400				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/trash [get]
+func (h *Handler) GetTrashList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	itemType := entities.TrashItemType(q.Get("type"))
+	switch itemType {
+	case "", entities.TRASH_ITEM_TYPE_DEVICE, entities.TRASH_ITEM_TYPE_SENSOR:
+	default:
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation([]string{fmt.Sprintf("type must be one of device, sensor: %s", itemType)}))
+		return
+	}
+
+	params := entities.GetTrashListParams{
+		Type:   itemType,
+		Search: q.Get("search"),
+		Sort:   q.Get("sort"),
+		Limit:  count,
+		Offset: (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetTrashList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
@@ -0,0 +1,435 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateWebhook create webhook handler
+// @Summary			Create Webhook.
+// @Description		Add a webhook receiving the events matching its filter, `alert.firing`, `alert.*` or `*`. An empty filter receives every event.
+// @Description		Events are POSTed as JSON signed with the secret, see the X-Webhook-Signature header.
+// @Description		Failed deliveries are retried with exponential backoff.
+// @Tags			Webhooks
+// @Accept			json
+// @Produce			json
+// @Param 			json		body		entities.CreateUpdateWebhookPayload	true	"Webhook data"
+// @Success			201			{object}	util.Response{data=entities.Webhook}
+// @Failure			400			{object}	util.Response
+// @Failure			500			{object}	util.Response
+// @Router	/v1/webhooks [post]
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateWebhookPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateWebhookPayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	webhookID, err := h.repo.CreateWebhook(ctx, webhook(body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateWebhook update webhook handler
+// @Summary			Update Webhook.
+// @Description		Update a Webhook. Pending retries are sent to the new url with the new secret.
+// @Tags			Webhooks
+// @Accept			json
+// @Param 			webhook_id	path	string								true	"Webhook ID"
+// @Param 			json		body	entities.CreateUpdateWebhookPayload	true	"Webhook data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.Webhook}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/webhooks/{webhook_id} [put]
+func (h *Handler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	webhookID := chi.URLParam(r, "webhook_id")
+	if webhookID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateWebhookPayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	errs := h.validateWebhookPayload(body)
+	if len(errs) > 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateWebhook(ctx, webhookID, webhook(body))
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteWebhook delete webhook handler
+// @Summary			Delete Webhook.
+// @Description		Delete a Webhook with its delivery log, pending retries are dropped.
+// @Tags			Webhooks
+// @Param			webhook_id		path			string	 true	"Webhook ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/webhooks/{webhook_id} [delete]
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	webhookID := chi.URLParam(r, "webhook_id")
+	if webhookID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.DeleteWebhook(ctx, webhookID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetWebhook get webhook handler
+// @Summary			Get Webhook by ID.
+// @Description		Get Webhook by ID. The secret is not returned.
+// @Tags			Webhooks
+// @Param			webhook_id		path			string	 true	"Webhook ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.Webhook}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/webhooks/{webhook_id} [get]
+func (h *Handler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	webhookID := chi.URLParam(r, "webhook_id")
+	if webhookID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetWebhookList get webhook list handler
+// @Summary			Get list of Webhooks.
+// @Description		Get list of Webhooks.
+// @Tags			Webhooks
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching webhooks by name or url"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.Webhook}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/webhooks [get]
+func (h *Handler) GetWebhookList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetWebhookListParams{
+		Search: q.Get("search"),
+		Sort:   q.Get("sort"),
+		Limit:  count,
+		Offset: (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetWebhookList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetWebhookDeliveryList get webhook delivery list handler
+// @Summary			Get the delivery log of a Webhook.
+// @Description		Get the deliveries of a Webhook with their status, attempts and last response code.
+// @Tags			Webhooks
+// @Param			webhook_id		path			string	 true	"Webhook ID"
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: status/event_type/next_attempt_at/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			status			query			string	 false	"Deliveries with the status (pending/succeeded/failed)"	example(failed)
+// @Param			event_type		query			string	 false	"Deliveries of the event type"								example(alert.firing)
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=[]entities.WebhookDelivery}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/webhooks/{webhook_id}/deliveries [get]
+func (h *Handler) GetWebhookDeliveryList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	webhookID := chi.URLParam(r, "webhook_id")
+	if webhookID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook not found", nil))
+		return
+	}
+
+	_, err := h.repo.GetWebhook(ctx, webhookID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetWebhookDeliveryListParams{
+		WebhookID: webhookID,
+		Status:    q.Get("status"),
+		EventType: q.Get("event_type"),
+		Sort:      q.Get("sort"),
+		Limit:     count,
+		Offset:    (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetWebhookDeliveryList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
+
+// GetWebhookDelivery get webhook delivery handler
+// @Summary			Get Webhook Delivery by ID.
+// @Description		Get a delivery with the log of its attempts, their response code and the start of the response body.
+// @Tags			Webhooks
+// @Param			webhook_id		path			string	 true	"Webhook ID"
+// @Param			delivery_id		path			string	 true	"Delivery ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.WebhookDelivery}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/webhooks/{webhook_id}/deliveries/{delivery_id} [get]
+func (h *Handler) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	webhookID := chi.URLParam(r, "webhook_id")
+	deliveryID := chi.URLParam(r, "delivery_id")
+	if webhookID == "" || deliveryID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook delivery not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if result.WebhookID != webhookID {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook delivery not found", nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// RedeliverWebhookDelivery redeliver webhook delivery handler
+// @Summary			Redeliver a Webhook Delivery.
+// @Description		Send the event of a delivery again as a new delivery, right away. The new delivery is retried like any other when it fails.
+// @Tags			Webhooks
+// @Param			webhook_id		path			string	 true	"Webhook ID"
+// @Param			delivery_id		path			string	 true	"Delivery ID"
+// @Produce			json
+// @Success			201 			{object}		util.Response{data=entities.WebhookDelivery}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Handler) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	webhookID := chi.URLParam(r, "webhook_id")
+	deliveryID := chi.URLParam(r, "delivery_id")
+	if webhookID == "" || deliveryID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook delivery not found", nil))
+		return
+	}
+
+	delivery, err := h.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+	if delivery.WebhookID != webhookID {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("webhook delivery not found", nil))
+		return
+	}
+
+	result, err := h.webhooks.Redeliver(ctx, deliveryID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+func (h *Handler) validateWebhookPayload(body entities.CreateUpdateWebhookPayload) []string {
+	err := h.validate.Struct(body)
+	if err != nil {
+		return util.ParseValidatorErr(err)
+	}
+
+	errs := []string{}
+	for _, pattern := range body.Events {
+		matched := false
+		for _, eventType := range events.TYPES {
+			if events.Matches(pattern, eventType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			errs = append(errs, fmt.Sprintf("Events %s does not match any event", pattern))
+		}
+	}
+
+	return errs
+}
+
+// webhook fills in the defaults of a webhook, every event when no filter is given and enabled.
+func webhook(body entities.CreateUpdateWebhookPayload) entities.Webhook {
+	result := entities.Webhook{
+		Name:    body.Name,
+		URL:     body.URL,
+		Secret:  body.Secret,
+		Events:  body.Events,
+		Enabled: true,
+	}
+
+	if result.Events == nil {
+		result.Events = []string{}
+	}
+	if body.Enabled != nil {
+		result.Enabled = *body.Enabled
+	}
+
+	return result
+}
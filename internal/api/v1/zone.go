@@ -0,0 +1,264 @@
+package v1
+
+import (
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateZone create zone handler
+// @Summary			Create Zone.
+// @Description		Create new Zone.
+// @Tags			Zones
+// @Accept			json
+// @Produce			json
+// @Param 			json	body		entities.CreateUpdateZonePayload	true	"Zone data"
+// @Success			201		{object}	util.Response{data=entities.Zone}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/zones [post]
+func (h *Handler) CreateZone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	var body entities.CreateUpdateZonePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetSite(ctx, body.SiteID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	zoneID, err := h.repo.CreateZone(ctx, entities.Zone{
+		SiteID:      body.SiteID,
+		Name:        body.Name,
+		Description: body.Description,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetZone(ctx, zoneID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp.Set("created", result))
+}
+
+// UpdateZone update zone handler
+// @Summary			Update Zone.
+// @Description		Update existing Zone.
+// @Tags			Zones
+// @Accept			json
+// @Param 			zone_id	path	string							true	"Zone ID"
+// @Param 			json		body	entities.CreateUpdateZonePayload	true	"Zone data"
+// @Produce			json
+// @Success			200		{object}	util.Response{data=entities.Zone}
+// @Failure			400		{object}	util.Response
+// @Failure			404		{object}	util.Response
+// @Failure			500		{object}	util.Response
+// @Router	/v1/zones/{zone_id} [put]
+func (h *Handler) UpdateZone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	zoneID := chi.URLParam(r, "zone_id")
+	if zoneID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("zone not found", nil))
+		return
+	}
+
+	var body entities.CreateUpdateZonePayload
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil))
+		return
+	}
+
+	err = h.validate.Struct(body)
+	if err != nil {
+		errs := util.ParseValidatorErr(err)
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, resp.Set("invalid data", nil).AddErrValidation(errs))
+		return
+	}
+
+	_, err = h.repo.GetZone(ctx, zoneID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	_, err = h.repo.GetSite(ctx, body.SiteID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	err = h.repo.UpdateZone(ctx, zoneID, entities.Zone{
+		SiteID:      body.SiteID,
+		Name:        body.Name,
+		Description: body.Description,
+	})
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	result, err := h.repo.GetZone(ctx, zoneID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// DeleteZone delete zone handler
+// @Summary			Delete Zone.
+// @Description		Delete Zone. Devices placed in the zone are kept without a zone.
+// @Tags			Zones
+// @Param			zone_id		path			string	 true	"Zone ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/zones/{zone_id} [delete]
+func (h *Handler) DeleteZone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	zoneID := chi.URLParam(r, "zone_id")
+	if zoneID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("zone not found", nil))
+		return
+	}
+
+	err := h.repo.DeleteZone(ctx, zoneID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", nil))
+}
+
+// GetZone get zone handler
+// @Summary			Get zone by zone ID.
+// @Description		Get zone by zone ID.
+// @Tags			Zones
+// @Param			zone_id		path			string	 true	"Zone ID"
+// @Produce			json
+// @Success			200 			{object}		util.Response{data=entities.Zone}
+// @Failure			404				{object}		util.Response
+// @Failure			500				{object}		util.Response
+// @Router	/v1/zones/{zone_id} [get]
+func (h *Handler) GetZone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	zoneID := chi.URLParam(r, "zone_id")
+	if zoneID == "" {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, resp.Set("zone not found", nil))
+		return
+	}
+
+	result, err := h.repo.GetZone(ctx, zoneID)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", result))
+}
+
+// GetZoneList get zone list handler
+// @Summary			Get list of Zone.
+// @Description		Get list of Zone.
+// @Tags			Zones
+// @Produce			json
+// @Param			page			query			int	     false	"Pagination page number (default 1, max 500)"				example(1)
+// @Param			count			query			int	     false	"Pagination data limit  (default 10, max 100)"				example(10)
+// @Param			sort			query			string	 false	"Data sorting (value: name/created_at/updated_at). For desc order, use prefix '-'"	example(-created_at)
+// @Param			search			query			string	 false	"Keyword for searching zone by name or description"	example(block)
+// @Param			organization_id	query			string	 false	"Filter by organization ID"
+// @Param			site_id			query			string	 false	"Filter by site ID"
+// @Success			200 			{object}		util.Response{data=[]entities.Zone}
+// @Failure			500				{object}		util.Response
+// @Router	/v1/zones [get]
+func (h *Handler) GetZoneList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := util.NewResponse()
+
+	q := r.URL.Query()
+	page, count := util.Pagination(q.Get("page"), q.Get("count"))
+
+	params := entities.GetZoneListParams{
+		OrganizationID: q.Get("organization_id"),
+		SiteID:         q.Get("site_id"),
+		Search:         q.Get("search"),
+		Sort:           q.Get("sort"),
+		Limit:          count,
+		Offset:         (page - 1) * count,
+	}
+
+	results, total, err := h.repo.GetZoneList(ctx, params)
+	if err != nil {
+		status, msg := util.ErrStatusCode(err)
+		render.Status(r, status)
+		render.JSON(w, r, resp.Set(msg, nil))
+		return
+	}
+
+	resp.AddMeta(page, count, total)
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.Set("success", results))
+}
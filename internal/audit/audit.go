@@ -0,0 +1,61 @@
+// Package audit carries who made a change, and in which request, down to the audit log of devices and sensors.
+package audit
+
+import (
+	"context"
+	"go-api/internal/entities"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// USER_HEADER names the user making a request, there is no authentication to take it from, so the user is
+// only claimed by the client.
+const USER_HEADER = "X-User"
+
+// MAX_ACTOR_LENGTH is the size of the claimed_actor column of the audit log.
+const MAX_ACTOR_LENGTH = 100
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx whose changes are recorded as made by actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor of the changes made with ctx, the system when ctx carries none.
+func Actor(ctx context.Context) string {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	if !ok || actor == "" {
+		return entities.AUDIT_ACTOR_SYSTEM
+	}
+
+	return actor
+}
+
+// RequestID returns the ID given to the request of ctx by the RequestID middleware, nil outside requests.
+func RequestID(ctx context.Context) *string {
+	requestID := middleware.GetReqID(ctx)
+	if requestID == "" {
+		return nil
+	}
+
+	return &requestID
+}
+
+// Middleware records the changes made by a request as claimed by the user of its X-User header, anonymous
+// without it. The header is not checked, anyone can send any user.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get(USER_HEADER))
+		if actor == "" {
+			actor = entities.AUDIT_ACTOR_ANONYMOUS
+		}
+		if runes := []rune(actor); len(runes) > MAX_ACTOR_LENGTH {
+			actor = string(runes[:MAX_ACTOR_LENGTH])
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithActor(r.Context(), actor)))
+	})
+}
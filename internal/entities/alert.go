@@ -0,0 +1,293 @@
+package entities
+
+import "time"
+
+type AlertOperator string
+
+var (
+	ALERT_OPERATOR_GT  AlertOperator = ">"
+	ALERT_OPERATOR_GTE AlertOperator = ">="
+	ALERT_OPERATOR_LT  AlertOperator = "<"
+	ALERT_OPERATOR_LTE AlertOperator = "<="
+)
+
+// Breached reports whether value crosses the threshold.
+func (o AlertOperator) Breached(value, threshold float64) bool {
+	switch o {
+	case ALERT_OPERATOR_GT:
+		return value > threshold
+	case ALERT_OPERATOR_GTE:
+		return value >= threshold
+	case ALERT_OPERATOR_LT:
+		return value < threshold
+	case ALERT_OPERATOR_LTE:
+		return value <= threshold
+	}
+	return false
+}
+
+// Cleared reports whether value is back past the clear threshold, below it for > and >=, above it for < and <=.
+func (o AlertOperator) Cleared(value, clearThreshold float64) bool {
+	switch o {
+	case ALERT_OPERATOR_GT, ALERT_OPERATOR_GTE:
+		return value < clearThreshold
+	case ALERT_OPERATOR_LT, ALERT_OPERATOR_LTE:
+		return value > clearThreshold
+	}
+	return false
+}
+
+// Upper reports whether the operator alerts on values above the threshold.
+func (o AlertOperator) Upper() bool {
+	return o == ALERT_OPERATOR_GT || o == ALERT_OPERATOR_GTE
+}
+
+type AlertCondition string
+
+var (
+	ALERT_CONDITION_THRESHOLD      AlertCondition = "threshold"
+	ALERT_CONDITION_NO_DATA        AlertCondition = "no_data"
+	ALERT_CONDITION_RATE_OF_CHANGE AlertCondition = "rate_of_change"
+	ALERT_CONDITION_ANOMALY        AlertCondition = "anomaly"
+	ALERT_CONDITION_COMPOSITE      AlertCondition = "composite"
+)
+
+type AlertSeverity string
+
+var (
+	ALERT_SEVERITY_INFO     AlertSeverity = "info"
+	ALERT_SEVERITY_WARNING  AlertSeverity = "warning"
+	ALERT_SEVERITY_CRITICAL AlertSeverity = "critical"
+)
+
+type AlertStatus string
+
+var (
+	ALERT_STATUS_OK       AlertStatus = "ok"
+	ALERT_STATUS_PENDING  AlertStatus = "pending"
+	ALERT_STATUS_FIRING   AlertStatus = "firing"
+	ALERT_STATUS_RESOLVED AlertStatus = "resolved"
+)
+
+// ALERT_ACTOR_SYSTEM is the actor of the status changes made by the rule evaluation.
+const ALERT_ACTOR_SYSTEM = "system"
+
+type AlertEventType string
+
+var (
+	ALERT_EVENT_OK           AlertEventType = "ok"
+	ALERT_EVENT_PENDING      AlertEventType = "pending"
+	ALERT_EVENT_FIRING       AlertEventType = "firing"
+	ALERT_EVENT_RESOLVED     AlertEventType = "resolved"
+	ALERT_EVENT_ACKNOWLEDGED AlertEventType = "acknowledged"
+	ALERT_EVENT_ASSIGNED     AlertEventType = "assigned"
+	ALERT_EVENT_UNASSIGNED   AlertEventType = "unassigned"
+	ALERT_EVENT_COMMENTED    AlertEventType = "commented"
+	ALERT_EVENT_SNOOZED      AlertEventType = "snoozed"
+	ALERT_EVENT_UNSNOOZED    AlertEventType = "unsnoozed"
+)
+
+// AlertRule applies to one sensor or to every sensor of a type. For a threshold rule an alert goes pending
+// when a reading crosses the threshold and fires once the condition held for For seconds. A firing alert
+// resolves when a reading is back past ClearThreshold, so values around the threshold do not flap.
+// A rate of change rule does the same with the change of the value over the last Window seconds and an
+// anomaly rule with the anomaly score of the readings. A no data rule fires when the sensor sent nothing
+// for For seconds and resolves with the next reading. A composite rule has no sensor, its Expression
+// spans sensors and devices and its alerts are per device or for the whole rule.
+type AlertRule struct {
+	ID                 string         `json:"id"`
+	Name               string         `json:"name"`
+	Description        string         `json:"description"`
+	SensorID           *string        `json:"sensor_id"`
+	SensorType         *SensorType    `json:"sensor_type"`
+	Condition          AlertCondition `json:"condition"`
+	Operator           AlertOperator  `json:"operator"`
+	Threshold          float64        `json:"threshold"`
+	ClearThreshold     float64        `json:"clear_threshold"`
+	For                int            `json:"for"`
+	Window             int            `json:"window"`
+	Severity           AlertSeverity  `json:"severity"`
+	NotifyEmails       []string       `json:"notify_emails"`
+	Expression         string         `json:"expression"`
+	EscalationPolicyID *string        `json:"escalation_policy_id"`
+	Enabled            bool           `json:"enabled"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+func (a *AlertRule) ForDuration() time.Duration {
+	return time.Duration(a.For) * time.Second
+}
+
+func (a *AlertRule) WindowDuration() time.Duration {
+	return time.Duration(a.Window) * time.Second
+}
+
+type CreateUpdateAlertRulePayload struct {
+	Name               string         `json:"name" validate:"required,max=100" example:"Greenhouse too hot"`
+	Description        string         `json:"description" example:"Open the vents above 35 degrees"`
+	SensorID           *string        `json:"sensor_id" validate:"excluded_with=SensorType,omitempty,uuid" example:"96a5ec77-9012-4bf3-b08e-39ef4c07fcce"`
+	SensorType         *SensorType    `json:"sensor_type" validate:"excluded_with=SensorID,omitempty,sensorType"`
+	Condition          AlertCondition `json:"condition" validate:"omitempty,alertCondition" example:"threshold"`
+	Operator           AlertOperator  `json:"operator" validate:"omitempty,alertOperator" example:">"`
+	Threshold          float64        `json:"threshold" example:"35"`
+	ClearThreshold     *float64       `json:"clear_threshold" example:"33"`
+	For                int            `json:"for" validate:"min=0,max=86400" example:"600"`
+	Window             int            `json:"window" validate:"min=0,max=86400" example:"0"`
+	Severity           AlertSeverity  `json:"severity" validate:"alertSeverity" example:"warning"`
+	NotifyEmails       []string       `json:"notify_emails" validate:"max=20,dive,email" example:"manager@example.com"`
+	Expression         string         `json:"expression" validate:"max=1000" example:"soil_moisture < 20 AND type:temperature > 30 for 15m on the same device"`
+	EscalationPolicyID *string        `json:"escalation_policy_id" validate:"omitempty,uuid" example:"5d1c9a7e-2b4f-4e8a-9c3d-7f6e5a4b3c2d"`
+	Enabled            *bool          `json:"enabled" example:"true"`
+}
+
+type GetAlertRuleListParams struct {
+	Condition  string
+	SensorID   string
+	SensorType string
+	Severity   string
+	Search     string
+	Sort       string
+	Limit      int
+	Offset     int
+}
+
+// AlertRuleSensor is a sensor a rule applies to, with the time of its latest reading.
+type AlertRuleSensor struct {
+	SensorID       string
+	DeviceID       string
+	LastRecordedAt *time.Time
+	CreatedAt      time.Time
+}
+
+// Alert is the state of a rule for one sensor.
+type Alert struct {
+	ID              string        `json:"id"`
+	RuleID          string        `json:"rule_id"`
+	RuleName        string        `json:"rule_name"`
+	Severity        AlertSeverity `json:"severity"`
+	SensorID        string        `json:"sensor_id"`
+	DeviceID        string        `json:"device_id"`
+	Status          AlertStatus   `json:"status"`
+	Value           float64       `json:"value"`
+	PendingSince    *time.Time    `json:"pending_since"`
+	FiredAt         *time.Time    `json:"fired_at"`
+	ResolvedAt      *time.Time    `json:"resolved_at"`
+	LastEvaluatedAt *time.Time    `json:"last_evaluated_at"`
+	AcknowledgedAt  *time.Time    `json:"acknowledged_at"`
+	AcknowledgedBy  string        `json:"acknowledged_by"`
+	Assignee        string        `json:"assignee"`
+	SnoozedUntil    *time.Time    `json:"snoozed_until"`
+	ResolvedBy      string        `json:"resolved_by"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// Active reports whether the alert is pending or firing.
+func (a *Alert) Active() bool {
+	return a.Status == ALERT_STATUS_PENDING || a.Status == ALERT_STATUS_FIRING
+}
+
+// Snoozed reports whether the alert is snoozed at the given time.
+func (a *Alert) Snoozed(at time.Time) bool {
+	return a.SnoozedUntil != nil && a.SnoozedUntil.After(at)
+}
+
+type GetAlertListParams struct {
+	Statuses     []AlertStatus
+	Severity     string
+	RuleID       string
+	SensorID     string
+	DeviceID     string
+	Assignee     string
+	Acknowledged *bool
+	Sort         string
+	Limit        int
+	Offset       int
+}
+
+// AlertEvent is an entry of the timeline of an alert, a status change or the action of a user.
+// Status and Value are those of the alert after the entry.
+type AlertEvent struct {
+	ID           string         `json:"id"`
+	AlertID      string         `json:"alert_id"`
+	Type         AlertEventType `json:"type"`
+	Actor        string         `json:"actor"`
+	Note         string         `json:"note"`
+	Status       AlertStatus    `json:"status"`
+	Value        float64        `json:"value"`
+	Assignee     string         `json:"assignee"`
+	SnoozedUntil *time.Time     `json:"snoozed_until"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type GetAlertEventListParams struct {
+	AlertID string
+	Type    string
+	Sort    string
+	Limit   int
+	Offset  int
+}
+
+type AlertActionPayload struct {
+	User string `json:"user" validate:"required,max=100" example:"jane"`
+	Note string `json:"note" validate:"max=2000" example:"Vents opened"`
+}
+
+type AlertCommentPayload struct {
+	User string `json:"user" validate:"required,max=100" example:"jane"`
+	Note string `json:"note" validate:"required,max=2000" example:"Checking the vents"`
+}
+
+type AssignAlertPayload struct {
+	User     string `json:"user" validate:"required,max=100" example:"jane"`
+	Assignee string `json:"assignee" validate:"max=100" example:"john"`
+	Note     string `json:"note" validate:"max=2000"`
+}
+
+type SnoozeAlertPayload struct {
+	User  string    `json:"user" validate:"required,max=100" example:"jane"`
+	Until time.Time `json:"until" validate:"required" example:"2024-06-01T08:00:00Z"`
+	Note  string    `json:"note" validate:"max=2000" example:"Sensor is being replaced"`
+}
+
+// AlertRuleDryRunPayload replays the readings recorded from From until To through a composite condition.
+type AlertRuleDryRunPayload struct {
+	Expression string     `json:"expression" validate:"required,max=1000" example:"soil_moisture < 20 AND type:temperature > 30 for 15m on the same device"`
+	From       time.Time  `json:"from" validate:"required" example:"2024-03-01T00:00:00Z"`
+	To         *time.Time `json:"to" example:"2024-03-08T00:00:00Z"`
+}
+
+// AlertRuleDryRun is when a composite condition would have fired over past readings. DeviceID is empty
+// for a condition that is not on the same device, ResolvedAt is nil when it still held at the end.
+type AlertRuleDryRun struct {
+	Expression string             `json:"expression"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Readings   int                `json:"readings"`
+	Firings    []*AlertRuleFiring `json:"firings"`
+}
+
+type AlertRuleFiring struct {
+	DeviceID     string     `json:"device_id"`
+	PendingSince time.Time  `json:"pending_since"`
+	FiredAt      time.Time  `json:"fired_at"`
+	ResolvedAt   *time.Time `json:"resolved_at"`
+}
+
+// ConditionValue is a reading with the sensor it belongs to, an operand value of a composite condition.
+type ConditionValue struct {
+	SensorID   string
+	DeviceID   string
+	SensorName string
+	SensorType SensorType
+	Value      float64
+	RecordedAt time.Time
+}
+
+// ConditionSensors selects the sensors of the operands of a composite condition, by ID, lowercase name or type.
+type ConditionSensors struct {
+	IDs   []string
+	Names []string
+	Types []string
+}
@@ -0,0 +1,82 @@
+package entities
+
+import "time"
+
+const (
+	ANOMALY_DEFAULT_SENSITIVITY = 3.0
+	ANOMALY_DEFAULT_ALPHA       = 0.1
+	ANOMALY_DEFAULT_WINDOW      = 60
+)
+
+// AnomalyDetector scores every reading of a sensor by how far it is from what the previous readings predict.
+// A reading is flagged when its score, in standard deviations, reaches Sensitivity. Alpha is the weight
+// of a new reading in the moving averages and Window the number of readings of the rolling z-score.
+// A seasonal detector learns a daily cycle by UTC hour and scores the readings without it.
+type AnomalyDetector struct {
+	SensorID       string       `json:"sensor_id"`
+	Enabled        bool         `json:"enabled"`
+	Sensitivity    float64      `json:"sensitivity"`
+	Alpha          float64      `json:"alpha"`
+	Window         int          `json:"window"`
+	Seasonal       bool         `json:"seasonal"`
+	Model          AnomalyModel `json:"model"`
+	LastRecordedAt *time.Time   `json:"last_recorded_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// AnomalyModel is the learned state of a detector. Values are deseasonalized, Level and Variance are
+// their exponentially weighted mean and variance, Recent the last Window of them and Hours the offset
+// of each UTC hour from the level.
+type AnomalyModel struct {
+	Count    int64     `json:"count"`
+	Level    float64   `json:"level"`
+	Variance float64   `json:"variance"`
+	Recent   []float64 `json:"recent"`
+	Hours    []float64 `json:"hours,omitempty"`
+}
+
+type PutAnomalyDetectorPayload struct {
+	Enabled     *bool    `json:"enabled" example:"true"`
+	Sensitivity *float64 `json:"sensitivity" validate:"omitempty,min=1,max=10" example:"3"`
+	Alpha       *float64 `json:"alpha" validate:"omitempty,gt=0,max=1" example:"0.1"`
+	Window      *int     `json:"window" validate:"omitempty,min=10,max=1000" example:"60"`
+	Seasonal    bool     `json:"seasonal" example:"true"`
+	Reset       bool     `json:"reset" example:"false"`
+}
+
+// AnomalyScore is the result of scoring one reading. Score is the larger of the absolute rolling z-score
+// and EWMA score, Expected the value the model predicted.
+type AnomalyScore struct {
+	Expected  float64
+	Score     float64
+	ZScore    float64
+	EWMAScore float64
+	Anomalous bool
+}
+
+// SensorAnomaly is a reading flagged by the anomaly detector of its sensor.
+type SensorAnomaly struct {
+	ID         int64     `json:"id"`
+	ReadingID  int64     `json:"reading_id"`
+	SensorID   string    `json:"sensor_id"`
+	DeviceID   string    `json:"device_id"`
+	Value      float64   `json:"value"`
+	Expected   float64   `json:"expected"`
+	Score      float64   `json:"score"`
+	ZScore     float64   `json:"z_score"`
+	EWMAScore  float64   `json:"ewma_score"`
+	RecordedAt time.Time `json:"recorded_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetSensorAnomalyListParams struct {
+	SensorID string
+	DeviceID string
+	From     *time.Time
+	To       *time.Time
+	MinScore *float64
+	Sort     string
+	Limit    int
+	Offset   int
+}
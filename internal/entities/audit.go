@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+type AuditResourceType string
+
+var (
+	AUDIT_RESOURCE_DEVICE AuditResourceType = "device"
+	AUDIT_RESOURCE_SENSOR AuditResourceType = "sensor"
+)
+
+type AuditAction string
+
+var (
+	AUDIT_ACTION_CREATE  AuditAction = "create"
+	AUDIT_ACTION_UPDATE  AuditAction = "update"
+	AUDIT_ACTION_DELETE  AuditAction = "delete"
+	AUDIT_ACTION_RESTORE AuditAction = "restore"
+	AUDIT_ACTION_PURGE   AuditAction = "purge"
+)
+
+const (
+	// AUDIT_ACTOR_SYSTEM is the actor of the changes made by background workers, such as purging the trash.
+	AUDIT_ACTOR_SYSTEM = "system"
+	// AUDIT_ACTOR_ANONYMOUS is the actor of the changes made by requests without an X-User header.
+	AUDIT_ACTOR_ANONYMOUS = "anonymous"
+)
+
+// AuditChange is the value of a field before and after a change, Old is null for created resources
+// and New is null for purged ones.
+type AuditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditEntry records one change of a device or sensor. Changes holds the fields that changed, by column name.
+// ClaimedActor is self-reported by the request, see AUDIT_ACTOR_ANONYMOUS. RequestID is null for changes made
+// by background workers.
+type AuditEntry struct {
+	ID           string                 `json:"id"`
+	ResourceType AuditResourceType      `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	Action       AuditAction            `json:"action"`
+	ClaimedActor string                 `json:"claimed_actor"`
+	RequestID    *string                `json:"request_id"`
+	Changes      map[string]AuditChange `json:"changes"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+type GetAuditEntryListParams struct {
+	ResourceType AuditResourceType
+	ResourceID   string
+	Action       AuditAction
+	ClaimedActor string
+	RequestID    string
+	From         *time.Time
+	To           *time.Time
+	Sort         string
+	Limit        int
+	Offset       int
+}
@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+type CalibrationKind string
+
+var (
+	CALIBRATION_KIND_OFFSET     CalibrationKind = "offset"
+	CALIBRATION_KIND_SCALE      CalibrationKind = "scale"
+	CALIBRATION_KIND_POLYNOMIAL CalibrationKind = "polynomial"
+	CALIBRATION_KIND_MULTIPOINT CalibrationKind = "multipoint"
+)
+
+// CalibrationPoint maps a raw sensor value to the reference value measured at the same time.
+type CalibrationPoint struct {
+	Raw       float64 `json:"raw" example:"10.2"`
+	Reference float64 `json:"reference" example:"10"`
+}
+
+// SensorCalibration applies to readings recorded from EffectiveFrom until the next calibration of the sensor.
+type SensorCalibration struct {
+	ID            string             `json:"id"`
+	SensorID      string             `json:"sensor_id"`
+	Kind          CalibrationKind    `json:"kind"`
+	Offset        *float64           `json:"offset,omitempty"`
+	Scale         *float64           `json:"scale,omitempty"`
+	Coefficients  []float64          `json:"coefficients,omitempty"`
+	Points        []CalibrationPoint `json:"points,omitempty"`
+	Description   string             `json:"description"`
+	EffectiveFrom time.Time          `json:"effective_from"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// Apply returns the calibrated value of a raw reading.
+// Polynomial coefficients start with the constant term, multipoint interpolates linearly between
+// the points and extrapolates with the first and last segment.
+func (c *SensorCalibration) Apply(raw float64) float64 {
+	switch c.Kind {
+	case CALIBRATION_KIND_OFFSET:
+		if c.Offset != nil {
+			return raw + *c.Offset
+		}
+	case CALIBRATION_KIND_SCALE:
+		if c.Scale != nil {
+			return raw * *c.Scale
+		}
+	case CALIBRATION_KIND_POLYNOMIAL:
+		value := 0.0
+		for i := len(c.Coefficients) - 1; i >= 0; i-- {
+			value = value*raw + c.Coefficients[i]
+		}
+		if len(c.Coefficients) > 0 {
+			return value
+		}
+	case CALIBRATION_KIND_MULTIPOINT:
+		return interpolate(c.Points, raw)
+	}
+
+	return raw
+}
+
+func interpolate(points []CalibrationPoint, raw float64) float64 {
+	if len(points) == 0 {
+		return raw
+	}
+	if len(points) == 1 {
+		return raw + points[0].Reference - points[0].Raw
+	}
+
+	sorted := append([]CalibrationPoint{}, points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Raw < sorted[j].Raw })
+
+	i := sort.Search(len(sorted), func(i int) bool { return sorted[i].Raw >= raw })
+	switch {
+	case i == 0:
+		i = 1
+	case i == len(sorted):
+		i = len(sorted) - 1
+	}
+
+	lo, hi := sorted[i-1], sorted[i]
+	return lo.Reference + (raw-lo.Raw)*(hi.Reference-lo.Reference)/(hi.Raw-lo.Raw)
+}
+
+// CalibrationAt returns the calibration in effect at t from calibrations ordered by EffectiveFrom, or nil.
+func CalibrationAt(calibrations []*SensorCalibration, t time.Time) *SensorCalibration {
+	var current *SensorCalibration
+	for _, c := range calibrations {
+		if c.EffectiveFrom.After(t) {
+			break
+		}
+		current = c
+	}
+
+	return current
+}
+
+type CreateUpdateSensorCalibrationPayload struct {
+	Kind          CalibrationKind    `json:"kind" validate:"calibrationKind" example:"offset"`
+	Offset        *float64           `json:"offset" validate:"required_if=Kind offset" example:"-0.4"`
+	Scale         *float64           `json:"scale" validate:"required_if=Kind scale" example:"1.02"`
+	Coefficients  []float64          `json:"coefficients" validate:"required_if=Kind polynomial,max=10"`
+	Points        []CalibrationPoint `json:"points" validate:"required_if=Kind multipoint,omitempty,min=2,max=50"`
+	Description   string             `json:"description" example:"Spring recalibration"`
+	EffectiveFrom time.Time          `json:"effective_from" validate:"required" example:"2024-03-01T00:00:00Z"`
+}
@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+type CommandStatus string
+
+var (
+	COMMAND_STATUS_PENDING   CommandStatus = "pending"
+	COMMAND_STATUS_DELIVERED CommandStatus = "delivered"
+	COMMAND_STATUS_SUCCEEDED CommandStatus = "succeeded"
+	COMMAND_STATUS_FAILED    CommandStatus = "failed"
+	COMMAND_STATUS_EXPIRED   CommandStatus = "expired"
+)
+
+type DeviceCommand struct {
+	ID             string         `json:"id"`
+	DeviceID       string         `json:"device_id"`
+	Name           string         `json:"name"`
+	Params         map[string]any `json:"params"`
+	Status         CommandStatus  `json:"status"`
+	Result         map[string]any `json:"result"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+	AcknowledgedAt *time.Time     `json:"acknowledged_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type CreateDeviceCommandPayload struct {
+	Name   string         `json:"name" validate:"required,max=100" example:"open_valve"`
+	Params map[string]any `json:"params"`
+	TTL    int            `json:"ttl" validate:"required,min=1,max=604800" example:"600"`
+}
+
+type AckDeviceCommandPayload struct {
+	Status CommandStatus  `json:"status" validate:"commandAckStatus" example:"succeeded"`
+	Result map[string]any `json:"result"`
+}
+
+type GetDeviceCommandListParams struct {
+	DeviceID string
+	Status   string
+	Sort     string
+	Limit    int
+	Offset   int
+}
@@ -10,23 +10,57 @@ var (
 )
 
 type Device struct {
-	ID          string       `json:"id"`
-	Name        string       `json:"name"`
-	Description string       `json:"description"`
-	Status      DeviceStatus `json:"status"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Status      DeviceStatus      `json:"status"`
+	ZoneID      *string           `json:"zone_id"`
+	Latitude    *float64          `json:"latitude"`
+	Longitude   *float64          `json:"longitude"`
+	Altitude    *float64          `json:"altitude"`
+	Distance    *float64          `json:"distance,omitempty"`
+	Attributes  map[string]any    `json:"attributes"`
+	Labels      map[string]string `json:"labels"`
+	Version     int               `json:"version"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
 }
 
 type CreateUpdateDevicePayload struct {
-	Name        string       `json:"name" validate:"required" example:"Device #1"`
-	Description string       `json:"description" example:"First device"`
-	Status      DeviceStatus `json:"status" validate:"deviceStatus" example:"active"`
+	Name        string            `json:"name" validate:"required" example:"Device #1"`
+	Description string            `json:"description" example:"First device"`
+	Status      DeviceStatus      `json:"status" validate:"deviceStatus" example:"active"`
+	ZoneID      *string           `json:"zone_id" validate:"omitempty,uuid" example:"0d6c8a0e-2f7b-4f38-9f0e-5b8d6f1f6a21"`
+	Latitude    *float64          `json:"latitude" validate:"required_with=Longitude,omitempty,latitude" example:"-6.914744"`
+	Longitude   *float64          `json:"longitude" validate:"required_with=Latitude,omitempty,longitude" example:"107.609810"`
+	Altitude    *float64          `json:"altitude" validate:"excluded_without=Latitude" example:"768"`
+	Attributes  map[string]any    `json:"attributes"`
+	Labels      map[string]string `json:"labels" validate:"labels"`
 }
 
 type GetDeviceListParams struct {
-	Search string
-	Sort   string
-	Limit  int
-	Offset int
+	Search     string
+	Attributes map[string]string
+	Selector   []LabelRequirement
+	GroupID    string
+	Location   LocationFilter
+	Geo        GeoFilter
+	Sort       string
+	Limit      int
+	Offset     int
+}
+
+// CLONE_NAME_COUNTER is replaced by the clone number in the name of cloned devices.
+const CLONE_NAME_COUNTER = "{n}"
+
+type CloneDevicePayload struct {
+	Name  string `json:"name" validate:"required" example:"Station-{n}"`
+	Count int    `json:"count" validate:"omitempty,min=1,max=100" example:"3"`
+	Start int    `json:"start" validate:"omitempty,min=1" example:"1"`
+}
+
+type ClonedDevice struct {
+	DeviceID  string   `json:"device_id"`
+	Name      string   `json:"name"`
+	SensorIDs []string `json:"sensor_ids"`
 }
@@ -0,0 +1,135 @@
+package entities
+
+import "time"
+
+type EscalationGroupBy string
+
+var (
+	ESCALATION_GROUP_BY_RULE     EscalationGroupBy = "rule"
+	ESCALATION_GROUP_BY_DEVICE   EscalationGroupBy = "device"
+	ESCALATION_GROUP_BY_SENSOR   EscalationGroupBy = "sensor"
+	ESCALATION_GROUP_BY_SEVERITY EscalationGroupBy = "severity"
+)
+
+// EscalationPolicy notifies the firing alerts of its rules step by step until they are acknowledged or
+// resolved. A step is notified Delay seconds after the alert fired, the last step is repeated every
+// RepeatInterval seconds (never when 0). Alerts with the same GroupBy values are notified together: the
+// first notification waits GroupWait seconds for more alerts and a group is notified at most once every
+// GroupInterval seconds. An alert firing again within DedupWindow seconds of its last notification resumes
+// its escalation instead of starting over, and RateLimit caps the notifications of the policy per hour.
+type EscalationPolicy struct {
+	ID             string              `json:"id"`
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	Steps          []EscalationStep    `json:"steps"`
+	RepeatInterval int                 `json:"repeat_interval"`
+	GroupBy        []EscalationGroupBy `json:"group_by"`
+	GroupWait      int                 `json:"group_wait"`
+	GroupInterval  int                 `json:"group_interval"`
+	DedupWindow    int                 `json:"dedup_window"`
+	RateLimit      int                 `json:"rate_limit"`
+	Enabled        bool                `json:"enabled"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// NextNotifyAt returns when the next step of an escalation is due, nil when the steps are done and the
+// policy does not repeat. The first step waits the group wait of the policy on top of its delay.
+func (p *EscalationPolicy) NextNotifyAt(escalation *AlertEscalation) *time.Time {
+	var next time.Time
+
+	switch {
+	case escalation.Step < len(p.Steps):
+		next = escalation.FiredAt.Add(time.Duration(p.Steps[escalation.Step].Delay) * time.Second)
+		if escalation.Step == 0 {
+			next = next.Add(time.Duration(p.GroupWait) * time.Second)
+		}
+	case p.RepeatInterval > 0 && escalation.NotifiedAt != nil:
+		next = escalation.NotifiedAt.Add(time.Duration(p.RepeatInterval) * time.Second)
+	default:
+		return nil
+	}
+
+	return &next
+}
+
+// Start schedules the first step of the policy for an alert that fired at firedAt. An escalation of the
+// policy notified within the dedup window resumes where it was, so a flapping sensor is not notified on
+// every flap.
+func (p *EscalationPolicy) Start(escalation *AlertEscalation, firedAt, now time.Time) {
+	dedupWindow := time.Duration(p.DedupWindow) * time.Second
+	resume := escalation.PolicyID == p.ID && escalation.NotifiedAt != nil && now.Sub(*escalation.NotifiedAt) < dedupWindow
+	if !resume {
+		escalation.Step = 0
+		escalation.FiredAt = firedAt
+		escalation.NotifiedAt = nil
+	}
+
+	escalation.PolicyID = p.ID
+	escalation.NextNotifyAt = p.NextNotifyAt(escalation)
+}
+
+// EscalationStep is a channel of a policy, email recipients and webhooks.
+type EscalationStep struct {
+	Delay      int      `json:"delay" validate:"min=0,max=604800" example:"0"`
+	Emails     []string `json:"emails" validate:"max=20,dive,email" example:"oncall@example.com"`
+	WebhookIDs []string `json:"webhook_ids" validate:"max=10,dive,uuid" example:"3f0b6b8e-8a43-4f0e-9d55-2f1e6c1b7a90"`
+}
+
+type CreateUpdateEscalationPolicyPayload struct {
+	Name           string              `json:"name" validate:"required,max=100" example:"Greenhouse on-call"`
+	Description    string              `json:"description" example:"Night shift first, then the farm manager"`
+	Steps          []EscalationStep    `json:"steps" validate:"required,min=1,max=10,dive"`
+	RepeatInterval int                 `json:"repeat_interval" validate:"omitempty,min=60,max=86400" example:"3600"`
+	GroupBy        []EscalationGroupBy `json:"group_by" validate:"max=4,unique,dive,escalationGroupBy" example:"rule"`
+	GroupWait      int                 `json:"group_wait" validate:"min=0,max=3600" example:"30"`
+	GroupInterval  int                 `json:"group_interval" validate:"min=0,max=86400" example:"300"`
+	DedupWindow    int                 `json:"dedup_window" validate:"min=0,max=86400" example:"1800"`
+	RateLimit      int                 `json:"rate_limit" validate:"min=0,max=1000" example:"20"`
+	Enabled        *bool               `json:"enabled" example:"true"`
+}
+
+type GetEscalationPolicyListParams struct {
+	Search string
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// AlertEscalation is the progress of a firing alert through the steps of a policy. Step is the last step
+// notified since FiredAt, 0 before the first one, and NextNotifyAt is nil once the escalation stopped.
+type AlertEscalation struct {
+	AlertID      string     `json:"alert_id"`
+	PolicyID     string     `json:"policy_id"`
+	Step         int        `json:"step"`
+	FiredAt      time.Time  `json:"fired_at"`
+	NextNotifyAt *time.Time `json:"next_notify_at"`
+	NotifiedAt   *time.Time `json:"notified_at"`
+	Alert        *Alert     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// EscalationNotification is one message of a policy step to its channels, for a group of alerts. Step
+// counts from 1 and Repeat is set for the repetitions of the last step. Error lists the channels that
+// could not be notified.
+type EscalationNotification struct {
+	ID         string    `json:"id"`
+	PolicyID   string    `json:"policy_id"`
+	Step       int       `json:"step"`
+	Repeat     bool      `json:"repeat"`
+	GroupKey   string    `json:"group_key"`
+	AlertIDs   []string  `json:"alert_ids"`
+	Emails     []string  `json:"emails"`
+	WebhookIDs []string  `json:"webhook_ids"`
+	Error      string    `json:"error"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetEscalationNotificationListParams struct {
+	PolicyID string
+	AlertID  string
+	Sort     string
+	Limit    int
+	Offset   int
+}
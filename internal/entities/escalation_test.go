@@ -0,0 +1,131 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextNotifyAt(t *testing.T) {
+	firedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	notifiedAt := firedAt.Add(20 * time.Minute)
+	policy := &EscalationPolicy{
+		Steps:     []EscalationStep{{Delay: 0}, {Delay: 900}},
+		GroupWait: 30,
+	}
+	repeating := *policy
+	repeating.RepeatInterval = 3600
+
+	tests := []struct {
+		name       string
+		policy     *EscalationPolicy
+		escalation AlertEscalation
+		want       *time.Time
+	}{
+		{
+			name:       "first step waits the group wait",
+			policy:     policy,
+			escalation: AlertEscalation{Step: 0, FiredAt: firedAt},
+			want:       ptr(firedAt.Add(30 * time.Second)),
+		},
+		{
+			name:       "later step is its delay after firing",
+			policy:     policy,
+			escalation: AlertEscalation{Step: 1, FiredAt: firedAt, NotifiedAt: &firedAt},
+			want:       ptr(firedAt.Add(15 * time.Minute)),
+		},
+		{
+			name:       "steps done without repeat",
+			policy:     policy,
+			escalation: AlertEscalation{Step: 2, FiredAt: firedAt, NotifiedAt: &notifiedAt},
+			want:       nil,
+		},
+		{
+			name:       "steps done repeats after the last notification",
+			policy:     &repeating,
+			escalation: AlertEscalation{Step: 2, FiredAt: firedAt, NotifiedAt: &notifiedAt},
+			want:       ptr(notifiedAt.Add(time.Hour)),
+		},
+		{
+			name:       "no steps",
+			policy:     &EscalationPolicy{RepeatInterval: 3600},
+			escalation: AlertEscalation{FiredAt: firedAt},
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.NextNotifyAt(&tt.escalation)
+			if !equalTime(got, tt.want) {
+				t.Errorf("NextNotifyAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-2 * time.Hour)
+	recent := now.Add(-10 * time.Minute)
+	stale := now.Add(-time.Hour)
+	policy := &EscalationPolicy{
+		ID:          "policy-1",
+		Steps:       []EscalationStep{{Delay: 0}, {Delay: 600}, {Delay: 3600}},
+		DedupWindow: 1800,
+	}
+
+	tests := []struct {
+		name       string
+		escalation AlertEscalation
+		want       AlertEscalation
+	}{
+		{
+			name:       "new escalation",
+			escalation: AlertEscalation{AlertID: "alert-1"},
+			want:       AlertEscalation{AlertID: "alert-1", PolicyID: "policy-1", Step: 0, FiredAt: now, NextNotifyAt: &now},
+		},
+		{
+			name:       "notified within the dedup window resumes",
+			escalation: AlertEscalation{PolicyID: "policy-1", Step: 1, FiredAt: earlier, NotifiedAt: &recent},
+			want:       AlertEscalation{PolicyID: "policy-1", Step: 1, FiredAt: earlier, NotifiedAt: &recent, NextNotifyAt: ptr(earlier.Add(10 * time.Minute))},
+		},
+		{
+			name:       "notified before the dedup window starts over",
+			escalation: AlertEscalation{PolicyID: "policy-1", Step: 2, FiredAt: earlier, NotifiedAt: &stale},
+			want:       AlertEscalation{PolicyID: "policy-1", Step: 0, FiredAt: now, NextNotifyAt: &now},
+		},
+		{
+			name:       "another policy starts over",
+			escalation: AlertEscalation{PolicyID: "policy-2", Step: 1, FiredAt: earlier, NotifiedAt: &recent},
+			want:       AlertEscalation{PolicyID: "policy-1", Step: 0, FiredAt: now, NextNotifyAt: &now},
+		},
+		{
+			name:       "never notified starts over",
+			escalation: AlertEscalation{PolicyID: "policy-1", Step: 0, FiredAt: earlier},
+			want:       AlertEscalation{PolicyID: "policy-1", Step: 0, FiredAt: now, NextNotifyAt: &now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.escalation
+			policy.Start(&got, now, now)
+
+			if got.PolicyID != tt.want.PolicyID || got.Step != tt.want.Step || !got.FiredAt.Equal(tt.want.FiredAt) ||
+				!equalTime(got.NotifiedAt, tt.want.NotifiedAt) || !equalTime(got.NextNotifyAt, tt.want.NextNotifyAt) {
+				t.Errorf("Start() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,67 @@
+package entities
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// BoundingBox is given as min/max longitude and latitude, MinLongitude > MaxLongitude crosses the antimeridian.
+type BoundingBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+// GeoFilter restricts a device list to a bounding box and/or a radius in meters around Near.
+// When Near is set the distance is returned and can be used for sorting.
+type GeoFilter struct {
+	BoundingBox *BoundingBox
+	Near        *GeoPoint
+	Radius      float64
+}
+
+type GeoJSONGeometry struct {
+	Type        string    `json:"type" example:"Point"`
+	Coordinates []float64 `json:"coordinates" swaggertype:"array,number" example:"107.609810,-6.914744"`
+}
+
+type GeoJSONFeature struct {
+	Type       string           `json:"type" example:"Feature"`
+	ID         string           `json:"id"`
+	Geometry   *GeoJSONGeometry `json:"geometry"`
+	Properties *Device          `json:"properties"`
+}
+
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type" example:"FeatureCollection"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// NewDeviceFeatureCollection converts devices to GeoJSON, devices without a location get a null geometry.
+func NewDeviceFeatureCollection(devices []*Device) GeoJSONFeatureCollection {
+	collection := GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []GeoJSONFeature{},
+	}
+
+	for _, d := range devices {
+		feature := GeoJSONFeature{
+			Type:       "Feature",
+			ID:         d.ID,
+			Properties: d,
+		}
+
+		if d.Latitude != nil && d.Longitude != nil {
+			coordinates := []float64{*d.Longitude, *d.Latitude}
+			if d.Altitude != nil {
+				coordinates = append(coordinates, *d.Altitude)
+			}
+			feature.Geometry = &GeoJSONGeometry{Type: "Point", Coordinates: coordinates}
+		}
+
+		collection.Features = append(collection.Features, feature)
+	}
+
+	return collection
+}
@@ -0,0 +1,53 @@
+package entities
+
+import "time"
+
+type DeviceGroupType string
+
+var (
+	DEVICE_GROUP_TYPE_STATIC  DeviceGroupType = "static"
+	DEVICE_GROUP_TYPE_DYNAMIC DeviceGroupType = "dynamic"
+)
+
+type DeviceGroup struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Type        DeviceGroupType    `json:"type"`
+	Filter      *DeviceGroupFilter `json:"filter"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// DeviceGroupFilter is the saved device list filter that defines the members of a dynamic group.
+type DeviceGroupFilter struct {
+	Search     string            `json:"search" example:"station"`
+	Attributes map[string]string `json:"attributes"`
+	Selector   string            `json:"selector" example:"site=north,env!=test"`
+}
+
+type CreateUpdateDeviceGroupPayload struct {
+	Name        string             `json:"name" validate:"required,max=100" example:"North stations"`
+	Description string             `json:"description" example:"Weather stations of the north site"`
+	Type        DeviceGroupType    `json:"type" validate:"deviceGroupType" example:"dynamic"`
+	Filter      *DeviceGroupFilter `json:"filter" validate:"required_if=Type dynamic"`
+}
+
+type DeviceGroupMembersPayload struct {
+	DeviceIDs []string `json:"device_ids" validate:"required,min=1,max=1000,dive,uuid"`
+}
+
+type DeviceGroupStatusPayload struct {
+	Status DeviceStatus `json:"status" validate:"deviceStatus" example:"inactive"`
+}
+
+type BulkUpdateResult struct {
+	Updated int64 `json:"updated"`
+}
+
+type GetDeviceGroupListParams struct {
+	Search string
+	Sort   string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,19 @@
+package entities
+
+type SelectorOperator string
+
+var (
+	SELECTOR_OPERATOR_EQUALS     SelectorOperator = "="
+	SELECTOR_OPERATOR_NOT_EQUALS SelectorOperator = "!="
+	SELECTOR_OPERATOR_IN         SelectorOperator = "in"
+	SELECTOR_OPERATOR_NOT_IN     SelectorOperator = "notin"
+	SELECTOR_OPERATOR_EXISTS     SelectorOperator = "exists"
+	SELECTOR_OPERATOR_NOT_EXISTS SelectorOperator = "!"
+)
+
+// LabelRequirement is a single condition of a label selector, e.g. env!=test or tier in (a,b).
+type LabelRequirement struct {
+	Key      string
+	Operator SelectorOperator
+	Values   []string
+}
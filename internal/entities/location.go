@@ -0,0 +1,77 @@
+package entities
+
+import "time"
+
+type Organization struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateUpdateOrganizationPayload struct {
+	Name        string `json:"name" validate:"required,max=100" example:"Mertani Farms"`
+	Description string `json:"description" example:"Main organization"`
+}
+
+type GetOrganizationListParams struct {
+	Search string
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+type Site struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CreateUpdateSitePayload struct {
+	OrganizationID string `json:"organization_id" validate:"uuid" example:"b6f0a4a2-5c1e-4c55-9a53-0f4f7f3c2d10"`
+	Name           string `json:"name" validate:"required,max=100" example:"North Field"`
+	Description    string `json:"description" example:"Rice fields of the north site"`
+}
+
+type GetSiteListParams struct {
+	OrganizationID string
+	Search         string
+	Sort           string
+	Limit          int
+	Offset         int
+}
+
+type Zone struct {
+	ID          string    `json:"id"`
+	SiteID      string    `json:"site_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateUpdateZonePayload struct {
+	SiteID      string `json:"site_id" validate:"uuid" example:"0d6c8a0e-2f7b-4f38-9f0e-5b8d6f1f6a21"`
+	Name        string `json:"name" validate:"required,max=100" example:"Block A"`
+	Description string `json:"description" example:"Irrigation block A"`
+}
+
+type GetZoneListParams struct {
+	OrganizationID string
+	SiteID         string
+	Search         string
+	Sort           string
+	Limit          int
+	Offset         int
+}
+
+// LocationFilter selects resources placed anywhere below the given organization, site or zone.
+type LocationFilter struct {
+	OrganizationID string
+	SiteID         string
+	ZoneID         string
+}
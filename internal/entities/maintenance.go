@@ -0,0 +1,77 @@
+package entities
+
+import "time"
+
+type MaintenanceRecurrence string
+
+var (
+	MAINTENANCE_RECURRENCE_NONE   MaintenanceRecurrence = "none"
+	MAINTENANCE_RECURRENCE_DAILY  MaintenanceRecurrence = "daily"
+	MAINTENANCE_RECURRENCE_WEEKLY MaintenanceRecurrence = "weekly"
+)
+
+// Period is the time between two occurrences of a recurring window, zero for one-off windows.
+// Periods are fixed durations, so recurring windows follow UTC and ignore daylight saving.
+func (m MaintenanceRecurrence) Period() time.Duration {
+	switch m {
+	case MAINTENANCE_RECURRENCE_DAILY:
+		return 24 * time.Hour
+	case MAINTENANCE_RECURRENCE_WEEKLY:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// MaintenanceWindow targets either a device or a device group.
+// Recurring windows repeat the starts_at/ends_at span every period until recurrence_until.
+type MaintenanceWindow struct {
+	ID              string                `json:"id"`
+	DeviceID        *string               `json:"device_id"`
+	GroupID         *string               `json:"group_id"`
+	Name            string                `json:"name"`
+	Description     string                `json:"description"`
+	StartsAt        time.Time             `json:"starts_at"`
+	EndsAt          time.Time             `json:"ends_at"`
+	Recurrence      MaintenanceRecurrence `json:"recurrence"`
+	RecurrenceUntil *time.Time            `json:"recurrence_until"`
+	Active          bool                  `json:"active"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// ActiveAt reports whether t falls inside the window or one of its occurrences.
+func (m *MaintenanceWindow) ActiveAt(t time.Time) bool {
+	if t.Before(m.StartsAt) {
+		return false
+	}
+
+	period := m.Recurrence.Period()
+	if period == 0 {
+		return t.Before(m.EndsAt)
+	}
+
+	if m.RecurrenceUntil != nil && !t.Before(*m.RecurrenceUntil) {
+		return false
+	}
+
+	return t.Sub(m.StartsAt)%period < m.EndsAt.Sub(m.StartsAt)
+}
+
+type CreateUpdateMaintenanceWindowPayload struct {
+	DeviceID        *string               `json:"device_id" validate:"required_without=GroupID,excluded_with=GroupID,omitempty,uuid" example:"d2431891-c5e4-462d-bf9b-7a194d5bebda"`
+	GroupID         *string               `json:"group_id" validate:"required_without=DeviceID,excluded_with=DeviceID,omitempty,uuid"`
+	Name            string                `json:"name" validate:"required,max=100" example:"Seasonal cleaning"`
+	Description     string                `json:"description" example:"Sensor cleaning and battery swap"`
+	StartsAt        time.Time             `json:"starts_at" validate:"required" example:"2024-03-01T08:00:00Z"`
+	EndsAt          time.Time             `json:"ends_at" validate:"required,gtfield=StartsAt" example:"2024-03-01T10:00:00Z"`
+	Recurrence      MaintenanceRecurrence `json:"recurrence" validate:"omitempty,maintenanceRecurrence" example:"weekly"`
+	RecurrenceUntil *time.Time            `json:"recurrence_until" example:"2024-06-01T00:00:00Z"`
+}
+
+type GetMaintenanceWindowListParams struct {
+	DeviceID string
+	GroupID  string
+	Sort     string
+	Limit    int
+	Offset   int
+}
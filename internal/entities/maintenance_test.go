@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActiveAt(t *testing.T) {
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	until := start.Add(3 * 24 * time.Hour)
+
+	oneOff := &MaintenanceWindow{StartsAt: start, EndsAt: end, Recurrence: MAINTENANCE_RECURRENCE_NONE}
+	daily := &MaintenanceWindow{StartsAt: start, EndsAt: end, Recurrence: MAINTENANCE_RECURRENCE_DAILY, RecurrenceUntil: &until}
+	endless := &MaintenanceWindow{StartsAt: start, EndsAt: end, Recurrence: MAINTENANCE_RECURRENCE_DAILY}
+
+	tests := []struct {
+		name   string
+		window *MaintenanceWindow
+		at     time.Time
+		want   bool
+	}{
+		{name: "one-off before starts_at", window: oneOff, at: start.Add(-time.Second), want: false},
+		{name: "one-off at starts_at", window: oneOff, at: start, want: true},
+		{name: "one-off inside", window: oneOff, at: start.Add(time.Hour), want: true},
+		{name: "one-off at ends_at", window: oneOff, at: end, want: false},
+		{name: "one-off a day later", window: oneOff, at: start.Add(24 * time.Hour), want: false},
+		{name: "daily before starts_at", window: daily, at: start.Add(-24*time.Hour + time.Hour), want: false},
+		{name: "daily second occurrence", window: daily, at: start.Add(24*time.Hour + time.Hour), want: true},
+		{name: "daily between occurrences", window: daily, at: end.Add(time.Hour), want: false},
+		{name: "daily at end of an occurrence", window: daily, at: end.Add(24 * time.Hour), want: false},
+		{name: "daily at recurrence_until", window: daily, at: until, want: false},
+		{name: "daily after recurrence_until", window: daily, at: until.Add(time.Hour), want: false},
+		{name: "daily without recurrence_until", window: endless, at: start.Add(100*24*time.Hour + time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.ActiveAt(tt.at); got != tt.want {
+				t.Errorf("ActiveAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
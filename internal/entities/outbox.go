@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// OutboxEvent is an event stored in the transaction of the change causing it, kept until it is turned into
+// webhook deliveries.
+type OutboxEvent struct {
+	ID         int64
+	Type       string
+	ResourceID string
+	Data       map[string]any
+	CreatedAt  time.Time
+}
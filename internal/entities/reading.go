@@ -0,0 +1,75 @@
+package entities
+
+import "time"
+
+type SensorReading struct {
+	ID          int64     `json:"id"`
+	SensorID    string    `json:"sensor_id"`
+	DeviceID    string    `json:"device_id"`
+	Value       float64   `json:"value"`
+	Maintenance bool      `json:"maintenance"`
+	RecordedAt  time.Time `json:"recorded_at"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// AnomalyScore is set on ingest by the anomaly detector of the sensor, for the alert evaluation.
+	AnomalyScore *float64 `json:"-"`
+}
+
+type LatestSensorReading struct {
+	DeviceID   string     `json:"device_id"`
+	SensorID   string     `json:"sensor_id"`
+	SensorName string     `json:"sensor_name"`
+	SensorType SensorType `json:"sensor_type"`
+	Value      float64    `json:"value"`
+	RecordedAt time.Time  `json:"recorded_at"`
+}
+
+type SensorReadingPayload struct {
+	Value      float64   `json:"value" example:"27.5"`
+	RecordedAt time.Time `json:"recorded_at" example:"2024-03-01T10:00:00Z"`
+}
+
+type CreateSensorReadingsPayload struct {
+	Readings []SensorReadingPayload `json:"readings" validate:"required,min=1,max=1000,dive"`
+}
+
+type GetSensorReadingListParams struct {
+	SensorID           string
+	DeviceID           string
+	From               *time.Time
+	To                 *time.Time
+	ExcludeMaintenance bool
+	Raw                bool
+	Sort               string
+	Limit              int
+	Offset             int
+}
+
+type AggregateGroupBy string
+
+var (
+	AGGREGATE_GROUP_BY_ZONE         AggregateGroupBy = "zone"
+	AGGREGATE_GROUP_BY_SITE         AggregateGroupBy = "site"
+	AGGREGATE_GROUP_BY_ORGANIZATION AggregateGroupBy = "organization"
+)
+
+// ReadingAggregate rolls the readings of one sensor type up to a location.
+type ReadingAggregate struct {
+	LocationID   string     `json:"location_id"`
+	LocationName string     `json:"location_name"`
+	SensorType   SensorType `json:"sensor_type"`
+	Count        int64      `json:"count"`
+	Min          float64    `json:"min"`
+	Max          float64    `json:"max"`
+	Avg          float64    `json:"avg"`
+}
+
+type GetReadingAggregateParams struct {
+	GroupBy            AggregateGroupBy
+	Location           LocationFilter
+	SensorType         string
+	From               *time.Time
+	To                 *time.Time
+	ExcludeMaintenance bool
+	Raw                bool
+}
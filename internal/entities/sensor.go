@@ -26,31 +26,61 @@ var (
 )
 
 type Sensor struct {
-	ID          string     `json:"id"`
-	DeviceID    string     `json:"device_id"`
-	Type        SensorType `json:"type"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          string            `json:"id"`
+	DeviceID    string            `json:"device_id"`
+	Type        SensorType        `json:"type"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Attributes  map[string]any    `json:"attributes"`
+	Labels      map[string]string `json:"labels"`
+	Virtual     bool              `json:"virtual"`
+	Expression  string            `json:"expression,omitempty"`
+	Version     int               `json:"version"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
 }
 
 type CreateSensorPayload struct {
-	DeviceID    string     `json:"device_id" validate:"uuid" example:"d2431891-c5e4-462d-bf9b-7a194d5bebda"`
-	Type        SensorType `json:"type" validate:"sensorType" example:"temperature"`
-	Name        string     `json:"name" validate:"required" example:"Sensor #1"`
-	Description string     `json:"description" example:"First Sensor"`
+	DeviceID    string            `json:"device_id" validate:"uuid" example:"d2431891-c5e4-462d-bf9b-7a194d5bebda"`
+	Type        SensorType        `json:"type" validate:"sensorType" example:"temperature"`
+	Name        string            `json:"name" validate:"required" example:"Sensor #1"`
+	Description string            `json:"description" example:"First Sensor"`
+	Attributes  map[string]any    `json:"attributes"`
+	Labels      map[string]string `json:"labels" validate:"labels"`
+	Expression  string            `json:"expression" validate:"max=1000" example:"({7e1b7a4c-2f4e-4d59-9a43-2b8b5f0f6c11} + {0c9d3e52-6a1f-4b8e-8f2a-5d4c3b2a1e09}) / 2"`
 }
 
 type UpdateSensorPayload struct {
-	Name        string `json:"name" validate:"required" example:"Sensor #1.2"`
-	Description string `json:"description" example:"First Sensor v2"`
+	Name        string            `json:"name" validate:"required" example:"Sensor #1.2"`
+	Description string            `json:"description" example:"First Sensor v2"`
+	Attributes  map[string]any    `json:"attributes"`
+	Labels      map[string]string `json:"labels" validate:"labels"`
+	Expression  string            `json:"expression" validate:"max=1000"`
 }
 
 type GetSensorListParams struct {
-	DeviceID string
-	Search   string
-	Sort     string
-	Limit    int
-	Offset   int
+	DeviceID   string
+	Search     string
+	Attributes map[string]string
+	Selector   []LabelRequirement
+	DeviceIDs  []string
+	Location   LocationFilter
+	Virtual    *bool
+	Sort       string
+	Limit      int
+	Offset     int
+}
+
+// SensorDeviceAssociation is a period in which a sensor was attached to a device, DetachedAt is nil for the current one.
+type SensorDeviceAssociation struct {
+	ID         int64      `json:"id"`
+	SensorID   string     `json:"sensor_id"`
+	DeviceID   string     `json:"device_id"`
+	AttachedAt time.Time  `json:"attached_at"`
+	DetachedAt *time.Time `json:"detached_at"`
+}
+
+type MoveSensorPayload struct {
+	DeviceID string     `json:"device_id" validate:"uuid" example:"d2431891-c5e4-462d-bf9b-7a194d5bebda"`
+	MovedAt  *time.Time `json:"moved_at" example:"2024-03-01T10:00:00Z"`
 }
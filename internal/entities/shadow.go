@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"reflect"
+	"time"
+)
+
+type ShadowSection string
+
+var (
+	SHADOW_SECTION_DESIRED  ShadowSection = "desired"
+	SHADOW_SECTION_REPORTED ShadowSection = "reported"
+)
+
+type DeviceShadow struct {
+	DeviceID  string         `json:"device_id"`
+	Desired   map[string]any `json:"desired"`
+	Reported  map[string]any `json:"reported"`
+	Delta     map[string]any `json:"delta"`
+	Version   int64          `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type DeviceShadowDelta struct {
+	DeviceID string         `json:"device_id"`
+	Delta    map[string]any `json:"delta"`
+	Version  int64          `json:"version"`
+}
+
+// UpdateDeviceShadowPayload is a partial state merged into a shadow section. When Version is set the update only
+// applies to a shadow at that version, 0 for a shadow that was never written.
+type UpdateDeviceShadowPayload struct {
+	State   map[string]any `json:"state" validate:"required"`
+	Version *int64         `json:"version" validate:"omitempty,min=0" example:"3"`
+}
+
+// ComputeDelta returns every desired key whose value differs from the reported one.
+// Nested objects are compared key by key, so only the differing leaves are returned.
+func (s *DeviceShadow) ComputeDelta() map[string]any {
+	return shadowDelta(s.Desired, s.Reported)
+}
+
+// MergeShadowState applies a partial state document on top of the current one.
+// Keys set to null are removed, nested objects are merged recursively.
+func MergeShadowState(current, patch map[string]any) map[string]any {
+	result := make(map[string]any, len(current))
+	for k, v := range current {
+		result[k] = v
+	}
+
+	for k, v := range patch {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+
+		patchObj, ok := v.(map[string]any)
+		if !ok {
+			result[k] = v
+			continue
+		}
+
+		currentObj, _ := result[k].(map[string]any)
+		merged := MergeShadowState(currentObj, patchObj)
+		if len(merged) == 0 {
+			delete(result, k)
+			continue
+		}
+		result[k] = merged
+	}
+
+	return result
+}
+
+func shadowDelta(desired, reported map[string]any) map[string]any {
+	delta := map[string]any{}
+
+	for k, want := range desired {
+		got, ok := reported[k]
+		if !ok {
+			delta[k] = want
+			continue
+		}
+
+		wantObj, wantIsObj := want.(map[string]any)
+		gotObj, gotIsObj := got.(map[string]any)
+		if wantIsObj && gotIsObj {
+			if nested := shadowDelta(wantObj, gotObj); len(nested) > 0 {
+				delta[k] = nested
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(want, got) {
+			delta[k] = want
+		}
+	}
+
+	return delta
+}
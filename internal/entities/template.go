@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// DeviceTemplateDevice holds the default fields of devices created from a template.
+type DeviceTemplateDevice struct {
+	Description string            `json:"description" example:"Weather station"`
+	Status      DeviceStatus      `json:"status" validate:"omitempty,deviceStatus" example:"active"`
+	Attributes  map[string]any    `json:"attributes"`
+	Labels      map[string]string `json:"labels" validate:"labels"`
+}
+
+type DeviceTemplateSensor struct {
+	Type        SensorType        `json:"type" validate:"sensorType" example:"temperature"`
+	Name        string            `json:"name" validate:"required" example:"Air Temperature"`
+	Description string            `json:"description" example:"Air temperature at 2m"`
+	Attributes  map[string]any    `json:"attributes"`
+	Labels      map[string]string `json:"labels" validate:"labels"`
+}
+
+type DeviceTemplate struct {
+	ID          string                 `json:"id"`
+	Slug        string                 `json:"slug"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Device      DeviceTemplateDevice   `json:"device"`
+	Sensors     []DeviceTemplateSensor `json:"sensors"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+type CreateUpdateDeviceTemplatePayload struct {
+	Slug        string                 `json:"slug" validate:"required,max=100,slug" example:"weather-station-v2"`
+	Name        string                 `json:"name" validate:"required,max=100" example:"Weather Station v2"`
+	Description string                 `json:"description" example:"Standard weather station with 6 sensors"`
+	Device      DeviceTemplateDevice   `json:"device"`
+	Sensors     []DeviceTemplateSensor `json:"sensors" validate:"max=100,dive"`
+}
+
+type GetDeviceTemplateListParams struct {
+	Search string
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// DeviceWithSensors is a device together with the sensors created for it.
+type DeviceWithSensors struct {
+	Device
+	Sensors []*Sensor `json:"sensors"`
+}
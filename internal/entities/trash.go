@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+type TrashItemType string
+
+var (
+	TRASH_ITEM_TYPE_DEVICE TrashItemType = "device"
+	TRASH_ITEM_TYPE_SENSOR TrashItemType = "sensor"
+)
+
+// TrashItem is a deleted device or sensor, DeviceID is set for sensors. Items are purged once they have been
+// in the trash longer than the retention.
+type TrashItem struct {
+	Type      TrashItemType `json:"type"`
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	DeviceID  *string       `json:"device_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	DeletedAt time.Time     `json:"deleted_at"`
+}
+
+type GetTrashListParams struct {
+	Type   TrashItemType
+	Search string
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// PurgedTrash is the number of devices and sensors purged from the trash.
+type PurgedTrash struct {
+	Devices int64 `json:"devices"`
+	Sensors int64 `json:"sensors"`
+}
@@ -0,0 +1,76 @@
+package entities
+
+import "time"
+
+type WebhookDeliveryStatus string
+
+var (
+	WEBHOOK_DELIVERY_STATUS_PENDING   WebhookDeliveryStatus = "pending"
+	WEBHOOK_DELIVERY_STATUS_SUCCEEDED WebhookDeliveryStatus = "succeeded"
+	WEBHOOK_DELIVERY_STATUS_FAILED    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook receives the events matching its filter, signed with its secret. The secret is never returned.
+type Webhook struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateUpdateWebhookPayload struct {
+	Name    string   `json:"name" validate:"required,max=100" example:"Farm dashboard"`
+	URL     string   `json:"url" validate:"required,max=2000,http_url" example:"https://example.com/hooks/iot"`
+	Secret  string   `json:"secret" validate:"required,min=16,max=200" example:"5f2b8c1e9a7d4e3f"`
+	Events  []string `json:"events" validate:"max=50,dive,required,max=100" example:"alert.*"`
+	Enabled *bool    `json:"enabled" example:"true"`
+}
+
+type GetWebhookListParams struct {
+	Search string
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// WebhookDelivery is one event sent to a webhook, with the state of its retries.
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	WebhookID      string                `json:"webhook_id"`
+	EventType      string                `json:"event_type"`
+	Payload        map[string]any        `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code"`
+	LastError      string                `json:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	RedeliveryOf   *string               `json:"redelivery_of"`
+	AttemptLog     []*WebhookAttempt     `json:"attempt_log,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// WebhookAttempt is one request of a delivery. StatusCode is nil when no response was received.
+type WebhookAttempt struct {
+	ID           string    `json:"id"`
+	DeliveryID   string    `json:"delivery_id"`
+	StatusCode   *int      `json:"status_code"`
+	Error        string    `json:"error"`
+	ResponseBody string    `json:"response_body"`
+	DurationMs   int       `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type GetWebhookDeliveryListParams struct {
+	WebhookID string
+	Status    string
+	EventType string
+	Sort      string
+	Limit     int
+	Offset    int
+}
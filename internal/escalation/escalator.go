@@ -0,0 +1,273 @@
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/internal/notify"
+	"go-api/internal/repositories"
+	"go-api/internal/webhooks"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EVENT_TYPE is the event type of the escalation notifications sent to webhooks. It is not published on
+// the broker, only the webhooks of a step receive it.
+const EVENT_TYPE = "alert.escalation"
+
+// dueBatch is the number of due escalations notified per run.
+const dueBatch = 500
+
+// Message is the data of an escalation notification sent to a webhook.
+type Message struct {
+	PolicyID   string            `json:"policy_id"`
+	PolicyName string            `json:"policy_name"`
+	Step       int               `json:"step"`
+	Repeat     bool              `json:"repeat"`
+	GroupKey   string            `json:"group_key"`
+	Alerts     []*entities.Alert `json:"alerts"`
+}
+
+// Escalator notifies the firing alerts of rules with an escalation policy, step by step, to the email
+// recipients and webhooks of the policy. Escalations are started in the transaction of the alert firing and
+// their progress is stored, so escalations survive a restart. email is nil when SMTP is not configured.
+type Escalator struct {
+	repo     repositories.IRepository
+	webhooks *webhooks.Dispatcher
+	email    *notify.EmailNotifier
+}
+
+func NewEscalator(repo repositories.IRepository, dispatcher *webhooks.Dispatcher, email *notify.EmailNotifier) *Escalator {
+	return &Escalator{
+		repo:     repo,
+		webhooks: dispatcher,
+		email:    email,
+	}
+}
+
+// GroupKey returns the values of the group by fields of a policy for an alert, such as "rule=<id>".
+// Without group by fields every alert of the policy is in the same group.
+func GroupKey(policy *entities.EscalationPolicy, alert *entities.Alert) string {
+	parts := []string{}
+	for _, v := range policy.GroupBy {
+		switch v {
+		case entities.ESCALATION_GROUP_BY_RULE:
+			parts = append(parts, "rule="+alert.RuleID)
+		case entities.ESCALATION_GROUP_BY_DEVICE:
+			parts = append(parts, "device="+alert.DeviceID)
+		case entities.ESCALATION_GROUP_BY_SENSOR:
+			parts = append(parts, "sensor="+alert.SensorID)
+		case entities.ESCALATION_GROUP_BY_SEVERITY:
+			parts = append(parts, "severity="+string(alert.Severity))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// group is the due escalations notified in one message, the same step of the same group of a policy.
+type group struct {
+	policy      *entities.EscalationPolicy
+	key         string
+	step        int
+	escalations []*entities.AlertEscalation
+}
+
+// NotifyDue notifies the due escalations, one message per group and step. Escalations of alerts that are no
+// longer firing, were acknowledged or whose policy is disabled stop, snoozed ones wait for the snooze to end.
+// A group notified less than the group interval ago waits for it and a policy over its rate limit keeps its
+// due escalations for the next notification the limit allows.
+func (e *Escalator) NotifyDue(ctx context.Context, now time.Time) error {
+	due, err := e.repo.GetDueAlertEscalations(ctx, now, dueBatch)
+	if err != nil {
+		return err
+	}
+
+	policies := map[string]*entities.EscalationPolicy{}
+	groups := map[string]*group{}
+	idle := []*entities.AlertEscalation{}
+	for _, escalation := range due {
+		policy, ok := policies[escalation.PolicyID]
+		if !ok {
+			policy, err = e.repo.GetEscalationPolicy(ctx, escalation.PolicyID)
+			if err != nil {
+				return err
+			}
+			policies[escalation.PolicyID] = policy
+		}
+
+		alert := escalation.Alert
+		switch {
+		case !policy.Enabled || len(policy.Steps) == 0 || alert.Status != entities.ALERT_STATUS_FIRING || alert.AcknowledgedAt != nil:
+			escalation.NextNotifyAt = nil
+			idle = append(idle, escalation)
+			continue
+		case alert.Snoozed(now):
+			until := *alert.SnoozedUntil
+			escalation.NextNotifyAt = &until
+			idle = append(idle, escalation)
+			continue
+		}
+
+		step := min(escalation.Step, len(policy.Steps)-1)
+		key := GroupKey(policy, alert)
+		id := fmt.Sprintf("%s|%s|%d", policy.ID, key, step)
+
+		g, ok := groups[id]
+		if !ok {
+			g = &group{policy: policy, key: key, step: step}
+			groups[id] = g
+		}
+		g.escalations = append(g.escalations, escalation)
+	}
+
+	if len(idle) > 0 {
+		err = e.repo.SaveAlertEscalations(ctx, idle, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	ids := []string{}
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// the group interval is checked against the notifications before this run, so the steps of a group
+	// due together are all sent
+	lastNotified := map[string]*time.Time{}
+	for _, id := range ids {
+		g := groups[id]
+
+		lastKey := g.policy.ID + "|" + g.key
+		last, ok := lastNotified[lastKey]
+		if !ok {
+			last, err = e.repo.GetLastEscalationNotificationAt(ctx, g.policy.ID, g.key)
+			if err != nil {
+				return err
+			}
+			lastNotified[lastKey] = last
+		}
+
+		if g.policy.GroupInterval > 0 && last != nil {
+			next := last.Add(time.Duration(g.policy.GroupInterval) * time.Second)
+			if next.After(now) {
+				err = e.postpone(ctx, g, next)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if g.policy.RateLimit > 0 {
+			sent, err := e.repo.GetEscalationNotificationTimes(ctx, g.policy.ID, now.Add(-time.Hour))
+			if err != nil {
+				return err
+			}
+			if len(sent) >= g.policy.RateLimit {
+				// the limit allows a notification once enough of the last hour's ones are more than an hour old
+				err = e.postpone(ctx, g, sent[len(sent)-g.policy.RateLimit].Add(time.Hour))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		err = e.notify(ctx, g, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// postpone moves the next notification of the escalations of a group to next.
+func (e *Escalator) postpone(ctx context.Context, g *group, next time.Time) error {
+	for _, v := range g.escalations {
+		v.NextNotifyAt = &next
+	}
+	return e.repo.SaveAlertEscalations(ctx, g.escalations, nil)
+}
+
+// notify sends the step of a group to its channels and advances the escalations of the group. A channel
+// that fails is recorded in the notification, webhook deliveries are retried by the dispatcher.
+func (e *Escalator) notify(ctx context.Context, g *group, now time.Time) error {
+	step := g.policy.Steps[g.step]
+
+	notification := &entities.EscalationNotification{
+		PolicyID:   g.policy.ID,
+		Step:       g.step + 1,
+		Repeat:     true,
+		GroupKey:   g.key,
+		AlertIDs:   []string{},
+		Emails:     step.Emails,
+		WebhookIDs: step.WebhookIDs,
+		CreatedAt:  now,
+	}
+	alerts := []*entities.Alert{}
+	for _, v := range g.escalations {
+		alerts = append(alerts, v.Alert)
+		notification.AlertIDs = append(notification.AlertIDs, v.AlertID)
+		if v.Step < len(g.policy.Steps) {
+			notification.Repeat = false
+		}
+	}
+
+	errs := []string{}
+	if len(step.Emails) > 0 {
+		switch {
+		case e.email == nil:
+			errs = append(errs, "email is not configured")
+		default:
+			escalation := fmt.Sprintf("%s step %d", g.policy.Name, notification.Step)
+			err := e.email.SendEscalation(step.Emails, escalation, alerts)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("email %s", err.Error()))
+			}
+		}
+	}
+
+	for _, webhookID := range step.WebhookIDs {
+		webhook, err := e.repo.GetWebhook(ctx, webhookID)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("webhook %s %s", webhookID, err.Error()))
+			continue
+		}
+		if !webhook.Enabled {
+			errs = append(errs, fmt.Sprintf("webhook %s is disabled", webhookID))
+			continue
+		}
+
+		err = e.webhooks.Deliver(ctx, webhook, events.Event{
+			Type:       EVENT_TYPE,
+			ResourceID: g.policy.ID,
+			Data: Message{
+				PolicyID:   g.policy.ID,
+				PolicyName: g.policy.Name,
+				Step:       notification.Step,
+				Repeat:     notification.Repeat,
+				GroupKey:   g.key,
+				Alerts:     alerts,
+			},
+			CreatedAt: now,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("webhook %s %s", webhookID, err.Error()))
+		}
+	}
+	notification.Error = strings.Join(errs, "; ")
+
+	notifiedAt := now
+	for _, v := range g.escalations {
+		v.Step = g.step + 1
+		v.NotifiedAt = &notifiedAt
+		v.NextNotifyAt = g.policy.NextNotifyAt(v)
+	}
+
+	return e.repo.SaveAlertEscalations(ctx, g.escalations, notification)
+}
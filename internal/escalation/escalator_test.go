@@ -0,0 +1,143 @@
+package escalation
+
+import (
+	"context"
+	"go-api/internal/entities"
+	"go-api/internal/repositories"
+	"testing"
+	"time"
+)
+
+// fakeRepository serves one policy, its due escalations and its notifications of the last hour, the other
+// methods of the interface are not used by NotifyDue and panic.
+type fakeRepository struct {
+	repositories.IRepository
+
+	policy        *entities.EscalationPolicy
+	due           []*entities.AlertEscalation
+	sent          []time.Time
+	saved         []*entities.AlertEscalation
+	notifications []*entities.EscalationNotification
+}
+
+func (r *fakeRepository) GetDueAlertEscalations(ctx context.Context, now time.Time, limit int) ([]*entities.AlertEscalation, error) {
+	return r.due, nil
+}
+
+func (r *fakeRepository) GetEscalationPolicy(ctx context.Context, policyID string) (*entities.EscalationPolicy, error) {
+	return r.policy, nil
+}
+
+func (r *fakeRepository) GetLastEscalationNotificationAt(ctx context.Context, policyID, groupKey string) (*time.Time, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetEscalationNotificationTimes(ctx context.Context, policyID string, after time.Time) ([]time.Time, error) {
+	return r.sent, nil
+}
+
+func (r *fakeRepository) SaveAlertEscalations(ctx context.Context, escalations []*entities.AlertEscalation, notification *entities.EscalationNotification) error {
+	r.saved = append(r.saved, escalations...)
+	if notification != nil {
+		r.notifications = append(r.notifications, notification)
+	}
+	return nil
+}
+
+func TestGroupKey(t *testing.T) {
+	alert := &entities.Alert{
+		RuleID:   "rule-1",
+		DeviceID: "device-1",
+		SensorID: "sensor-1",
+		Severity: entities.ALERT_SEVERITY_CRITICAL,
+	}
+
+	tests := []struct {
+		groupBy []entities.EscalationGroupBy
+		want    string
+	}{
+		{nil, ""},
+		{[]entities.EscalationGroupBy{entities.ESCALATION_GROUP_BY_RULE}, "rule=rule-1"},
+		{[]entities.EscalationGroupBy{entities.ESCALATION_GROUP_BY_DEVICE, entities.ESCALATION_GROUP_BY_SEVERITY}, "device=device-1,severity=critical"},
+		{[]entities.EscalationGroupBy{entities.ESCALATION_GROUP_BY_SEVERITY, entities.ESCALATION_GROUP_BY_DEVICE}, "severity=critical,device=device-1"},
+		{[]entities.EscalationGroupBy{entities.ESCALATION_GROUP_BY_RULE, entities.ESCALATION_GROUP_BY_SENSOR}, "rule=rule-1,sensor=sensor-1"},
+	}
+
+	for _, tt := range tests {
+		policy := &entities.EscalationPolicy{GroupBy: tt.groupBy}
+		if got := GroupKey(policy, alert); got != tt.want {
+			t.Errorf("GroupKey(%v) = %q, want %q", tt.groupBy, got, tt.want)
+		}
+	}
+}
+
+func TestNotifyDueRateLimit(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		sent     []time.Time
+		notified bool
+		next     time.Time
+	}{
+		{
+			name:     "under the limit",
+			sent:     []time.Time{now.Add(-50 * time.Minute)},
+			notified: true,
+		},
+		{
+			name: "at the limit waits for the oldest to leave the hour",
+			sent: []time.Time{now.Add(-50 * time.Minute), now.Add(-20 * time.Minute)},
+			next: now.Add(10 * time.Minute),
+		},
+		{
+			name: "over a lowered limit waits until two are gone",
+			sent: []time.Time{now.Add(-50 * time.Minute), now.Add(-40 * time.Minute), now.Add(-5 * time.Minute)},
+			next: now.Add(20 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due := now.Add(-time.Minute)
+			escalation := &entities.AlertEscalation{
+				AlertID:      "alert-1",
+				PolicyID:     "policy-1",
+				FiredAt:      now.Add(-time.Hour),
+				NextNotifyAt: &due,
+				Alert:        &entities.Alert{ID: "alert-1", RuleID: "rule-1", Status: entities.ALERT_STATUS_FIRING},
+			}
+			repo := &fakeRepository{
+				policy: &entities.EscalationPolicy{
+					ID:        "policy-1",
+					Steps:     []entities.EscalationStep{{Delay: 0}, {Delay: 600}},
+					RateLimit: 2,
+					Enabled:   true,
+				},
+				due:  []*entities.AlertEscalation{escalation},
+				sent: tt.sent,
+			}
+
+			err := NewEscalator(repo, nil, nil).NotifyDue(context.Background(), now)
+			if err != nil {
+				t.Fatalf("NotifyDue() error = %v", err)
+			}
+
+			if got := len(repo.notifications) == 1; got != tt.notified {
+				t.Fatalf("notified = %v, want %v", got, tt.notified)
+			}
+			if len(repo.saved) != 1 {
+				t.Fatalf("saved %d escalations, want 1", len(repo.saved))
+			}
+			if tt.notified {
+				if escalation.Step != 1 {
+					t.Errorf("step = %d, want 1", escalation.Step)
+				}
+				return
+			}
+			if escalation.Step != 0 || escalation.NextNotifyAt == nil || !escalation.NextNotifyAt.Equal(tt.next) {
+				t.Errorf("escalation = step %d next %v, want step 0 next %v", escalation.Step, escalation.NextNotifyAt, tt.next)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEVICE_SHADOW_UPDATED       = "device.shadow.updated"
+	DEVICE_COMMAND_CREATED      = "device.command.created"
+	DEVICE_COMMAND_ACKNOWLEDGED = "device.command.acknowledged"
+	DEVICE_COMMAND_EXPIRED      = "device.command.expired"
+	ALERT_PENDING               = "alert.pending"
+	ALERT_FIRING                = "alert.firing"
+	ALERT_RESOLVED              = "alert.resolved"
+	ALERT_ACKNOWLEDGED          = "alert.acknowledged"
+	ALERT_ASSIGNED              = "alert.assigned"
+	ALERT_COMMENTED             = "alert.commented"
+	ALERT_SNOOZED               = "alert.snoozed"
+	SENSOR_ANOMALY              = "sensor.anomaly"
+)
+
+// TYPES lists every published event type.
+var TYPES = []string{
+	DEVICE_SHADOW_UPDATED,
+	DEVICE_COMMAND_CREATED,
+	DEVICE_COMMAND_ACKNOWLEDGED,
+	DEVICE_COMMAND_EXPIRED,
+	ALERT_PENDING,
+	ALERT_FIRING,
+	ALERT_RESOLVED,
+	ALERT_ACKNOWLEDGED,
+	ALERT_ASSIGNED,
+	ALERT_COMMENTED,
+	ALERT_SNOOZED,
+	SENSOR_ANOMALY,
+}
+
+// subscriberBuffer is the number of events kept for a slow subscriber before new ones are dropped.
+const subscriberBuffer = 16
+
+type Event struct {
+	Type       string    `json:"type"`
+	ResourceID string    `json:"resource_id"`
+	Data       any       `json:"data,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type subscriber struct {
+	ch     chan Event
+	filter func(Event) bool
+
+	// a queued subscriber keeps the events its buffer has no room for in pending, forward moves them to ch
+	queued  bool
+	mu      sync.Mutex
+	pending []Event
+	wake    chan struct{}
+	done    chan struct{}
+}
+
+// forward sends the pending events of a queued subscriber in order until it unsubscribes.
+func (s *subscriber) forward() {
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		event := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- event:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Broker is an in-process publish/subscribe hub for change notifications.
+type Broker struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]*subscriber
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: map[int]*subscriber{},
+	}
+}
+
+// Publish delivers the event to every subscriber whose filter accepts it.
+// It never blocks, a subscriber with a full buffer misses the event unless it is queued.
+func (b *Broker) Publish(event Event) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, s := range b.subscribers {
+		if s.filter != nil && !s.filter(event) {
+			continue
+		}
+
+		if s.queued {
+			s.mu.Lock()
+			s.pending = append(s.pending, event)
+			s.mu.Unlock()
+
+			select {
+			case s.wake <- struct{}{}:
+			default:
+			}
+			continue
+		}
+
+		select {
+		case s.ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a subscriber for events accepted by filter (all events when nil).
+// The returned function must be called to release the subscription.
+func (b *Broker) Subscribe(filter func(Event) bool) (<-chan Event, func()) {
+	return b.subscribe(filter, false)
+}
+
+// SubscribeQueued registers a subscriber like Subscribe that never misses an event: the events a slow
+// subscriber has no room for are queued in memory until it receives them.
+func (b *Broker) SubscribeQueued(filter func(Event) bool) (<-chan Event, func()) {
+	return b.subscribe(filter, true)
+}
+
+func (b *Broker) subscribe(filter func(Event) bool, queued bool) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	s := &subscriber{
+		ch:     make(chan Event, subscriberBuffer),
+		filter: filter,
+		queued: queued,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	b.subscribers[id] = s
+	if queued {
+		go s.forward()
+	}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers, id)
+			close(s.done)
+		})
+	}
+
+	return s.ch, unsubscribe
+}
+
+// ForResource returns a filter matching events of the given type and resource.
+func ForResource(eventType, resourceID string) func(Event) bool {
+	return func(e Event) bool {
+		return e.Type == eventType && e.ResourceID == resourceID
+	}
+}
+
+// Matches reports whether the event type matches a pattern, an event type, a prefix ending in ".*" such as
+// "alert.*", or "*" for every event.
+func Matches(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, "*")
+	return ok && strings.HasSuffix(prefix, ".") && strings.HasPrefix(eventType, prefix)
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	broker := NewBroker()
+	received, unsubscribe := broker.Subscribe(nil)
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		broker.Publish(Event{Type: ALERT_FIRING, ResourceID: fmt.Sprint(i)})
+	}
+
+	if got := len(received); got != subscriberBuffer {
+		t.Errorf("buffered %d events, want %d", got, subscriberBuffer)
+	}
+}
+
+func TestSubscribeQueuedKeepsEveryEvent(t *testing.T) {
+	broker := NewBroker()
+	received, unsubscribe := broker.SubscribeQueued(func(e Event) bool {
+		return e.Type == ALERT_FIRING
+	})
+	defer unsubscribe()
+
+	n := subscriberBuffer * 10
+	for i := 0; i < n; i++ {
+		broker.Publish(Event{Type: ALERT_FIRING, ResourceID: fmt.Sprint(i)})
+		broker.Publish(Event{Type: ALERT_RESOLVED, ResourceID: fmt.Sprint(i)})
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case event := <-received:
+			if event.ResourceID != fmt.Sprint(i) || event.Type != ALERT_FIRING {
+				t.Fatalf("event %d = %+v, want the firing event of %d", i, event, i)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("received %d events, want %d", i, n)
+		}
+	}
+
+	select {
+	case event := <-received:
+		t.Errorf("received %+v, want no more events", event)
+	default:
+	}
+}
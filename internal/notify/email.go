@@ -0,0 +1,325 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/internal/repositories"
+	"go-api/pkg/mail"
+	htmltemplate "html/template"
+	"log/slog"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// maxReportAlerts is the number of alerts listed in a report, the rest is only counted.
+const maxReportAlerts = 100
+
+var templateFuncs = map[string]any{
+	"upper": func(v any) string {
+		return strings.ToUpper(fmt.Sprint(v))
+	},
+	"datetime": func(v any) string {
+		switch t := v.(type) {
+		case time.Time:
+			return t.UTC().Format("2006-01-02 15:04 UTC")
+		case *time.Time:
+			if t != nil {
+				return t.UTC().Format("2006-01-02 15:04 UTC")
+			}
+		}
+		return ""
+	},
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02")
+	},
+}
+
+// templates renders a message from <name>.txt.tmpl, which also defines the "subject" template,
+// and <name>.html.tmpl.
+type templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+func parseTemplates(name string) (*templates, error) {
+	text, err := texttemplate.New(name+".txt.tmpl").Funcs(templateFuncs).ParseFS(templateFS, "templates/"+name+".txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := htmltemplate.New(name+".html.tmpl").Funcs(templateFuncs).ParseFS(templateFS, "templates/"+name+".html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	return &templates{text: text, html: html}, nil
+}
+
+func (t *templates) render(from string, to []string, data any) (mail.Message, error) {
+	msg := mail.Message{
+		From: from,
+		To:   to,
+	}
+
+	var buf bytes.Buffer
+	if err := t.text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return msg, err
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := t.text.Execute(&buf, data); err != nil {
+		return msg, err
+	}
+	msg.Text = buf.String()
+
+	buf.Reset()
+	if err := t.html.Execute(&buf, data); err != nil {
+		return msg, err
+	}
+	msg.HTML = buf.String()
+
+	return msg, nil
+}
+
+type alertDigest struct {
+	Alerts     []*entities.Alert
+	Firing     int
+	Resolved   int
+	Escalation string
+}
+
+type alertReport struct {
+	GeneratedAt time.Time
+	Firing      int64
+	Pending     int64
+	Total       int64
+	More        int64
+	Alerts      []*entities.Alert
+}
+
+// batch is the alerts waiting to be sent to one recipient, with the latest state of each alert.
+type batch struct {
+	alerts []*entities.Alert
+	timer  *time.Timer
+}
+
+// EmailNotifier emails firing and resolved alerts to the recipients of their rule. The alerts of a recipient
+// are collected for a window starting with the first one and sent as one message.
+type EmailNotifier struct {
+	repo   repositories.IRepository
+	sender mail.Sender
+	from   string
+	window time.Duration
+	alerts *templates
+	report *templates
+
+	mu      sync.Mutex
+	batches map[string]*batch
+}
+
+func NewEmailNotifier(repo repositories.IRepository, sender mail.Sender, from string, window time.Duration) (*EmailNotifier, error) {
+	alerts, err := parseTemplates("alerts")
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := parseTemplates("report")
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailNotifier{
+		repo:    repo,
+		sender:  sender,
+		from:    from,
+		window:  window,
+		alerts:  alerts,
+		report:  report,
+		batches: map[string]*batch{},
+	}, nil
+}
+
+// Run notifies the alert events of the broker until ctx is cancelled, then sends the waiting batches.
+// The subscription is queued, so alerts changing faster than they are notified are not missed.
+func (n *EmailNotifier) Run(ctx context.Context, broker *events.Broker) {
+	received, unsubscribe := broker.SubscribeQueued(func(e events.Event) bool {
+		return e.Type == events.ALERT_FIRING || e.Type == events.ALERT_RESOLVED
+	})
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			n.FlushAll()
+			return
+		case event := <-received:
+			alert, ok := event.Data.(*entities.Alert)
+			if !ok {
+				continue
+			}
+
+			err := n.Notify(ctx, alert)
+			if err != nil {
+				slog.Error(
+					"Failed to notify alert by email",
+					slog.Any("err", err),
+					slog.String("alertID", alert.ID),
+				)
+			}
+		}
+	}
+}
+
+// Notify queues the alert for the recipients of its rule, snoozed alerts are left out.
+func (n *EmailNotifier) Notify(ctx context.Context, alert *entities.Alert) error {
+	if alert.Snoozed(time.Now()) {
+		return nil
+	}
+
+	rule, err := n.repo.GetAlertRule(ctx, alert.RuleID)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range rule.NotifyEmails {
+		n.queue(recipient, alert)
+	}
+
+	return nil
+}
+
+func (n *EmailNotifier) queue(recipient string, alert *entities.Alert) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	b, ok := n.batches[recipient]
+	if !ok {
+		b = &batch{}
+		b.timer = time.AfterFunc(n.window, func() {
+			n.flush(recipient)
+		})
+		n.batches[recipient] = b
+	}
+
+	for i, v := range b.alerts {
+		if v.ID == alert.ID {
+			b.alerts[i] = alert
+			return
+		}
+	}
+	b.alerts = append(b.alerts, alert)
+}
+
+// FlushAll sends every waiting batch right away.
+func (n *EmailNotifier) FlushAll() {
+	n.mu.Lock()
+	recipients := []string{}
+	for recipient := range n.batches {
+		recipients = append(recipients, recipient)
+	}
+	n.mu.Unlock()
+
+	for _, recipient := range recipients {
+		n.flush(recipient)
+	}
+}
+
+func (n *EmailNotifier) flush(recipient string) {
+	n.mu.Lock()
+	b, ok := n.batches[recipient]
+	delete(n.batches, recipient)
+	n.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	b.timer.Stop()
+
+	data := alertDigest{Alerts: b.alerts}
+	for _, v := range b.alerts {
+		switch v.Status {
+		case entities.ALERT_STATUS_FIRING:
+			data.Firing++
+		case entities.ALERT_STATUS_RESOLVED:
+			data.Resolved++
+		}
+	}
+
+	err := n.send(n.alerts, []string{recipient}, data)
+	if err != nil {
+		slog.Error(
+			"Failed to send alert email",
+			slog.Any("err", err),
+			slog.String("recipient", recipient),
+			slog.Int("alerts", len(b.alerts)),
+		)
+	}
+}
+
+// SendEscalation emails the alerts of an escalation notification right away, they are grouped already.
+// The escalation, such as the policy name and step, prefixes the subject.
+func (n *EmailNotifier) SendEscalation(to []string, escalation string, alerts []*entities.Alert) error {
+	data := alertDigest{Alerts: alerts, Escalation: escalation}
+	for _, v := range alerts {
+		switch v.Status {
+		case entities.ALERT_STATUS_FIRING:
+			data.Firing++
+		case entities.ALERT_STATUS_RESOLVED:
+			data.Resolved++
+		}
+	}
+
+	return n.send(n.alerts, to, data)
+}
+
+// SendReport emails a summary of the firing and pending alerts.
+func (n *EmailNotifier) SendReport(ctx context.Context, to []string, now time.Time) error {
+	data := alertReport{GeneratedAt: now}
+
+	var err error
+	_, data.Firing, err = n.repo.GetAlertList(ctx, entities.GetAlertListParams{
+		Statuses: []entities.AlertStatus{entities.ALERT_STATUS_FIRING},
+		Limit:    1,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, data.Pending, err = n.repo.GetAlertList(ctx, entities.GetAlertListParams{
+		Statuses: []entities.AlertStatus{entities.ALERT_STATUS_PENDING},
+		Limit:    1,
+	})
+	if err != nil {
+		return err
+	}
+
+	data.Alerts, data.Total, err = n.repo.GetAlertList(ctx, entities.GetAlertListParams{
+		Statuses: []entities.AlertStatus{entities.ALERT_STATUS_FIRING, entities.ALERT_STATUS_PENDING},
+		Sort:     "status",
+		Limit:    maxReportAlerts,
+	})
+	if err != nil {
+		return err
+	}
+	data.More = data.Total - int64(len(data.Alerts))
+
+	return n.send(n.report, to, data)
+}
+
+func (n *EmailNotifier) send(t *templates, to []string, data any) error {
+	msg, err := t.render(n.from, to, data)
+	if err != nil {
+		return err
+	}
+	return n.sender.Send(msg)
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"go-api/internal/entities"
+	"go-api/internal/repositories"
+	"go-api/pkg/mail"
+	"go-api/pkg/mail/mailtest"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRepository returns the alert rules it holds, the other methods of the interface are not used
+// by the notifier and panic.
+type fakeRepository struct {
+	repositories.IRepository
+
+	rules map[string]*entities.AlertRule
+}
+
+func (r *fakeRepository) GetAlertRule(ctx context.Context, ruleID string) (*entities.AlertRule, error) {
+	return r.rules[ruleID], nil
+}
+
+// messageText returns the subject and the plain text part of a received message.
+func messageText(t *testing.T, msg mailtest.Message) (string, string) {
+	t.Helper()
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(msg.Data))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("DecodeHeader() error = %v", err)
+	}
+
+	_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("ParseMediaType() error = %v", err)
+	}
+	part, err := multipart.NewReader(parsed.Body, params["boundary"]).NextPart()
+	if err != nil {
+		t.Fatalf("NextPart() error = %v", err)
+	}
+	text, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	return subject, string(text)
+}
+
+func TestEmailNotifierBatchesAlerts(t *testing.T) {
+	server, err := mailtest.NewServer()
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	defer server.Close()
+
+	repo := &fakeRepository{rules: map[string]*entities.AlertRule{
+		"rule-1": {ID: "rule-1", Name: "Dry soil", NotifyEmails: []string{"jane@example.com"}},
+		"rule-2": {ID: "rule-2", Name: "Hot greenhouse", NotifyEmails: []string{"jane@example.com"}},
+	}}
+
+	window := 200 * time.Millisecond
+	notifier, err := NewEmailNotifier(repo, mail.NewSMTP(server.Host, server.Port, "", ""), "alerts@example.com", window)
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	firedAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	alerts := []*entities.Alert{
+		{ID: "alert-1", RuleID: "rule-1", RuleName: "Dry soil", Status: entities.ALERT_STATUS_FIRING, SensorID: "sensor-1", Value: 12, FiredAt: &firedAt},
+		{ID: "alert-2", RuleID: "rule-2", RuleName: "Hot greenhouse", Status: entities.ALERT_STATUS_FIRING, SensorID: "sensor-2", Value: 41, FiredAt: &firedAt},
+		// the latest state of an alert replaces the queued one
+		{ID: "alert-1", RuleID: "rule-1", RuleName: "Dry soil", Status: entities.ALERT_STATUS_RESOLVED, SensorID: "sensor-1", Value: 30, FiredAt: &firedAt},
+		{ID: "alert-3", RuleID: "rule-1", RuleName: "Dry soil", Status: entities.ALERT_STATUS_FIRING, SensorID: "sensor-3", Value: 9, FiredAt: &firedAt},
+	}
+	for _, alert := range alerts {
+		if err := notifier.Notify(context.Background(), alert); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+
+	if got := len(server.Messages()); got != 0 {
+		t.Fatalf("received %d messages before the window ended, want 0", got)
+	}
+
+	var msg mailtest.Message
+	select {
+	case msg = <-server.Received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received after the window")
+	}
+
+	// nothing else is sent for the batch
+	time.Sleep(2 * window)
+	if got := len(server.Messages()); got != 1 {
+		t.Fatalf("received %d messages, want 1", got)
+	}
+
+	if len(msg.To) != 1 || msg.To[0] != "jane@example.com" {
+		t.Errorf("to = %v, want [jane@example.com]", msg.To)
+	}
+
+	subject, text := messageText(t, msg)
+	if subject != "2 firing, 1 resolved alerts" {
+		t.Errorf("subject = %q, want %q", subject, "2 firing, 1 resolved alerts")
+	}
+	for _, want := range []string{"[RESOLVED] Dry soil", "[FIRING] Hot greenhouse", "Sensor:   sensor-3"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text does not contain %q:\n%s", want, text)
+		}
+	}
+	if strings.Count(text, "Sensor:   sensor-1") != 1 {
+		t.Errorf("text lists alert-1 %d times, want once:\n%s", strings.Count(text, "Sensor:   sensor-1"), text)
+	}
+}
+
+func TestEmailNotifierSkipsSnoozedAlerts(t *testing.T) {
+	server, err := mailtest.NewServer()
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	defer server.Close()
+
+	repo := &fakeRepository{rules: map[string]*entities.AlertRule{
+		"rule-1": {ID: "rule-1", Name: "Dry soil", NotifyEmails: []string{"jane@example.com"}},
+	}}
+
+	notifier, err := NewEmailNotifier(repo, mail.NewSMTP(server.Host, server.Port, "", ""), "alerts@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	until := time.Now().Add(time.Hour)
+	err = notifier.Notify(context.Background(), &entities.Alert{ID: "alert-1", RuleID: "rule-1", Status: entities.ALERT_STATUS_FIRING, SnoozedUntil: &until})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	notifier.FlushAll()
+	if got := len(server.Messages()); got != 0 {
+		t.Errorf("received %d messages, want 0", got)
+	}
+}
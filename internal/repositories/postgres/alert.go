@@ -0,0 +1,968 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	alertRuleColumns = `id, name, description, sensor_id, sensor_type, condition, operator, threshold, clear_threshold,
+	for_seconds, window_seconds, severity, notify_emails, expression, escalation_policy_id, enabled, created_at, updated_at`
+	alertColumns = `a.id, a.rule_id, r.name AS rule_name, r.severity, COALESCE(a.sensor_id::text, '') AS sensor_id,
+	COALESCE(a.device_id::text, '') AS device_id, a.status, a.value,
+	a.pending_since, a.fired_at, a.resolved_at, a.last_evaluated_at, a.acknowledged_at, a.acknowledged_by, a.assignee,
+	a.snoozed_until, a.resolved_by, a.created_at, a.updated_at`
+	alertEventColumns = `id, alert_id, type, actor, note, status, value, assignee, snoozed_until, created_at`
+)
+
+type AlertRule struct {
+	ID                 string         `db:"id"`
+	Name               string         `db:"name"`
+	Description        string         `db:"description"`
+	SensorID           *string        `db:"sensor_id"`
+	SensorType         *string        `db:"sensor_type"`
+	Condition          string         `db:"condition"`
+	Operator           string         `db:"operator"`
+	Threshold          float64        `db:"threshold"`
+	ClearThreshold     float64        `db:"clear_threshold"`
+	ForSeconds         int            `db:"for_seconds"`
+	WindowSeconds      int            `db:"window_seconds"`
+	Severity           string         `db:"severity"`
+	NotifyEmails       pq.StringArray `db:"notify_emails"`
+	Expression         string         `db:"expression"`
+	EscalationPolicyID *string        `db:"escalation_policy_id"`
+	Enabled            bool           `db:"enabled"`
+	CreatedAt          time.Time      `db:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at"`
+}
+
+func (a *AlertRule) ToEntity() *entities.AlertRule {
+	rule := &entities.AlertRule{
+		ID:                 a.ID,
+		Name:               a.Name,
+		Description:        a.Description,
+		SensorID:           a.SensorID,
+		Condition:          entities.AlertCondition(a.Condition),
+		Operator:           entities.AlertOperator(a.Operator),
+		Threshold:          a.Threshold,
+		ClearThreshold:     a.ClearThreshold,
+		For:                a.ForSeconds,
+		Window:             a.WindowSeconds,
+		Severity:           entities.AlertSeverity(a.Severity),
+		NotifyEmails:       append([]string{}, a.NotifyEmails...),
+		Expression:         a.Expression,
+		EscalationPolicyID: a.EscalationPolicyID,
+		Enabled:            a.Enabled,
+		CreatedAt:          a.CreatedAt,
+		UpdatedAt:          a.UpdatedAt,
+	}
+
+	if a.SensorType != nil {
+		sensorType := entities.SensorType(*a.SensorType)
+		rule.SensorType = &sensorType
+	}
+
+	return rule
+}
+
+type Alert struct {
+	ID              string     `db:"id"`
+	RuleID          string     `db:"rule_id"`
+	RuleName        string     `db:"rule_name"`
+	Severity        string     `db:"severity"`
+	SensorID        string     `db:"sensor_id"`
+	DeviceID        string     `db:"device_id"`
+	Status          string     `db:"status"`
+	Value           float64    `db:"value"`
+	PendingSince    *time.Time `db:"pending_since"`
+	FiredAt         *time.Time `db:"fired_at"`
+	ResolvedAt      *time.Time `db:"resolved_at"`
+	LastEvaluatedAt *time.Time `db:"last_evaluated_at"`
+	AcknowledgedAt  *time.Time `db:"acknowledged_at"`
+	AcknowledgedBy  string     `db:"acknowledged_by"`
+	Assignee        string     `db:"assignee"`
+	SnoozedUntil    *time.Time `db:"snoozed_until"`
+	ResolvedBy      string     `db:"resolved_by"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+}
+
+func (a *Alert) ToEntity() *entities.Alert {
+	return &entities.Alert{
+		ID:              a.ID,
+		RuleID:          a.RuleID,
+		RuleName:        a.RuleName,
+		Severity:        entities.AlertSeverity(a.Severity),
+		SensorID:        a.SensorID,
+		DeviceID:        a.DeviceID,
+		Status:          entities.AlertStatus(a.Status),
+		Value:           a.Value,
+		PendingSince:    a.PendingSince,
+		FiredAt:         a.FiredAt,
+		ResolvedAt:      a.ResolvedAt,
+		LastEvaluatedAt: a.LastEvaluatedAt,
+		AcknowledgedAt:  a.AcknowledgedAt,
+		AcknowledgedBy:  a.AcknowledgedBy,
+		Assignee:        a.Assignee,
+		SnoozedUntil:    a.SnoozedUntil,
+		ResolvedBy:      a.ResolvedBy,
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+	}
+}
+
+type AlertEvent struct {
+	ID           string     `db:"id"`
+	AlertID      string     `db:"alert_id"`
+	Type         string     `db:"type"`
+	Actor        string     `db:"actor"`
+	Note         string     `db:"note"`
+	Status       string     `db:"status"`
+	Value        float64    `db:"value"`
+	Assignee     string     `db:"assignee"`
+	SnoozedUntil *time.Time `db:"snoozed_until"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (a *AlertEvent) ToEntity() *entities.AlertEvent {
+	return &entities.AlertEvent{
+		ID:           a.ID,
+		AlertID:      a.AlertID,
+		Type:         entities.AlertEventType(a.Type),
+		Actor:        a.Actor,
+		Note:         a.Note,
+		Status:       entities.AlertStatus(a.Status),
+		Value:        a.Value,
+		Assignee:     a.Assignee,
+		SnoozedUntil: a.SnoozedUntil,
+		CreatedAt:    a.CreatedAt,
+	}
+}
+
+func (r *repository) CreateAlertRule(ctx context.Context, payload entities.AlertRule) (string, error) {
+	var ruleID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO alert_rules
+		(name, description, sensor_id, sensor_type, condition, operator, threshold, clear_threshold, for_seconds, window_seconds,
+			severity, notify_emails, expression, escalation_policy_id, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		payload.SensorID,
+		payload.SensorType,
+		payload.Condition,
+		payload.Operator,
+		payload.Threshold,
+		payload.ClearThreshold,
+		payload.For,
+		payload.Window,
+		payload.Severity,
+		pq.Array(payload.NotifyEmails),
+		payload.Expression,
+		payload.EscalationPolicyID,
+		payload.Enabled,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&ruleID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ruleID, util.NewErrNotFound("sensor not found")
+		}
+
+		slog.Error(
+			"Failed to CreateAlertRule",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return ruleID, util.NewErrInternalServer("failed to create alert rule")
+	}
+
+	return ruleID, nil
+}
+
+// UpdateAlertRule changes a rule, its alerts keep their state and are evaluated with the new rule
+// as the next readings arrive.
+func (r *repository) UpdateAlertRule(ctx context.Context, ruleID string, payload entities.AlertRule) error {
+	query := `UPDATE alert_rules
+		SET name = $1, description = $2, sensor_id = $3, sensor_type = $4, condition = $5, operator = $6, threshold = $7,
+			clear_threshold = $8, for_seconds = $9, window_seconds = $10, severity = $11, notify_emails = $12, expression = $13,
+			escalation_policy_id = $14, enabled = $15, updated_at = $16
+		WHERE id = $17`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		payload.SensorID,
+		payload.SensorType,
+		payload.Condition,
+		payload.Operator,
+		payload.Threshold,
+		payload.ClearThreshold,
+		payload.For,
+		payload.Window,
+		payload.Severity,
+		pq.Array(payload.NotifyEmails),
+		payload.Expression,
+		payload.EscalationPolicyID,
+		payload.Enabled,
+		time.Now().UTC(),
+		ruleID,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return util.NewErrNotFound("sensor not found")
+		}
+
+		slog.Error(
+			"Failed to UpdateAlertRule",
+			slog.Any("err", err),
+			slog.Any("ruleID", ruleID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update alert rule")
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteAlertRule(ctx context.Context, ruleID string) error {
+	query := `DELETE FROM alert_rules WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, ruleID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteAlertRule",
+			slog.Any("err", err),
+			slog.Any("ruleID", ruleID),
+		)
+		return util.NewErrInternalServer("failed to delete alert rule")
+	}
+
+	return nil
+}
+
+func (r *repository) GetAlertRule(ctx context.Context, ruleID string) (*entities.AlertRule, error) {
+	var model AlertRule
+
+	query := fmt.Sprintf(`SELECT %s FROM alert_rules WHERE id = $1`, alertRuleColumns)
+	err := r.db.GetContext(ctx, &model, query, ruleID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("alert rule not found")
+		}
+
+		slog.Error(
+			"Failed to GetAlertRule",
+			slog.Any("err", err),
+			slog.Any("ruleID", ruleID),
+		)
+		return nil, util.NewErrInternalServer("failed to get alert rule")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetAlertRuleList(ctx context.Context, params entities.GetAlertRuleListParams) ([]*entities.AlertRule, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "severity", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM alert_rules"
+	queryData := fmt.Sprintf("SELECT %s FROM alert_rules", alertRuleColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if params.Condition != "" {
+		args["condition"] = params.Condition
+		whereQueries = append(whereQueries, "condition = :condition")
+	}
+	if params.SensorID != "" {
+		args["sensor_id"] = params.SensorID
+		whereQueries = append(whereQueries, "sensor_id = :sensor_id")
+	}
+	if params.SensorType != "" {
+		args["sensor_type"] = params.SensorType
+		whereQueries = append(whereQueries, "sensor_type = :sensor_type")
+	}
+	if params.Severity != "" {
+		args["severity"] = params.Severity
+		whereQueries = append(whereQueries, "severity = :severity")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertRuleList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert rule list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertRuleList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert rule list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertRuleList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert rule list")
+	}
+	defer stmtData.Close()
+
+	var model []AlertRule
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertRuleList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert rule list")
+	}
+
+	rules := []*entities.AlertRule{}
+	for _, v := range model {
+		rules = append(rules, v.ToEntity())
+	}
+
+	return rules, total, nil
+}
+
+// GetSensorAlertRules returns the enabled rules of a sensor, set on the sensor itself or on its type.
+func (r *repository) GetSensorAlertRules(ctx context.Context, sensorID string) ([]*entities.AlertRule, error) {
+	query := fmt.Sprintf(`SELECT %s FROM alert_rules
+		WHERE enabled AND (sensor_id = $1 OR sensor_type = (SELECT type FROM sensors WHERE id = $1))`, alertRuleColumns)
+
+	var model []AlertRule
+	err := r.db.SelectContext(ctx, &model, query, sensorID)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorAlertRules",
+			slog.Any("err", err),
+			slog.Any("sensorID", sensorID),
+		)
+		return nil, util.NewErrInternalServer("failed to get alert rules")
+	}
+
+	rules := []*entities.AlertRule{}
+	for _, v := range model {
+		rules = append(rules, v.ToEntity())
+	}
+
+	return rules, nil
+}
+
+// GetEnabledAlertRules returns the enabled rules of a condition.
+func (r *repository) GetEnabledAlertRules(ctx context.Context, condition entities.AlertCondition) ([]*entities.AlertRule, error) {
+	query := fmt.Sprintf(`SELECT %s FROM alert_rules WHERE enabled AND condition = $1`, alertRuleColumns)
+
+	var model []AlertRule
+	err := r.db.SelectContext(ctx, &model, query, condition)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEnabledAlertRules",
+			slog.Any("err", err),
+			slog.Any("condition", condition),
+		)
+		return nil, util.NewErrInternalServer("failed to get alert rules")
+	}
+
+	rules := []*entities.AlertRule{}
+	for _, v := range model {
+		rules = append(rules, v.ToEntity())
+	}
+
+	return rules, nil
+}
+
+type AlertRuleSensor struct {
+	SensorID       string     `db:"sensor_id"`
+	DeviceID       string     `db:"device_id"`
+	LastRecordedAt *time.Time `db:"last_recorded_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+func (a *AlertRuleSensor) ToEntity() *entities.AlertRuleSensor {
+	return &entities.AlertRuleSensor{
+		SensorID:       a.SensorID,
+		DeviceID:       a.DeviceID,
+		LastRecordedAt: a.LastRecordedAt,
+		CreatedAt:      a.CreatedAt,
+	}
+}
+
+// GetAlertRuleSensors returns the sensors a rule applies to with the time of their latest reading.
+func (r *repository) GetAlertRuleSensors(ctx context.Context, rule *entities.AlertRule) ([]*entities.AlertRuleSensor, error) {
+	query := `SELECT s.id AS sensor_id, s.device_id, s.created_at,
+			(SELECT MAX(recorded_at) FROM sensor_readings WHERE sensor_id = s.id) AS last_recorded_at
+		FROM sensors s
+		WHERE (s.id = $1 OR s.type = $2) AND s.deleted_at IS NULL`
+
+	var model []AlertRuleSensor
+	err := r.db.SelectContext(ctx, &model, query, rule.SensorID, rule.SensorType)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertRuleSensors",
+			slog.Any("err", err),
+			slog.Any("ruleID", rule.ID),
+		)
+		return nil, util.NewErrInternalServer("failed to get alert rule sensors")
+	}
+
+	sensors := []*entities.AlertRuleSensor{}
+	for _, v := range model {
+		sensors = append(sensors, v.ToEntity())
+	}
+
+	return sensors, nil
+}
+
+// UpdateAlert runs update on the alert of a rule and sensor, created in the ok state when there is none yet,
+// and appends the events update returns to its timeline.
+// The alert is locked until update returns, so concurrent evaluations of the same alert are serialized.
+func (r *repository) UpdateAlert(ctx context.Context, ruleID, sensorID, deviceID string, update func(alert *entities.Alert) ([]*entities.AlertEvent, error)) (*entities.Alert, error) {
+	var alert *entities.Alert
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		queryInsert := `INSERT INTO alert_states (rule_id, sensor_id, device_id, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			ON CONFLICT (rule_id, sensor_id) DO NOTHING`
+
+		_, err := tx.ExecContext(ctx, queryInsert, ruleID, sensorID, deviceID, entities.ALERT_STATUS_OK, nowUTC)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return util.NewErrNotFound("alert rule not found")
+			}
+
+			slog.Error(
+				"Failed to UpdateAlert Insert",
+				slog.Any("err", err),
+				slog.Any("ruleID", ruleID),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to update alert")
+		}
+
+		var model Alert
+		querySelect := fmt.Sprintf(`SELECT %s FROM alert_states a
+			JOIN alert_rules r ON r.id = a.rule_id
+			WHERE a.rule_id = $1 AND a.sensor_id = $2
+			FOR UPDATE OF a`, alertColumns)
+
+		err = tx.GetContext(ctx, &model, querySelect, ruleID, sensorID)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateAlert Get",
+				slog.Any("err", err),
+				slog.Any("ruleID", ruleID),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to update alert")
+		}
+
+		alert = model.ToEntity()
+		alert.DeviceID = deviceID
+		events, err := update(alert)
+		if err != nil {
+			return err
+		}
+
+		return saveAlert(ctx, tx, alert, events, nowUTC)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// UpdateAlertByID runs update on an existing alert and stores the result with the timeline entries it returns.
+func (r *repository) UpdateAlertByID(ctx context.Context, alertID string, update func(alert *entities.Alert) ([]*entities.AlertEvent, error)) (*entities.Alert, error) {
+	var alert *entities.Alert
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var model Alert
+		query := fmt.Sprintf(`SELECT %s FROM alert_states a
+			JOIN alert_rules r ON r.id = a.rule_id
+			WHERE a.id = $1 AND %s
+			FOR UPDATE OF a`, alertColumns, alertNotDeletedQuery)
+
+		err := tx.GetContext(ctx, &model, query, alertID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("alert not found")
+			}
+
+			slog.Error(
+				"Failed to UpdateAlertByID Get",
+				slog.Any("err", err),
+				slog.Any("alertID", alertID),
+			)
+			return util.NewErrInternalServer("failed to update alert")
+		}
+
+		alert = model.ToEntity()
+		events, err := update(alert)
+		if err != nil {
+			return err
+		}
+
+		return saveAlert(ctx, tx, alert, events, time.Now().UTC())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// UpdateCompositeAlert runs update on the alert of a composite rule for a device, created as ok when it does not
+// exist yet, and stores the result with the timeline entries it returns. An empty deviceID is the alert of a rule
+// that is not on the same device.
+func (r *repository) UpdateCompositeAlert(ctx context.Context, ruleID, deviceID string, update func(alert *entities.Alert) ([]*entities.AlertEvent, error)) (*entities.Alert, error) {
+	var alert *entities.Alert
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		queryInsert := `INSERT INTO alert_states (rule_id, device_id, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (rule_id, device_id) WHERE sensor_id IS NULL DO NOTHING`
+		if deviceID == "" {
+			queryInsert = `INSERT INTO alert_states (rule_id, device_id, status, created_at, updated_at)
+				VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $4)
+				ON CONFLICT (rule_id) WHERE sensor_id IS NULL AND device_id IS NULL DO NOTHING`
+		}
+
+		_, err := tx.ExecContext(ctx, queryInsert, ruleID, deviceID, entities.ALERT_STATUS_OK, nowUTC)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return util.NewErrNotFound("alert rule not found")
+			}
+
+			slog.Error(
+				"Failed to UpdateCompositeAlert Insert",
+				slog.Any("err", err),
+				slog.Any("ruleID", ruleID),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to update alert")
+		}
+
+		var model Alert
+		querySelect := fmt.Sprintf(`SELECT %s FROM alert_states a
+			JOIN alert_rules r ON r.id = a.rule_id
+			WHERE a.rule_id = $1 AND a.sensor_id IS NULL AND a.device_id IS NOT DISTINCT FROM NULLIF($2, '')::uuid
+			FOR UPDATE OF a`, alertColumns)
+
+		err = tx.GetContext(ctx, &model, querySelect, ruleID, deviceID)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateCompositeAlert Get",
+				slog.Any("err", err),
+				slog.Any("ruleID", ruleID),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to update alert")
+		}
+
+		alert = model.ToEntity()
+		events, err := update(alert)
+		if err != nil {
+			return err
+		}
+
+		return saveAlert(ctx, tx, alert, events, nowUTC)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// alertEventTypes maps the timeline entries that are announced to their event type.
+var alertEventTypes = map[entities.AlertEventType]string{
+	entities.ALERT_EVENT_PENDING:      events.ALERT_PENDING,
+	entities.ALERT_EVENT_FIRING:       events.ALERT_FIRING,
+	entities.ALERT_EVENT_RESOLVED:     events.ALERT_RESOLVED,
+	entities.ALERT_EVENT_ACKNOWLEDGED: events.ALERT_ACKNOWLEDGED,
+	entities.ALERT_EVENT_ASSIGNED:     events.ALERT_ASSIGNED,
+	entities.ALERT_EVENT_UNASSIGNED:   events.ALERT_ASSIGNED,
+	entities.ALERT_EVENT_COMMENTED:    events.ALERT_COMMENTED,
+	entities.ALERT_EVENT_SNOOZED:      events.ALERT_SNOOZED,
+	entities.ALERT_EVENT_UNSNOOZED:    events.ALERT_SNOOZED,
+}
+
+// saveAlert writes an alert locked in tx, appends the entries to its timeline and stores the event of every
+// announced entry in the outbox, with the alert as it was at that entry. An alert that fired starts its
+// escalation.
+func saveAlert(ctx context.Context, tx *sqlx.Tx, alert *entities.Alert, timeline []*entities.AlertEvent, now time.Time) error {
+	alert.UpdatedAt = now
+	queryUpdate := `UPDATE alert_states
+		SET device_id = NULLIF($1, '')::uuid, status = $2, value = $3, pending_since = $4, fired_at = $5, resolved_at = $6,
+			last_evaluated_at = $7, acknowledged_at = $8, acknowledged_by = $9, assignee = $10, snoozed_until = $11,
+			resolved_by = $12, updated_at = $13
+		WHERE id = $14`
+
+	_, err := tx.ExecContext(
+		ctx,
+		queryUpdate,
+		alert.DeviceID,
+		alert.Status,
+		alert.Value,
+		alert.PendingSince,
+		alert.FiredAt,
+		alert.ResolvedAt,
+		alert.LastEvaluatedAt,
+		alert.AcknowledgedAt,
+		alert.AcknowledgedBy,
+		alert.Assignee,
+		alert.SnoozedUntil,
+		alert.ResolvedBy,
+		alert.UpdatedAt,
+		alert.ID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateAlert",
+			slog.Any("err", err),
+			slog.Any("alert", alert),
+		)
+		return util.NewErrInternalServer("failed to update alert")
+	}
+
+	// clock_timestamp keeps the events of one transaction in order
+	queryEvent := `INSERT INTO alert_events
+		(alert_id, type, actor, note, status, value, assignee, snoozed_until, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, clock_timestamp())`
+
+	for _, v := range timeline {
+		_, err := tx.ExecContext(
+			ctx,
+			queryEvent,
+			alert.ID,
+			v.Type,
+			v.Actor,
+			v.Note,
+			v.Status,
+			v.Value,
+			v.Assignee,
+			v.SnoozedUntil,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateAlert Insert Event",
+				slog.Any("err", err),
+				slog.Any("alertID", alert.ID),
+				slog.Any("event", v),
+			)
+			return util.NewErrInternalServer("failed to update alert")
+		}
+
+		eventType, ok := alertEventTypes[v.Type]
+		if !ok {
+			continue
+		}
+		data := *alert
+		data.Status = v.Status
+		data.Value = v.Value
+		err = insertOutboxEvent(ctx, tx, eventType, alert.ID, &data, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range timeline {
+		if v.Type == entities.ALERT_EVENT_FIRING {
+			return startAlertEscalation(ctx, tx, alert, now)
+		}
+	}
+
+	return nil
+}
+
+// alertNotDeletedQuery leaves out the alerts of deleted sensors and devices, they come back when these are
+// restored.
+const alertNotDeletedQuery = `NOT EXISTS (SELECT 1 FROM sensors s WHERE s.id = a.sensor_id AND s.deleted_at IS NOT NULL)
+	AND NOT EXISTS (SELECT 1 FROM devices d WHERE d.id = a.device_id AND d.deleted_at IS NOT NULL)`
+
+func (r *repository) GetAlert(ctx context.Context, alertID string) (*entities.Alert, error) {
+	var model Alert
+
+	query := fmt.Sprintf(`SELECT %s FROM alert_states a JOIN alert_rules r ON r.id = a.rule_id WHERE a.id = $1 AND %s`, alertColumns, alertNotDeletedQuery)
+	err := r.db.GetContext(ctx, &model, query, alertID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("alert not found")
+		}
+
+		slog.Error(
+			"Failed to GetAlert",
+			slog.Any("err", err),
+			slog.Any("alertID", alertID),
+		)
+		return nil, util.NewErrInternalServer("failed to get alert")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetAlertList(ctx context.Context, params entities.GetAlertListParams) ([]*entities.Alert, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"status", "fired_at", "pending_since", "last_evaluated_at", "acknowledged_at", "snoozed_until", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(a.id) FROM alert_states a JOIN alert_rules r ON r.id = a.rule_id"
+	queryData := fmt.Sprintf("SELECT %s FROM alert_states a JOIN alert_rules r ON r.id = a.rule_id", alertColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{alertNotDeletedQuery}
+	if len(params.Statuses) > 0 {
+		statuses := []string{}
+		for _, v := range params.Statuses {
+			statuses = append(statuses, string(v))
+		}
+		args["statuses"] = pq.Array(statuses)
+		whereQueries = append(whereQueries, "a.status = ANY(:statuses)")
+	}
+	if params.Severity != "" {
+		args["severity"] = params.Severity
+		whereQueries = append(whereQueries, "r.severity = :severity")
+	}
+	if params.RuleID != "" {
+		args["rule_id"] = params.RuleID
+		whereQueries = append(whereQueries, "a.rule_id = :rule_id")
+	}
+	if params.SensorID != "" {
+		args["sensor_id"] = params.SensorID
+		whereQueries = append(whereQueries, "a.sensor_id = :sensor_id")
+	}
+	if params.DeviceID != "" {
+		args["device_id"] = params.DeviceID
+		whereQueries = append(whereQueries, "a.device_id = :device_id")
+	}
+	if params.Assignee != "" {
+		args["assignee"] = params.Assignee
+		whereQueries = append(whereQueries, "a.assignee = :assignee")
+	}
+	if params.Acknowledged != nil {
+		if *params.Acknowledged {
+			whereQueries = append(whereQueries, "a.acknowledged_at IS NOT NULL")
+		} else {
+			whereQueries = append(whereQueries, "a.acknowledged_at IS NULL")
+		}
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY a.%s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert list")
+	}
+	defer stmtData.Close()
+
+	var model []Alert
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert list")
+	}
+
+	alerts := []*entities.Alert{}
+	for _, v := range model {
+		alerts = append(alerts, v.ToEntity())
+	}
+
+	return alerts, total, nil
+}
+
+// GetAlertEventList returns the timeline of an alert.
+func (r *repository) GetAlertEventList(ctx context.Context, params entities.GetAlertEventListParams) ([]*entities.AlertEvent, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"type", "created_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM alert_events"
+	queryData := fmt.Sprintf("SELECT %s FROM alert_events", alertEventColumns)
+
+	args := map[string]any{
+		"alert_id": params.AlertID,
+	}
+	whereQueries := []string{"alert_id = :alert_id"}
+	if params.Type != "" {
+		args["type"] = params.Type
+		whereQueries = append(whereQueries, "type = :type")
+	}
+
+	whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+	queryCount += whereQuery
+	queryData += whereQuery
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertEventList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert timeline")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertEventList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert timeline")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertEventList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert timeline")
+	}
+	defer stmtData.Close()
+
+	var model []AlertEvent
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAlertEventList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get alert timeline")
+	}
+
+	events := []*entities.AlertEvent{}
+	for _, v := range model {
+		events = append(events, v.ToEntity())
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,413 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	anomalyDetectorColumns = `sensor_id, enabled, sensitivity, alpha, window_size, seasonal, model, last_recorded_at,
+	created_at, updated_at`
+	sensorAnomalyColumns = `id, reading_id, sensor_id, device_id, value, expected, score, z_score, ewma_score,
+	recorded_at, created_at`
+)
+
+// AnomalyModel maps the jsonb model column of a detector.
+type AnomalyModel entities.AnomalyModel
+
+func (m AnomalyModel) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *AnomalyModel) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		*m = AnomalyModel{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for AnomalyModel")
+	}
+
+	result := AnomalyModel{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*m = result
+
+	return nil
+}
+
+type AnomalyDetector struct {
+	SensorID       string       `db:"sensor_id"`
+	Enabled        bool         `db:"enabled"`
+	Sensitivity    float64      `db:"sensitivity"`
+	Alpha          float64      `db:"alpha"`
+	Window         int          `db:"window_size"`
+	Seasonal       bool         `db:"seasonal"`
+	Model          AnomalyModel `db:"model"`
+	LastRecordedAt *time.Time   `db:"last_recorded_at"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at"`
+}
+
+func (a *AnomalyDetector) ToEntity() *entities.AnomalyDetector {
+	model := entities.AnomalyModel(a.Model)
+	if model.Recent == nil {
+		model.Recent = []float64{}
+	}
+
+	return &entities.AnomalyDetector{
+		SensorID:       a.SensorID,
+		Enabled:        a.Enabled,
+		Sensitivity:    a.Sensitivity,
+		Alpha:          a.Alpha,
+		Window:         a.Window,
+		Seasonal:       a.Seasonal,
+		Model:          model,
+		LastRecordedAt: a.LastRecordedAt,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+}
+
+type SensorAnomaly struct {
+	ID         int64     `db:"id"`
+	ReadingID  int64     `db:"reading_id"`
+	SensorID   string    `db:"sensor_id"`
+	DeviceID   string    `db:"device_id"`
+	Value      float64   `db:"value"`
+	Expected   float64   `db:"expected"`
+	Score      float64   `db:"score"`
+	ZScore     float64   `db:"z_score"`
+	EWMAScore  float64   `db:"ewma_score"`
+	RecordedAt time.Time `db:"recorded_at"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (s *SensorAnomaly) ToEntity() *entities.SensorAnomaly {
+	return &entities.SensorAnomaly{
+		ID:         s.ID,
+		ReadingID:  s.ReadingID,
+		SensorID:   s.SensorID,
+		DeviceID:   s.DeviceID,
+		Value:      s.Value,
+		Expected:   s.Expected,
+		Score:      s.Score,
+		ZScore:     s.ZScore,
+		EWMAScore:  s.EWMAScore,
+		RecordedAt: s.RecordedAt,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// PutAnomalyDetector creates or updates the detector of a sensor. The learned model is kept unless reset is set
+// or the detector switches seasonality, which changes what the model holds.
+func (r *repository) PutAnomalyDetector(ctx context.Context, payload entities.AnomalyDetector, reset bool) error {
+	nowUTC := time.Now().UTC()
+
+	query := `INSERT INTO anomaly_detectors
+		(sensor_id, enabled, sensitivity, alpha, window_size, seasonal, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (sensor_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled, sensitivity = EXCLUDED.sensitivity, alpha = EXCLUDED.alpha,
+			window_size = EXCLUDED.window_size, seasonal = EXCLUDED.seasonal, updated_at = EXCLUDED.updated_at,
+			model = CASE WHEN $8 OR anomaly_detectors.seasonal <> EXCLUDED.seasonal
+				THEN '{}' ELSE anomaly_detectors.model END,
+			last_recorded_at = CASE WHEN $8 OR anomaly_detectors.seasonal <> EXCLUDED.seasonal
+				THEN NULL ELSE anomaly_detectors.last_recorded_at END`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.SensorID,
+		payload.Enabled,
+		payload.Sensitivity,
+		payload.Alpha,
+		payload.Window,
+		payload.Seasonal,
+		nowUTC,
+		reset,
+	)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return util.NewErrNotFound("sensor not found")
+		}
+
+		slog.Error(
+			"Failed to PutAnomalyDetector",
+			slog.Any("err", err),
+			slog.Any("sensorID", payload.SensorID),
+		)
+		return util.NewErrInternalServer("failed to put anomaly detector")
+	}
+
+	return nil
+}
+
+// DeleteAnomalyDetector deletes the detector of a sensor with its model, the stored anomalies are kept.
+func (r *repository) DeleteAnomalyDetector(ctx context.Context, sensorID string) error {
+	query := `DELETE FROM anomaly_detectors WHERE sensor_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, sensorID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteAnomalyDetector",
+			slog.Any("err", err),
+			slog.Any("sensorID", sensorID),
+		)
+		return util.NewErrInternalServer("failed to delete anomaly detector")
+	}
+
+	return nil
+}
+
+func (r *repository) GetAnomalyDetector(ctx context.Context, sensorID string) (*entities.AnomalyDetector, error) {
+	var model AnomalyDetector
+
+	query := fmt.Sprintf(`SELECT %s FROM anomaly_detectors WHERE sensor_id = $1`, anomalyDetectorColumns)
+	err := r.db.GetContext(ctx, &model, query, sensorID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("anomaly detector not found")
+		}
+
+		slog.Error(
+			"Failed to GetAnomalyDetector",
+			slog.Any("err", err),
+			slog.Any("sensorID", sensorID),
+		)
+		return nil, util.NewErrInternalServer("failed to get anomaly detector")
+	}
+
+	return model.ToEntity(), nil
+}
+
+// GetAnomalyDetectorSensorIDs returns the given sensors that have an enabled detector.
+func (r *repository) GetAnomalyDetectorSensorIDs(ctx context.Context, sensorIDs []string) ([]string, error) {
+	query := `SELECT sensor_id FROM anomaly_detectors WHERE sensor_id = ANY($1) AND enabled`
+
+	results := []string{}
+	err := r.db.SelectContext(ctx, &results, query, pq.Array(sensorIDs))
+	if err != nil {
+		slog.Error(
+			"Failed to GetAnomalyDetectorSensorIDs",
+			slog.Any("err", err),
+			slog.Any("sensorIDs", sensorIDs),
+		)
+		return nil, util.NewErrInternalServer("failed to get anomaly detectors")
+	}
+
+	return results, nil
+}
+
+// UpdateAnomalyDetector runs update on the locked detector of a sensor, then stores its model with the
+// anomalies update returns. The stored anomalies are returned.
+func (r *repository) UpdateAnomalyDetector(ctx context.Context, sensorID string, update func(detector *entities.AnomalyDetector) []*entities.SensorAnomaly) ([]*entities.SensorAnomaly, error) {
+	anomalies := []*entities.SensorAnomaly{}
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		var model AnomalyDetector
+		query := fmt.Sprintf(`SELECT %s FROM anomaly_detectors WHERE sensor_id = $1 FOR UPDATE`, anomalyDetectorColumns)
+
+		err := tx.GetContext(ctx, &model, query, sensorID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("anomaly detector not found")
+			}
+
+			slog.Error(
+				"Failed to UpdateAnomalyDetector Get",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to update anomaly detector")
+		}
+
+		detector := model.ToEntity()
+		flagged := update(detector)
+
+		query = `UPDATE anomaly_detectors SET model = $1, last_recorded_at = $2, updated_at = $3 WHERE sensor_id = $4`
+		_, err = tx.ExecContext(ctx, query, AnomalyModel(detector.Model), detector.LastRecordedAt, nowUTC, sensorID)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateAnomalyDetector Update",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to update anomaly detector")
+		}
+
+		query = fmt.Sprintf(`INSERT INTO sensor_anomalies
+			(reading_id, sensor_id, device_id, value, expected, score, z_score, ewma_score, recorded_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING %s`, sensorAnomalyColumns)
+
+		for _, v := range flagged {
+			var anomaly SensorAnomaly
+			err := tx.GetContext(
+				ctx,
+				&anomaly,
+				query,
+				v.ReadingID,
+				v.SensorID,
+				v.DeviceID,
+				v.Value,
+				v.Expected,
+				v.Score,
+				v.ZScore,
+				v.EWMAScore,
+				v.RecordedAt,
+				nowUTC,
+			)
+			if err != nil {
+				slog.Error(
+					"Failed to UpdateAnomalyDetector Insert",
+					slog.Any("err", err),
+					slog.Any("sensorID", sensorID),
+					slog.Any("readingID", v.ReadingID),
+				)
+				return util.NewErrInternalServer("failed to update anomaly detector")
+			}
+
+			entity := anomaly.ToEntity()
+			err = insertOutboxEvent(ctx, tx, events.SENSOR_ANOMALY, entity.SensorID, entity, nowUTC)
+			if err != nil {
+				return err
+			}
+			anomalies = append(anomalies, entity)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return anomalies, nil
+}
+
+func (r *repository) GetSensorAnomalyList(ctx context.Context, params entities.GetSensorAnomalyListParams) ([]*entities.SensorAnomaly, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"recorded_at", "score"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	if orderBy == "created_at DESC" {
+		orderBy = "recorded_at DESC"
+	}
+
+	queryCount := "SELECT COUNT(id) FROM sensor_anomalies"
+	queryData := fmt.Sprintf("SELECT %s FROM sensor_anomalies", sensorAnomalyColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{"sensor_id NOT IN (SELECT id FROM sensors WHERE deleted_at IS NOT NULL)"}
+	if params.SensorID != "" {
+		args["sensor_id"] = params.SensorID
+		whereQueries = append(whereQueries, "sensor_id = :sensor_id")
+	}
+	if params.DeviceID != "" {
+		args["device_id"] = params.DeviceID
+		whereQueries = append(whereQueries, "device_id = :device_id")
+	}
+	if params.From != nil {
+		args["from"] = *params.From
+		whereQueries = append(whereQueries, "recorded_at >= :from")
+	}
+	if params.To != nil {
+		args["to"] = *params.To
+		whereQueries = append(whereQueries, "recorded_at < :to")
+	}
+	if params.MinScore != nil {
+		args["min_score"] = *params.MinScore
+		whereQueries = append(whereQueries, "score >= :min_score")
+	}
+
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorAnomalyList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor anomaly list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorAnomalyList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor anomaly list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorAnomalyList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor anomaly list")
+	}
+	defer stmtData.Close()
+
+	var model []SensorAnomaly
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorAnomalyList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor anomaly list")
+	}
+
+	anomalies := []*entities.SensorAnomaly{}
+	for _, v := range model {
+		anomalies = append(anomalies, v.ToEntity())
+	}
+
+	return anomalies, total, nil
+}
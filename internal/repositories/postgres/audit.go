@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"go-api/internal/audit"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const auditEntryColumns = `id, resource_type, resource_id, action, claimed_actor, request_id, changes, created_at`
+
+// auditTables are the tables of the audited resources.
+var auditTables = map[entities.AuditResourceType]string{
+	entities.AUDIT_RESOURCE_DEVICE: "devices",
+	entities.AUDIT_RESOURCE_SENSOR: "sensors",
+}
+
+type AuditEntry struct {
+	ID           string    `db:"id"`
+	ResourceType string    `db:"resource_type"`
+	ResourceID   string    `db:"resource_id"`
+	Action       string    `db:"action"`
+	ClaimedActor string    `db:"claimed_actor"`
+	RequestID    *string   `db:"request_id"`
+	Changes      JSONB     `db:"changes"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (a *AuditEntry) ToEntity() *entities.AuditEntry {
+	changes := map[string]entities.AuditChange{}
+	for field, v := range a.Changes {
+		change, _ := v.(map[string]any)
+		changes[field] = entities.AuditChange{Old: change["old"], New: change["new"]}
+	}
+
+	return &entities.AuditEntry{
+		ID:           a.ID,
+		ResourceType: entities.AuditResourceType(a.ResourceType),
+		ResourceID:   a.ResourceID,
+		Action:       entities.AuditAction(a.Action),
+		ClaimedActor: a.ClaimedActor,
+		RequestID:    a.RequestID,
+		Changes:      changes,
+		CreatedAt:    a.CreatedAt,
+	}
+}
+
+type AuditRow struct {
+	ID   string `db:"id"`
+	Data JSONB  `db:"data"`
+}
+
+// auditSnapshot returns the rows of the resources with the given IDs as JSON objects by ID, and locks them
+// until the end of the transaction so the snapshot stays current. Resources that do not exist are left out.
+func auditSnapshot(ctx context.Context, q sqlx.QueryerContext, resourceType entities.AuditResourceType, ids []string) (map[string]JSONB, error) {
+	query := fmt.Sprintf(`SELECT id, to_jsonb(t) AS data FROM %s t WHERE id = ANY($1) FOR UPDATE`, auditTables[resourceType])
+
+	snapshot, err := auditRows(ctx, q, query, pq.Array(ids))
+	if err != nil {
+		slog.Error(
+			"Failed to auditSnapshot",
+			slog.Any("err", err),
+			slog.Any("resourceType", resourceType),
+			slog.Any("ids", ids),
+		)
+		return nil, util.NewErrInternalServer("failed to record audit log")
+	}
+
+	return snapshot, nil
+}
+
+// activeVersion returns the version of a resource in an audit snapshot, false when the resource does not exist
+// or is in the trash.
+func activeVersion(data JSONB) (int, bool) {
+	if data == nil || data["deleted_at"] != nil {
+		return 0, false
+	}
+	version, _ := data["version"].(float64)
+
+	return int(version), true
+}
+
+// auditRows runs a query returning the id and the row as JSON of resources, such as a DELETE ... RETURNING,
+// and returns the rows by ID.
+func auditRows(ctx context.Context, q sqlx.QueryerContext, query string, args ...any) (map[string]JSONB, error) {
+	var rows []AuditRow
+	err := sqlx.SelectContext(ctx, q, &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]JSONB{}
+	for _, v := range rows {
+		snapshot[v.ID] = v.Data
+	}
+
+	return snapshot, nil
+}
+
+// recordAudit records the changes of the resources with the given IDs since the before snapshot, nil for
+// created resources, in the transaction of q.
+func recordAudit(ctx context.Context, q sqlx.ExtContext, resourceType entities.AuditResourceType, action entities.AuditAction, ids []string, before map[string]JSONB) error {
+	after, err := auditSnapshot(ctx, q, resourceType, ids)
+	if err != nil {
+		return err
+	}
+
+	return insertAuditEntries(ctx, q, resourceType, action, before, after)
+}
+
+// insertAuditEntries inserts an entry for every resource that differs between the before and after snapshots,
+// made by the actor and in the request of ctx.
+func insertAuditEntries(ctx context.Context, q sqlx.ExecerContext, resourceType entities.AuditResourceType, action entities.AuditAction, before, after map[string]JSONB) error {
+	ids := []string{}
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	query := `INSERT INTO audit_log (resource_type, resource_id, action, claimed_actor, request_id, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	actor := audit.Actor(ctx)
+	requestID := audit.RequestID(ctx)
+	nowUTC := time.Now().UTC()
+
+	for _, id := range ids {
+		changes := auditChanges(before[id], after[id])
+		if len(changes) == 0 {
+			continue
+		}
+
+		_, err := q.ExecContext(ctx, query, resourceType, id, action, actor, requestID, changes, nowUTC)
+		if err != nil {
+			slog.Error(
+				"Failed to insertAuditEntries",
+				slog.Any("err", err),
+				slog.Any("resourceType", resourceType),
+				slog.Any("resourceID", id),
+				slog.Any("action", action),
+			)
+			return util.NewErrInternalServer("failed to record audit log")
+		}
+	}
+
+	return nil
+}
+
+// auditChanges returns the old and new value of every column that differs between two rows, a missing row
+// having only null columns.
+func auditChanges(before, after JSONB) JSONB {
+	changes := JSONB{}
+	for field, old := range before {
+		if !reflect.DeepEqual(old, after[field]) {
+			changes[field] = map[string]any{"old": old, "new": after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok && value != nil {
+			changes[field] = map[string]any{"old": nil, "new": value}
+		}
+	}
+
+	return changes
+}
+
+func (r *repository) GetAuditEntryList(ctx context.Context, params entities.GetAuditEntryListParams) ([]*entities.AuditEntry, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"created_at", "claimed_actor"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM audit_log"
+	queryData := fmt.Sprintf("SELECT %s FROM audit_log", auditEntryColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.ResourceType != "" {
+		args["resource_type"] = params.ResourceType
+		whereQueries = append(whereQueries, "resource_type = :resource_type")
+	}
+	if params.ResourceID != "" {
+		args["resource_id"] = params.ResourceID
+		whereQueries = append(whereQueries, "resource_id = :resource_id")
+	}
+	if params.Action != "" {
+		args["action"] = params.Action
+		whereQueries = append(whereQueries, "action = :action")
+	}
+	if params.ClaimedActor != "" {
+		args["claimed_actor"] = params.ClaimedActor
+		whereQueries = append(whereQueries, "claimed_actor = :claimed_actor")
+	}
+	if params.RequestID != "" {
+		args["request_id"] = params.RequestID
+		whereQueries = append(whereQueries, "request_id = :request_id")
+	}
+	if params.From != nil {
+		args["from"] = *params.From
+		whereQueries = append(whereQueries, "created_at >= :from")
+	}
+	if params.To != nil {
+		args["to"] = *params.To
+		whereQueries = append(whereQueries, "created_at < :to")
+	}
+
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAuditEntryList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get audit log")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAuditEntryList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get audit log")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAuditEntryList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get audit log")
+	}
+	defer stmtData.Close()
+
+	var model []AuditEntry
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetAuditEntryList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get audit log")
+	}
+
+	entries := []*entities.AuditEntry{}
+	for _, v := range model {
+		entries = append(entries, v.ToEntity())
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,329 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const sensorCalibrationColumns = `id, sensor_id, kind, offset_value, scale_value, coefficients, points,
+	description, effective_from, created_at, updated_at`
+
+// recalibrateBatchSize limits how many readings are re-derived per query.
+const recalibrateBatchSize = 5000
+
+type SensorCalibration struct {
+	ID            string    `db:"id"`
+	SensorID      string    `db:"sensor_id"`
+	Kind          string    `db:"kind"`
+	Offset        *float64  `db:"offset_value"`
+	Scale         *float64  `db:"scale_value"`
+	Coefficients  []byte    `db:"coefficients"`
+	Points        []byte    `db:"points"`
+	Description   string    `db:"description"`
+	EffectiveFrom time.Time `db:"effective_from"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (c *SensorCalibration) ToEntity() *entities.SensorCalibration {
+	calibration := &entities.SensorCalibration{
+		ID:            c.ID,
+		SensorID:      c.SensorID,
+		Kind:          entities.CalibrationKind(c.Kind),
+		Offset:        c.Offset,
+		Scale:         c.Scale,
+		Description:   c.Description,
+		EffectiveFrom: c.EffectiveFrom,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+
+	if len(c.Coefficients) > 0 {
+		_ = json.Unmarshal(c.Coefficients, &calibration.Coefficients)
+	}
+	if len(c.Points) > 0 {
+		_ = json.Unmarshal(c.Points, &calibration.Points)
+	}
+
+	return calibration
+}
+
+// calibrationDocuments returns the coefficients and points of a calibration as jsonb values.
+func calibrationDocuments(payload entities.SensorCalibration) ([]byte, []byte, error) {
+	coefficients := payload.Coefficients
+	if coefficients == nil {
+		coefficients = []float64{}
+	}
+	points := payload.Points
+	if points == nil {
+		points = []entities.CalibrationPoint{}
+	}
+
+	coefficientList, err := json.Marshal(coefficients)
+	if err != nil {
+		return nil, nil, err
+	}
+	pointList, err := json.Marshal(points)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return coefficientList, pointList, nil
+}
+
+// CreateSensorCalibration stores a calibration and re-derives the readings it affects in the same transaction.
+func (r *repository) CreateSensorCalibration(ctx context.Context, payload entities.SensorCalibration) (string, error) {
+	var calibrationID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	coefficients, points, err := calibrationDocuments(payload)
+	if err != nil {
+		return calibrationID, util.NewErrInvalidRequest("invalid sensor calibration")
+	}
+
+	query := `INSERT INTO sensor_calibrations
+		(sensor_id, kind, offset_value, scale_value, coefficients, points, description, effective_from, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.QueryRowxContext(
+			ctx,
+			query,
+			payload.SensorID,
+			payload.Kind,
+			payload.Offset,
+			payload.Scale,
+			coefficients,
+			points,
+			payload.Description,
+			payload.EffectiveFrom,
+			payload.CreatedAt,
+			payload.UpdatedAt,
+		).Scan(&calibrationID)
+		if err != nil {
+			slog.Error(
+				"Failed to CreateSensorCalibration",
+				slog.Any("err", err),
+				slog.Any("payload", payload),
+			)
+			return util.NewErrInternalServer("failed to create sensor calibration")
+		}
+
+		return recalibrateSensorReadings(ctx, tx, payload.SensorID, payload.EffectiveFrom)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return calibrationID, nil
+}
+
+// UpdateSensorCalibration changes a calibration and re-derives the readings from the earlier of
+// its old and new effective time.
+func (r *repository) UpdateSensorCalibration(ctx context.Context, calibrationID string, payload entities.SensorCalibration) error {
+	coefficients, points, err := calibrationDocuments(payload)
+	if err != nil {
+		return util.NewErrInvalidRequest("invalid sensor calibration")
+	}
+
+	queryPrevious := `SELECT effective_from FROM sensor_calibrations WHERE id = $1 FOR UPDATE`
+
+	query := `UPDATE sensor_calibrations
+		SET kind = $1, offset_value = $2, scale_value = $3, coefficients = $4, points = $5,
+			description = $6, effective_from = $7, updated_at = $8
+		WHERE id = $9`
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var previousFrom time.Time
+		err := tx.GetContext(ctx, &previousFrom, queryPrevious, calibrationID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("sensor calibration not found")
+			}
+
+			slog.Error(
+				"Failed to UpdateSensorCalibration Get",
+				slog.Any("err", err),
+				slog.Any("calibrationID", calibrationID),
+			)
+			return util.NewErrInternalServer("failed to update sensor calibration")
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			payload.Kind,
+			payload.Offset,
+			payload.Scale,
+			coefficients,
+			points,
+			payload.Description,
+			payload.EffectiveFrom,
+			time.Now().UTC(),
+			calibrationID,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateSensorCalibration",
+				slog.Any("err", err),
+				slog.Any("calibrationID", calibrationID),
+				slog.Any("payload", payload),
+			)
+			return util.NewErrInternalServer("failed to update sensor calibration")
+		}
+
+		from := payload.EffectiveFrom
+		if previousFrom.Before(from) {
+			from = previousFrom
+		}
+
+		return recalibrateSensorReadings(ctx, tx, payload.SensorID, from)
+	})
+}
+
+// DeleteSensorCalibration removes a calibration, its readings fall back to the previous calibration.
+func (r *repository) DeleteSensorCalibration(ctx context.Context, calibrationID string) error {
+	query := `DELETE FROM sensor_calibrations WHERE id = $1 RETURNING sensor_id, effective_from`
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var (
+			sensorID string
+			from     time.Time
+		)
+
+		err := tx.QueryRowxContext(ctx, query, calibrationID).Scan(&sensorID, &from)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+
+			slog.Error(
+				"Failed to DeleteSensorCalibration",
+				slog.Any("err", err),
+				slog.Any("calibrationID", calibrationID),
+			)
+			return util.NewErrInternalServer("failed to delete sensor calibration")
+		}
+
+		return recalibrateSensorReadings(ctx, tx, sensorID, from)
+	})
+}
+
+func (r *repository) GetSensorCalibration(ctx context.Context, calibrationID string) (*entities.SensorCalibration, error) {
+	var model SensorCalibration
+
+	query := fmt.Sprintf(`SELECT %s FROM sensor_calibrations WHERE id = $1`, sensorCalibrationColumns)
+	err := r.db.GetContext(ctx, &model, query, calibrationID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("sensor calibration not found")
+		}
+
+		slog.Error(
+			"Failed to GetSensorCalibration",
+			slog.Any("err", err),
+			slog.Any("calibrationID", calibrationID),
+		)
+		return nil, util.NewErrInternalServer("failed to get sensor calibration")
+	}
+
+	return model.ToEntity(), nil
+}
+
+// GetSensorCalibrationList returns every calibration of a sensor ordered by effective time.
+func (r *repository) GetSensorCalibrationList(ctx context.Context, sensorID string) ([]*entities.SensorCalibration, error) {
+	return sensorCalibrations(ctx, r.db, sensorID)
+}
+
+func sensorCalibrations(ctx context.Context, q sqlx.QueryerContext, sensorID string) ([]*entities.SensorCalibration, error) {
+	query := fmt.Sprintf(`SELECT %s FROM sensor_calibrations WHERE sensor_id = $1 ORDER BY effective_from`, sensorCalibrationColumns)
+
+	var model []SensorCalibration
+	err := sqlx.SelectContext(ctx, q, &model, query, sensorID)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorCalibrationList",
+			slog.Any("err", err),
+			slog.Any("sensorID", sensorID),
+		)
+		return nil, util.NewErrInternalServer("failed to get sensor calibration list")
+	}
+
+	calibrations := []*entities.SensorCalibration{}
+	for _, v := range model {
+		calibrations = append(calibrations, v.ToEntity())
+	}
+
+	return calibrations, nil
+}
+
+// recalibrateSensorReadings re-derives calibrated_value of the sensor readings recorded at or after from, and
+// the readings of the virtual sensors depending on them. Raw values are never changed.
+func recalibrateSensorReadings(ctx context.Context, tx *sqlx.Tx, sensorID string, from time.Time) error {
+	calibrations, err := sensorCalibrations(ctx, tx, sensorID)
+	if err != nil {
+		return err
+	}
+
+	querySelect := `SELECT id, value, recorded_at FROM sensor_readings
+		WHERE sensor_id = $1 AND recorded_at >= $2 AND id > $3
+		ORDER BY id LIMIT $4`
+
+	queryUpdate := `UPDATE sensor_readings r SET calibrated_value = c.value
+		FROM (SELECT UNNEST($1::bigint[]) AS id, UNNEST($2::double precision[]) AS value) c
+		WHERE r.id = c.id`
+
+	var lastID int64
+	for {
+		var readings []SensorReading
+		err = tx.SelectContext(ctx, &readings, querySelect, sensorID, from, lastID, recalibrateBatchSize)
+		if err != nil {
+			slog.Error(
+				"Failed to recalibrateSensorReadings Select",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to recalibrate sensor readings")
+		}
+		if len(readings) == 0 {
+			break
+		}
+
+		ids := make([]int64, len(readings))
+		values := make([]sql.NullFloat64, len(readings))
+		for i, reading := range readings {
+			ids[i] = reading.ID
+			if calibration := entities.CalibrationAt(calibrations, reading.RecordedAt); calibration != nil {
+				values[i] = sql.NullFloat64{Float64: calibration.Apply(reading.Value), Valid: true}
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdate, pq.Array(ids), pq.Array(values))
+		if err != nil {
+			slog.Error(
+				"Failed to recalibrateSensorReadings Update",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to recalibrate sensor readings")
+		}
+
+		lastID = readings[len(readings)-1].ID
+	}
+
+	return rederiveVirtualSensorReadings(ctx, tx, sensorID, from)
+}
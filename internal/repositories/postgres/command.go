@@ -0,0 +1,340 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/util"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const deviceCommandColumns = `id, device_id, name, params, status, result, expires_at, delivered_at, acknowledged_at, created_at, updated_at`
+
+type DeviceCommand struct {
+	ID             string     `db:"id"`
+	DeviceID       string     `db:"device_id"`
+	Name           string     `db:"name"`
+	Params         JSONB      `db:"params"`
+	Status         string     `db:"status"`
+	Result         JSONB      `db:"result"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	AcknowledgedAt *time.Time `db:"acknowledged_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+func (c *DeviceCommand) ToEntity() *entities.DeviceCommand {
+	status := entities.CommandStatus(c.Status)
+
+	// the expiry worker runs periodically, report stale commands as expired right away
+	isOpen := status == entities.COMMAND_STATUS_PENDING || status == entities.COMMAND_STATUS_DELIVERED
+	if isOpen && !c.ExpiresAt.After(time.Now()) {
+		status = entities.COMMAND_STATUS_EXPIRED
+	}
+
+	return &entities.DeviceCommand{
+		ID:             c.ID,
+		DeviceID:       c.DeviceID,
+		Name:           c.Name,
+		Params:         c.Params,
+		Status:         status,
+		Result:         c.Result,
+		ExpiresAt:      c.ExpiresAt,
+		DeliveredAt:    c.DeliveredAt,
+		AcknowledgedAt: c.AcknowledgedAt,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+func (r *repository) CreateDeviceCommand(ctx context.Context, payload entities.DeviceCommand) (string, error) {
+	var model DeviceCommand
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		query := fmt.Sprintf(`INSERT INTO device_commands
+			(device_id, name, params, status, expires_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING %s`, deviceCommandColumns)
+
+		err := tx.GetContext(
+			ctx,
+			&model,
+			query,
+			payload.DeviceID,
+			payload.Name,
+			JSONB(payload.Params),
+			entities.COMMAND_STATUS_PENDING,
+			payload.ExpiresAt,
+			payload.CreatedAt,
+			payload.UpdatedAt,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to CreateDeviceCommand",
+				slog.Any("err", err),
+				slog.Any("payload", payload),
+			)
+			return util.NewErrInternalServer("failed to create device command")
+		}
+
+		return insertOutboxEvent(ctx, tx, events.DEVICE_COMMAND_CREATED, model.DeviceID, model.ToEntity(), nowUTC)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return model.ID, nil
+}
+
+func (r *repository) GetDeviceCommand(ctx context.Context, commandID string) (*entities.DeviceCommand, error) {
+	var model DeviceCommand
+
+	query := fmt.Sprintf(`SELECT %s FROM device_commands WHERE id = $1`, deviceCommandColumns)
+	err := r.db.GetContext(ctx, &model, query, commandID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("device command not found")
+		}
+
+		slog.Error(
+			"Failed to GetDeviceCommand",
+			slog.Any("err", err),
+			slog.Any("commandID", commandID),
+		)
+		return nil, util.NewErrInternalServer("failed to get device command")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetDeviceCommandList(ctx context.Context, params entities.GetDeviceCommandListParams) ([]*entities.DeviceCommand, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "expires_at", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM device_commands"
+	queryData := fmt.Sprintf("SELECT %s FROM device_commands", deviceCommandColumns)
+
+	whereQueries := []string{"device_id = :device_id"}
+	switch entities.CommandStatus(params.Status) {
+	case "":
+	case entities.COMMAND_STATUS_EXPIRED:
+		whereQueries = append(whereQueries, "(status = :status OR (status IN ('pending', 'delivered') AND expires_at <= NOW()))")
+	case entities.COMMAND_STATUS_PENDING, entities.COMMAND_STATUS_DELIVERED:
+		whereQueries = append(whereQueries, "status = :status AND expires_at > NOW()")
+	default:
+		whereQueries = append(whereQueries, "status = :status")
+	}
+
+	whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+	queryCount += whereQuery
+	queryData += whereQuery
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	args := map[string]any{
+		"device_id": params.DeviceID,
+		"status":    params.Status,
+	}
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceCommandList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device command list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceCommandList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device command list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceCommandList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device command list")
+	}
+	defer stmtData.Close()
+
+	var model []DeviceCommand
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceCommandList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device command list")
+	}
+
+	commands := []*entities.DeviceCommand{}
+	for _, v := range model {
+		commands = append(commands, v.ToEntity())
+	}
+
+	return commands, total, nil
+}
+
+// DeliverDeviceCommands returns the unacknowledged commands of a device and marks them as delivered.
+// Delivered commands are returned again until acknowledged, so a device that crashed can resume them.
+func (r *repository) DeliverDeviceCommands(ctx context.Context, deviceID string) ([]*entities.DeviceCommand, error) {
+	nowUTC := time.Now().UTC()
+
+	query := fmt.Sprintf(`UPDATE device_commands
+		SET status = $1, delivered_at = COALESCE(delivered_at, $2), updated_at = $2
+		WHERE device_id = $3 AND status IN ($4, $1) AND expires_at > $2
+		RETURNING %s`, deviceCommandColumns)
+
+	var model []DeviceCommand
+	err := r.db.SelectContext(
+		ctx,
+		&model,
+		query,
+		entities.COMMAND_STATUS_DELIVERED,
+		nowUTC,
+		deviceID,
+		entities.COMMAND_STATUS_PENDING,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to DeliverDeviceCommands",
+			slog.Any("err", err),
+			slog.Any("deviceID", deviceID),
+		)
+		return nil, util.NewErrInternalServer("failed to get pending device commands")
+	}
+
+	sort.Slice(model, func(i, j int) bool {
+		return model[i].CreatedAt.Before(model[j].CreatedAt)
+	})
+
+	commands := []*entities.DeviceCommand{}
+	for _, v := range model {
+		commands = append(commands, v.ToEntity())
+	}
+
+	return commands, nil
+}
+
+func (r *repository) AcknowledgeDeviceCommand(ctx context.Context, commandID string, payload entities.AckDeviceCommandPayload) error {
+	nowUTC := time.Now().UTC()
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		query := fmt.Sprintf(`UPDATE device_commands
+			SET status = $1, result = $2, acknowledged_at = $3, updated_at = $3
+			WHERE id = $4 AND status IN ($5, $6) AND expires_at > $3
+			RETURNING %s`, deviceCommandColumns)
+
+		var model DeviceCommand
+		err := tx.GetContext(
+			ctx,
+			&model,
+			query,
+			payload.Status,
+			JSONB(payload.Result),
+			nowUTC,
+			commandID,
+			entities.COMMAND_STATUS_PENDING,
+			entities.COMMAND_STATUS_DELIVERED,
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrConflict("device command is already acknowledged or expired")
+			}
+
+			slog.Error(
+				"Failed to AcknowledgeDeviceCommand",
+				slog.Any("err", err),
+				slog.Any("commandID", commandID),
+				slog.Any("payload", payload),
+			)
+			return util.NewErrInternalServer("failed to acknowledge device command")
+		}
+
+		return insertOutboxEvent(ctx, tx, events.DEVICE_COMMAND_ACKNOWLEDGED, model.DeviceID, model.ToEntity(), nowUTC)
+	})
+}
+
+// ExpireDeviceCommands marks every open command past its TTL as expired and returns them.
+func (r *repository) ExpireDeviceCommands(ctx context.Context) ([]*entities.DeviceCommand, error) {
+	commands := []*entities.DeviceCommand{}
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		query := fmt.Sprintf(`UPDATE device_commands
+			SET status = $1, updated_at = $2
+			WHERE status IN ($3, $4) AND expires_at <= $2
+			RETURNING %s`, deviceCommandColumns)
+
+		var model []DeviceCommand
+		err := tx.SelectContext(
+			ctx,
+			&model,
+			query,
+			entities.COMMAND_STATUS_EXPIRED,
+			nowUTC,
+			entities.COMMAND_STATUS_PENDING,
+			entities.COMMAND_STATUS_DELIVERED,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to ExpireDeviceCommands",
+				slog.Any("err", err),
+			)
+			return util.NewErrInternalServer("failed to expire device commands")
+		}
+
+		for _, v := range model {
+			command := v.ToEntity()
+			err := insertOutboxEvent(ctx, tx, events.DEVICE_COMMAND_EXPIRED, command.DeviceID, command, nowUTC)
+			if err != nil {
+				return err
+			}
+			commands = append(commands, command)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
@@ -6,18 +6,34 @@ import (
 	"errors"
 	"fmt"
 	"go-api/internal/entities"
+	"go-api/pkg/expr"
 	"go-api/pkg/util"
 	"log/slog"
+	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+const deviceColumns = `id, name, description, status, zone_id, latitude, longitude, altitude, attributes, labels, version,
+	created_at, updated_at`
+
 type Device struct {
-	ID          string    `db:"id"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	Status      string    `db:"status"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID          string      `db:"id"`
+	Name        string      `db:"name"`
+	Description string      `db:"description"`
+	Status      string      `db:"status"`
+	ZoneID      *string     `db:"zone_id"`
+	Latitude    *float64    `db:"latitude"`
+	Longitude   *float64    `db:"longitude"`
+	Altitude    *float64    `db:"altitude"`
+	Distance    *float64    `db:"distance"`
+	Attributes  JSONB       `db:"attributes"`
+	Labels      StringJSONB `db:"labels"`
+	Version     int         `db:"version"`
+	CreatedAt   time.Time   `db:"created_at"`
+	UpdatedAt   time.Time   `db:"updated_at"`
 }
 
 func (d *Device) ToEntity() *entities.Device {
@@ -26,6 +42,14 @@ func (d *Device) ToEntity() *entities.Device {
 		Name:        d.Name,
 		Description: d.Description,
 		Status:      entities.DeviceStatus(d.Status),
+		ZoneID:      d.ZoneID,
+		Latitude:    d.Latitude,
+		Longitude:   d.Longitude,
+		Altitude:    d.Altitude,
+		Distance:    d.Distance,
+		Attributes:  d.Attributes,
+		Labels:      d.Labels,
+		Version:     d.Version,
 		CreatedAt:   d.CreatedAt,
 		UpdatedAt:   d.UpdatedAt,
 	}
@@ -34,20 +58,42 @@ func (d *Device) ToEntity() *entities.Device {
 func (r *repository) CreateDevice(ctx context.Context, payload entities.Device) (string, error) {
 	var deviceID string
 
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		deviceID, err = insertDevice(ctx, tx, payload)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return deviceID, nil
+}
+
+// insertDevice inserts a device and records its creation in the audit log of the transaction.
+func insertDevice(ctx context.Context, tx *sqlx.Tx, payload entities.Device) (string, error) {
+	var deviceID string
+
 	nowUTC := time.Now().UTC()
 	payload.CreatedAt = nowUTC
 	payload.UpdatedAt = nowUTC
 
 	query := `INSERT INTO devices 
-	(name, description, status, created_at, updated_at) 
-	VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	(name, description, status, zone_id, latitude, longitude, altitude, attributes, labels, created_at, updated_at) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
 
-	err := r.db.QueryRowxContext(
+	err := tx.QueryRowxContext(
 		ctx,
 		query,
 		payload.Name,
 		payload.Description,
 		payload.Status,
+		payload.ZoneID,
+		payload.Latitude,
+		payload.Longitude,
+		payload.Altitude,
+		JSONB(payload.Attributes),
+		StringJSONB(payload.Labels),
 		payload.CreatedAt,
 		payload.UpdatedAt,
 	).Scan(&deviceID)
@@ -61,56 +107,338 @@ func (r *repository) CreateDevice(ctx context.Context, payload entities.Device)
 		return deviceID, util.NewErrInternalServer("failed to create device")
 	}
 
+	err = recordAudit(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_ACTION_CREATE, []string{deviceID}, nil)
+	if err != nil {
+		return deviceID, err
+	}
+
 	return deviceID, nil
 }
 
-func (r *repository) UpdateDevice(ctx context.Context, deviceID string, payload entities.Device) error {
-	query := `UPDATE devices 
-	SET name = $1, description = $2, status = $3, updated_at = $4 
-	WHERE id = $5`
+// CreateDeviceWithSensors creates a device and its sensors in one transaction.
+func (r *repository) CreateDeviceWithSensors(ctx context.Context, device entities.Device, sensors []entities.Sensor) (string, error) {
+	var deviceID string
 
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		payload.Name,
-		payload.Description,
-		payload.Status,
-		time.Now().UTC(),
-		deviceID,
-	)
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		deviceID, err = insertDevice(ctx, tx, device)
+		if err != nil {
+			return err
+		}
+
+		for _, sensor := range sensors {
+			sensor.DeviceID = deviceID
+			_, err = insertSensor(ctx, tx, sensor)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		slog.Error(
-			"Failed to UpdateDevice",
-			slog.Any("err", err),
-			slog.Any("deviceID", deviceID),
-			slog.Any("payload", payload),
-		)
-		return util.NewErrInternalServer("failed to update device")
+		return "", err
 	}
 
-	return nil
+	return deviceID, nil
+}
+
+// UpdateDevice updates a device when its version is one of versions, any version when versions is nil.
+func (r *repository) UpdateDevice(ctx context.Context, deviceID string, payload entities.Device, versions []int) error {
+	query := `UPDATE devices 
+	SET name = $1, description = $2, status = $3, zone_id = $4, latitude = $5, longitude = $6, altitude = $7,
+		attributes = $8, labels = $9, updated_at = $10, version = version + 1 
+	WHERE id = $11 AND deleted_at IS NULL`
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		before, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, []string{deviceID})
+		if err != nil {
+			return err
+		}
+		version, ok := activeVersion(before[deviceID])
+		if !ok {
+			return util.NewErrNotFound("device not found")
+		}
+		if !versionMatches(versions, version) {
+			return util.NewErrPreconditionFailed("device has been modified")
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			payload.Name,
+			payload.Description,
+			payload.Status,
+			payload.ZoneID,
+			payload.Latitude,
+			payload.Longitude,
+			payload.Altitude,
+			JSONB(payload.Attributes),
+			StringJSONB(payload.Labels),
+			time.Now().UTC(),
+			deviceID,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateDevice",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+				slog.Any("payload", payload),
+			)
+			return util.NewErrInternalServer("failed to update device")
+		}
+
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_ACTION_UPDATE, []string{deviceID}, before)
+	})
 }
 
-func (r *repository) DeleteDevice(ctx context.Context, deviceID string) error {
-	query := `DELETE FROM devices WHERE id = $1`
+// UpdateDevicesStatus sets the status of every given device and returns how many were changed.
+func (r *repository) UpdateDevicesStatus(ctx context.Context, deviceIDs []string, status entities.DeviceStatus) (int64, error) {
+	query := `UPDATE devices SET status = $1, updated_at = $2, version = version + 1 
+		WHERE id = ANY($3) AND status <> $1 AND deleted_at IS NULL`
 
-	_, err := r.db.ExecContext(ctx, query, deviceID)
+	var updated int64
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		before, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, deviceIDs)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, query, status, time.Now().UTC(), pq.Array(deviceIDs))
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateDevicesStatus",
+				slog.Any("err", err),
+				slog.Any("deviceIDs", deviceIDs),
+				slog.Any("status", status),
+			)
+			return util.NewErrInternalServer("failed to update device status")
+		}
+
+		updated, err = res.RowsAffected()
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateDevicesStatus RowsAffected",
+				slog.Any("err", err),
+			)
+			return util.NewErrInternalServer("failed to update device status")
+		}
+
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_ACTION_UPDATE, deviceIDs, before)
+	})
 	if err != nil {
-		slog.Error(
-			"Failed to DeleteDevice",
-			slog.Any("err", err),
-			slog.Any("deviceID", deviceID),
-		)
-		return util.NewErrInternalServer("failed to delete device")
+		return 0, err
 	}
 
-	return err
+	return updated, nil
+}
+
+// DeleteDevice moves a device without sensors to the trash. With cascade its sensors are moved to the trash with
+// it in the same transaction, unless virtual sensors of other devices reference them. As with UpdateDevice, the
+// device must be at one of versions unless versions is nil.
+func (r *repository) DeleteDevice(ctx context.Context, deviceID string, cascade bool, versions []int) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var device struct {
+			Version   int            `db:"version"`
+			SensorIDs pq.StringArray `db:"sensor_ids"`
+		}
+		err := tx.GetContext(
+			ctx,
+			&device,
+			`SELECT d.version, ARRAY(SELECT id FROM sensors WHERE device_id = d.id AND deleted_at IS NULL) AS sensor_ids 
+			FROM devices d WHERE d.id = $1 AND d.deleted_at IS NULL FOR UPDATE`,
+			deviceID,
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("device not found")
+			}
+
+			slog.Error(
+				"Failed to DeleteDevice sensors",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to delete device")
+		}
+		if !versionMatches(versions, device.Version) {
+			return util.NewErrPreconditionFailed("device has been modified")
+		}
+
+		sensorIDs := device.SensorIDs
+		if len(sensorIDs) > 0 && !cascade {
+			return util.NewErrConflict("device still has sensors")
+		}
+
+		deletedAt := time.Now().UTC()
+
+		devicesBefore, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, []string{deviceID})
+		if err != nil {
+			return err
+		}
+		sensorsBefore, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, sensorIDs)
+		if err != nil {
+			return err
+		}
+
+		if len(sensorIDs) > 0 {
+			var dependents []string
+			err = tx.SelectContext(
+				ctx,
+				&dependents,
+				`SELECT id FROM sensors WHERE device_id <> $1 AND deleted_at IS NULL AND expression_refs && $2`,
+				deviceID,
+				sensorIDs,
+			)
+			if err != nil {
+				slog.Error(
+					"Failed to DeleteDevice dependents",
+					slog.Any("err", err),
+					slog.Any("deviceID", deviceID),
+				)
+				return util.NewErrInternalServer("failed to delete device")
+			}
+			if len(dependents) > 0 {
+				return util.NewErrConflict(fmt.Sprintf("device sensors are referenced by virtual sensors (%s)", strings.Join(dependents, ", ")))
+			}
+
+			_, err = tx.ExecContext(ctx, `UPDATE sensors SET deleted_at = $1, version = version + 1 WHERE id = ANY($2)`, deletedAt, sensorIDs)
+			if err != nil {
+				slog.Error(
+					"Failed to DeleteDevice sensors",
+					slog.Any("err", err),
+					slog.Any("deviceID", deviceID),
+				)
+				return util.NewErrInternalServer("failed to delete device")
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE devices SET deleted_at = $1, version = version + 1 WHERE id = $2`, deletedAt, deviceID)
+		if err != nil {
+			slog.Error(
+				"Failed to DeleteDevice",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to delete device")
+		}
+
+		err = recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_DELETE, sensorIDs, sensorsBefore)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_ACTION_DELETE, []string{deviceID}, devicesBefore)
+	})
+}
+
+// RestoreDevice takes a device out of the trash with the sensors deleted together with it. It fails when one
+// of these virtual sensors references a sensor that stays in the trash.
+func (r *repository) RestoreDevice(ctx context.Context, deviceID string) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var deletedAt time.Time
+		err := tx.GetContext(
+			ctx,
+			&deletedAt,
+			`SELECT deleted_at FROM devices WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`,
+			deviceID,
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("deleted device not found")
+			}
+
+			slog.Error(
+				"Failed to RestoreDevice",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to restore device")
+		}
+
+		var missing []string
+		err = tx.SelectContext(
+			ctx,
+			&missing,
+			`SELECT DISTINCT ref.id FROM sensors s
+			JOIN sensors ref ON ref.id = ANY(s.expression_refs)
+			WHERE s.device_id = $1 AND s.deleted_at = $2 
+			AND ref.deleted_at IS NOT NULL AND NOT (ref.device_id = $1 AND ref.deleted_at = $2)`,
+			deviceID,
+			deletedAt,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to RestoreDevice references",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to restore device")
+		}
+		if len(missing) > 0 {
+			return util.NewErrConflict(fmt.Sprintf("virtual sensors of the device reference deleted sensors (%s)", strings.Join(missing, ", ")))
+		}
+
+		var sensorIDs []string
+		err = tx.SelectContext(ctx, &sensorIDs, `SELECT id FROM sensors WHERE device_id = $1 AND deleted_at = $2`, deviceID, deletedAt)
+		if err != nil {
+			slog.Error(
+				"Failed to RestoreDevice sensors",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to restore device")
+		}
+
+		devicesBefore, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, []string{deviceID})
+		if err != nil {
+			return err
+		}
+		sensorsBefore, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, sensorIDs)
+		if err != nil {
+			return err
+		}
+
+		queries := []struct {
+			query string
+			args  []any
+		}{
+			{
+				`UPDATE sensors SET deleted_at = NULL, version = version + 1 WHERE id = ANY($1)`,
+				[]any{pq.Array(sensorIDs)},
+			},
+			{
+				`UPDATE devices SET deleted_at = NULL, version = version + 1 WHERE id = $1`,
+				[]any{deviceID},
+			},
+		}
+
+		for _, q := range queries {
+			_, err = tx.ExecContext(ctx, q.query, q.args...)
+			if err != nil {
+				slog.Error(
+					"Failed to RestoreDevice",
+					slog.Any("err", err),
+					slog.Any("deviceID", deviceID),
+				)
+				return util.NewErrInternalServer("failed to restore device")
+			}
+		}
+
+		err = recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_RESTORE, sensorIDs, sensorsBefore)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_ACTION_RESTORE, []string{deviceID}, devicesBefore)
+	})
 }
 
 func (r *repository) GetDevice(ctx context.Context, deviceID string) (*entities.Device, error) {
 	var model Device
 
-	query := `SELECT id, name, description, status, created_at, updated_at FROM devices WHERE id = $1`
+	query := fmt.Sprintf(`SELECT %s FROM devices WHERE id = $1 AND deleted_at IS NULL`, deviceColumns)
 	err := r.db.GetContext(ctx, &model, query, deviceID)
 
 	if err != nil {
@@ -137,11 +465,22 @@ func (r *repository) GetDeviceList(ctx context.Context, params entities.GetDevic
 	)
 
 	queryCount := "SELECT COUNT(id) FROM devices"
-	queryData := "SELECT id, name, description, status, created_at, updated_at FROM devices"
+	queryData := fmt.Sprintf("SELECT %s FROM devices", deviceColumns)
+
+	// devices near a point carry their distance and are ordered by it unless another sort is given
+	if params.Geo.Near != nil {
+		queryData = fmt.Sprintf("SELECT %s, %s AS distance FROM devices", deviceColumns, geoDistanceQuery("latitude", "longitude"))
+		orderBy = util.SortValidation(params.Sort, append(availableSorts, "distance"))
+		if params.Sort == "" {
+			orderBy = "distance ASC"
+		}
+	}
+
+	args := map[string]any{}
+	whereQueries := deviceFilterQueries(params, args)
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
 
-	if params.Search != "" {
-		params.Search = fmt.Sprintf("%%%s%%", params.Search)
-		whereQuery := " WHERE (name LIKE :keyword OR description LIKE :keyword)"
 		queryCount += whereQuery
 		queryData += whereQuery
 	}
@@ -161,9 +500,7 @@ func (r *repository) GetDeviceList(ctx context.Context, params entities.GetDevic
 	}
 	defer stmtCount.Close()
 
-	err = stmtCount.GetContext(ctx, &total, map[string]any{
-		"keyword": params.Search,
-	})
+	err = stmtCount.GetContext(ctx, &total, args)
 	if err != nil {
 		slog.Error(
 			"Failed to GetDeviceList Count GetContext",
@@ -191,9 +528,7 @@ func (r *repository) GetDeviceList(ctx context.Context, params entities.GetDevic
 	defer stmtData.Close()
 
 	var model []Device
-	err = stmtData.SelectContext(ctx, &model, map[string]any{
-		"keyword": params.Search,
-	})
+	err = stmtData.SelectContext(ctx, &model, args)
 	if err != nil {
 		slog.Error(
 			"Failed to GetDeviceList Data SelectContext",
@@ -210,3 +545,194 @@ func (r *repository) GetDeviceList(ctx context.Context, params entities.GetDevic
 
 	return devices, total, nil
 }
+
+// GetDeviceIDList returns the IDs of every device matching the list filter, without pagination.
+func (r *repository) GetDeviceIDList(ctx context.Context, params entities.GetDeviceListParams) ([]string, error) {
+	args := map[string]any{}
+	query := "SELECT id FROM devices"
+	whereQueries := deviceFilterQueries(params, args)
+	if len(whereQueries) > 0 {
+		query += fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+	}
+
+	stmt, err := r.db.PrepareNamed(query)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceIDList PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, util.NewErrInternalServer("failed to get device list")
+	}
+	defer stmt.Close()
+
+	deviceIDs := []string{}
+	err = stmt.SelectContext(ctx, &deviceIDs, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceIDList SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, util.NewErrInternalServer("failed to get device list")
+	}
+
+	return deviceIDs, nil
+}
+
+// deviceFilterQueries builds the WHERE conditions of a device list, shared with GetDeviceIDList.
+func deviceFilterQueries(params entities.GetDeviceListParams, args map[string]any) []string {
+	whereQueries := []string{"deleted_at IS NULL"}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if len(params.Attributes) > 0 {
+		whereQueries = append(whereQueries, attributeFilterQueries("attributes", params.Attributes, args)...)
+	}
+	if len(params.Selector) > 0 {
+		whereQueries = append(whereQueries, labelSelectorQueries("labels", params.Selector, args)...)
+	}
+	if params.GroupID != "" {
+		args["group_id"] = params.GroupID
+		whereQueries = append(whereQueries, "id IN (SELECT device_id FROM device_group_members WHERE group_id = :group_id)")
+	}
+	whereQueries = append(whereQueries, locationFilterQueries("zone_id", params.Location, args)...)
+	whereQueries = append(whereQueries, geoFilterQueries("latitude", "longitude", params.Geo, args)...)
+
+	return whereQueries
+}
+
+type ClonedSensor struct {
+	SourceID   string `db:"source_id"`
+	ID         string `db:"id"`
+	Expression string `db:"expression"`
+}
+
+// CloneDevice copies a device with its sensors, labels and attributes once per name, in one transaction,
+// virtual sensors referencing sensors of the same device reference the copies.
+func (r *repository) CloneDevice(ctx context.Context, deviceID string, names []string) ([]*entities.ClonedDevice, error) {
+	clones := []*entities.ClonedDevice{}
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		queryDevice := `INSERT INTO devices
+			(name, description, status, zone_id, latitude, longitude, altitude, attributes, labels, created_at, updated_at)
+			SELECT $1, description, status, zone_id, latitude, longitude, altitude, attributes, labels, $2, $2
+			FROM devices WHERE id = $3 AND deleted_at IS NULL RETURNING id`
+
+		// new sensor IDs are generated up front, so expressions of virtual sensors referencing sensors
+		// of the same device can be pointed at the clones
+		querySensors := `WITH source AS (
+				SELECT id AS source_id, uuid_generate_v4() AS id, type, name, description, attributes, labels,
+					expression, expression_refs
+				FROM sensors WHERE device_id = $3 AND deleted_at IS NULL
+			), sensor AS (
+				INSERT INTO sensors
+				(id, device_id, type, name, description, attributes, labels, expression, expression_refs, created_at, updated_at)
+				SELECT id, $1, type, name, description, attributes, labels, expression, expression_refs, $2, $2
+				FROM source RETURNING id, device_id, created_at
+			), history AS (
+				INSERT INTO sensor_device_history (sensor_id, device_id, attached_at, created_at)
+				SELECT id, device_id, created_at, created_at FROM sensor
+			)
+			SELECT source_id, id, COALESCE(expression, '') AS expression FROM source`
+
+		queryExpression := `UPDATE sensors SET expression = $1, expression_refs = $2 WHERE id = $3`
+
+		for _, name := range names {
+			clone := &entities.ClonedDevice{Name: name, SensorIDs: []string{}}
+
+			err := tx.GetContext(ctx, &clone.DeviceID, queryDevice, name, nowUTC, deviceID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return util.NewErrNotFound("device not found")
+				}
+
+				slog.Error(
+					"Failed to CloneDevice",
+					slog.Any("err", err),
+					slog.Any("deviceID", deviceID),
+					slog.Any("name", name),
+				)
+				return util.NewErrInternalServer("failed to clone device")
+			}
+
+			var sensors []ClonedSensor
+			err = tx.SelectContext(ctx, &sensors, querySensors, clone.DeviceID, nowUTC, deviceID)
+			if err != nil {
+				slog.Error(
+					"Failed to CloneDevice sensors",
+					slog.Any("err", err),
+					slog.Any("deviceID", deviceID),
+					slog.Any("name", name),
+				)
+				return util.NewErrInternalServer("failed to clone device")
+			}
+
+			sensorIDs := map[string]string{}
+			for _, v := range sensors {
+				sensorIDs[v.SourceID] = v.ID
+				clone.SensorIDs = append(clone.SensorIDs, v.ID)
+			}
+
+			for _, v := range sensors {
+				if v.Expression == "" {
+					continue
+				}
+
+				e, err := expr.Parse(v.Expression)
+				if err != nil {
+					slog.Error(
+						"Failed to CloneDevice parse sensor expression",
+						slog.Any("err", err),
+						slog.Any("deviceID", deviceID),
+						slog.Any("sensorID", v.SourceID),
+					)
+					return util.NewErrInternalServer("failed to clone device")
+				}
+
+				expression, refs, err := sensorExpression(e.Rename(sensorIDs))
+				if err != nil {
+					slog.Error(
+						"Failed to CloneDevice rename sensor expression",
+						slog.Any("err", err),
+						slog.Any("deviceID", deviceID),
+						slog.Any("sensorID", v.SourceID),
+					)
+					return util.NewErrInternalServer("failed to clone device")
+				}
+
+				_, err = tx.ExecContext(ctx, queryExpression, expression, refs, v.ID)
+				if err != nil {
+					slog.Error(
+						"Failed to CloneDevice sensor expression",
+						slog.Any("err", err),
+						slog.Any("deviceID", deviceID),
+						slog.Any("sensorID", v.ID),
+					)
+					return util.NewErrInternalServer("failed to clone device")
+				}
+			}
+
+			err = recordAudit(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_ACTION_CREATE, []string{clone.DeviceID}, nil)
+			if err != nil {
+				return err
+			}
+			err = recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_CREATE, clone.SensorIDs, nil)
+			if err != nil {
+				return err
+			}
+
+			clones = append(clones, clone)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clones, nil
+}
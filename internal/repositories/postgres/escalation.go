@@ -0,0 +1,693 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	escalationPolicyColumns = `id, name, description, steps, repeat_seconds, group_by, group_wait_seconds,
+	group_interval_seconds, dedup_window_seconds, rate_limit_per_hour, enabled, created_at, updated_at`
+	alertEscalationColumns        = `alert_id, policy_id, step, fired_at, next_notify_at, notified_at, created_at, updated_at`
+	escalationNotificationColumns = `id, policy_id, step, repeat, group_key, alert_ids, emails, webhook_ids, error, created_at`
+)
+
+// EscalationSteps maps the jsonb steps column of a policy.
+type EscalationSteps []entities.EscalationStep
+
+func (s EscalationSteps) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *EscalationSteps) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		*s = EscalationSteps{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for EscalationSteps")
+	}
+
+	result := EscalationSteps{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*s = result
+
+	return nil
+}
+
+type EscalationPolicy struct {
+	ID                   string          `db:"id"`
+	Name                 string          `db:"name"`
+	Description          string          `db:"description"`
+	Steps                EscalationSteps `db:"steps"`
+	RepeatSeconds        int             `db:"repeat_seconds"`
+	GroupBy              pq.StringArray  `db:"group_by"`
+	GroupWaitSeconds     int             `db:"group_wait_seconds"`
+	GroupIntervalSeconds int             `db:"group_interval_seconds"`
+	DedupWindowSeconds   int             `db:"dedup_window_seconds"`
+	RateLimitPerHour     int             `db:"rate_limit_per_hour"`
+	Enabled              bool            `db:"enabled"`
+	CreatedAt            time.Time       `db:"created_at"`
+	UpdatedAt            time.Time       `db:"updated_at"`
+}
+
+func (e *EscalationPolicy) ToEntity() *entities.EscalationPolicy {
+	steps := []entities.EscalationStep{}
+	for _, v := range e.Steps {
+		if v.Emails == nil {
+			v.Emails = []string{}
+		}
+		if v.WebhookIDs == nil {
+			v.WebhookIDs = []string{}
+		}
+		steps = append(steps, v)
+	}
+
+	groupBy := []entities.EscalationGroupBy{}
+	for _, v := range e.GroupBy {
+		groupBy = append(groupBy, entities.EscalationGroupBy(v))
+	}
+
+	return &entities.EscalationPolicy{
+		ID:             e.ID,
+		Name:           e.Name,
+		Description:    e.Description,
+		Steps:          steps,
+		RepeatInterval: e.RepeatSeconds,
+		GroupBy:        groupBy,
+		GroupWait:      e.GroupWaitSeconds,
+		GroupInterval:  e.GroupIntervalSeconds,
+		DedupWindow:    e.DedupWindowSeconds,
+		RateLimit:      e.RateLimitPerHour,
+		Enabled:        e.Enabled,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
+type AlertEscalation struct {
+	AlertID      string     `db:"alert_id"`
+	PolicyID     string     `db:"policy_id"`
+	Step         int        `db:"step"`
+	FiredAt      time.Time  `db:"fired_at"`
+	NextNotifyAt *time.Time `db:"next_notify_at"`
+	NotifiedAt   *time.Time `db:"notified_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+func (a *AlertEscalation) ToEntity() *entities.AlertEscalation {
+	return &entities.AlertEscalation{
+		AlertID:      a.AlertID,
+		PolicyID:     a.PolicyID,
+		Step:         a.Step,
+		FiredAt:      a.FiredAt,
+		NextNotifyAt: a.NextNotifyAt,
+		NotifiedAt:   a.NotifiedAt,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+// DueAlertEscalation is an escalation with its alert, the escalation columns are prefixed to keep them
+// apart from the alert columns.
+type DueAlertEscalation struct {
+	Alert
+	PolicyID     string     `db:"escalation_policy_id"`
+	Step         int        `db:"escalation_step"`
+	EscFiredAt   time.Time  `db:"escalation_fired_at"`
+	NextNotifyAt *time.Time `db:"escalation_next_notify_at"`
+	NotifiedAt   *time.Time `db:"escalation_notified_at"`
+}
+
+func (d *DueAlertEscalation) ToEntity() *entities.AlertEscalation {
+	return &entities.AlertEscalation{
+		AlertID:      d.Alert.ID,
+		PolicyID:     d.PolicyID,
+		Step:         d.Step,
+		FiredAt:      d.EscFiredAt,
+		NextNotifyAt: d.NextNotifyAt,
+		NotifiedAt:   d.NotifiedAt,
+		Alert:        d.Alert.ToEntity(),
+	}
+}
+
+type EscalationNotification struct {
+	ID         string         `db:"id"`
+	PolicyID   string         `db:"policy_id"`
+	Step       int            `db:"step"`
+	Repeat     bool           `db:"repeat"`
+	GroupKey   string         `db:"group_key"`
+	AlertIDs   pq.StringArray `db:"alert_ids"`
+	Emails     pq.StringArray `db:"emails"`
+	WebhookIDs pq.StringArray `db:"webhook_ids"`
+	Error      string         `db:"error"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (e *EscalationNotification) ToEntity() *entities.EscalationNotification {
+	return &entities.EscalationNotification{
+		ID:         e.ID,
+		PolicyID:   e.PolicyID,
+		Step:       e.Step,
+		Repeat:     e.Repeat,
+		GroupKey:   e.GroupKey,
+		AlertIDs:   append([]string{}, e.AlertIDs...),
+		Emails:     append([]string{}, e.Emails...),
+		WebhookIDs: append([]string{}, e.WebhookIDs...),
+		Error:      e.Error,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+func escalationGroupBy(groupBy []entities.EscalationGroupBy) []string {
+	result := []string{}
+	for _, v := range groupBy {
+		result = append(result, string(v))
+	}
+	return result
+}
+
+func (r *repository) CreateEscalationPolicy(ctx context.Context, payload entities.EscalationPolicy) (string, error) {
+	var policyID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO escalation_policies
+		(name, description, steps, repeat_seconds, group_by, group_wait_seconds, group_interval_seconds,
+			dedup_window_seconds, rate_limit_per_hour, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		EscalationSteps(payload.Steps),
+		payload.RepeatInterval,
+		pq.Array(escalationGroupBy(payload.GroupBy)),
+		payload.GroupWait,
+		payload.GroupInterval,
+		payload.DedupWindow,
+		payload.RateLimit,
+		payload.Enabled,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&policyID)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateEscalationPolicy",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return policyID, util.NewErrInternalServer("failed to create escalation policy")
+	}
+
+	return policyID, nil
+}
+
+// UpdateEscalationPolicy changes a policy, the escalations in progress follow the new steps from their next
+// notification on.
+func (r *repository) UpdateEscalationPolicy(ctx context.Context, policyID string, payload entities.EscalationPolicy) error {
+	query := `UPDATE escalation_policies
+		SET name = $1, description = $2, steps = $3, repeat_seconds = $4, group_by = $5, group_wait_seconds = $6,
+			group_interval_seconds = $7, dedup_window_seconds = $8, rate_limit_per_hour = $9, enabled = $10, updated_at = $11
+		WHERE id = $12`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		EscalationSteps(payload.Steps),
+		payload.RepeatInterval,
+		pq.Array(escalationGroupBy(payload.GroupBy)),
+		payload.GroupWait,
+		payload.GroupInterval,
+		payload.DedupWindow,
+		payload.RateLimit,
+		payload.Enabled,
+		time.Now().UTC(),
+		policyID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateEscalationPolicy",
+			slog.Any("err", err),
+			slog.Any("policyID", policyID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update escalation policy")
+	}
+
+	return nil
+}
+
+// DeleteEscalationPolicy deletes a policy with its escalations and notification log, its rules are left
+// without a policy.
+func (r *repository) DeleteEscalationPolicy(ctx context.Context, policyID string) error {
+	query := `DELETE FROM escalation_policies WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, policyID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteEscalationPolicy",
+			slog.Any("err", err),
+			slog.Any("policyID", policyID),
+		)
+		return util.NewErrInternalServer("failed to delete escalation policy")
+	}
+
+	return nil
+}
+
+func (r *repository) GetEscalationPolicy(ctx context.Context, policyID string) (*entities.EscalationPolicy, error) {
+	var model EscalationPolicy
+
+	query := fmt.Sprintf(`SELECT %s FROM escalation_policies WHERE id = $1`, escalationPolicyColumns)
+	err := r.db.GetContext(ctx, &model, query, policyID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("escalation policy not found")
+		}
+
+		slog.Error(
+			"Failed to GetEscalationPolicy",
+			slog.Any("err", err),
+			slog.Any("policyID", policyID),
+		)
+		return nil, util.NewErrInternalServer("failed to get escalation policy")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetEscalationPolicyList(ctx context.Context, params entities.GetEscalationPolicyListParams) ([]*entities.EscalationPolicy, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM escalation_policies"
+	queryData := fmt.Sprintf("SELECT %s FROM escalation_policies", escalationPolicyColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationPolicyList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation policy list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationPolicyList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation policy list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationPolicyList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation policy list")
+	}
+	defer stmtData.Close()
+
+	var model []EscalationPolicy
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationPolicyList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation policy list")
+	}
+
+	policies := []*entities.EscalationPolicy{}
+	for _, v := range model {
+		policies = append(policies, v.ToEntity())
+	}
+
+	return policies, total, nil
+}
+
+// startAlertEscalation starts the escalation of an alert that fired in tx, when its rule has an enabled policy.
+// The escalation is locked, so it cannot be saved concurrently by a notification of its previous firing.
+func startAlertEscalation(ctx context.Context, tx *sqlx.Tx, alert *entities.Alert, now time.Time) error {
+	var policy EscalationPolicy
+	query := fmt.Sprintf(`SELECT %s FROM escalation_policies
+		WHERE id = (SELECT escalation_policy_id FROM alert_rules WHERE id = $1) AND enabled`, escalationPolicyColumns)
+
+	err := tx.GetContext(ctx, &policy, query, alert.RuleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		slog.Error(
+			"Failed to startAlertEscalation Get Policy",
+			slog.Any("err", err),
+			slog.Any("alertID", alert.ID),
+		)
+		return util.NewErrInternalServer("failed to start alert escalation")
+	}
+
+	var model AlertEscalation
+	query = fmt.Sprintf(`SELECT %s FROM alert_escalations WHERE alert_id = $1 FOR UPDATE`, alertEscalationColumns)
+
+	escalation := &entities.AlertEscalation{AlertID: alert.ID}
+	err = tx.GetContext(ctx, &model, query, alert.ID)
+	switch {
+	case err == nil:
+		escalation = model.ToEntity()
+	case !errors.Is(err, sql.ErrNoRows):
+		slog.Error(
+			"Failed to startAlertEscalation Get",
+			slog.Any("err", err),
+			slog.Any("alertID", alert.ID),
+		)
+		return util.NewErrInternalServer("failed to start alert escalation")
+	}
+
+	firedAt := now
+	if alert.FiredAt != nil {
+		firedAt = *alert.FiredAt
+	}
+	policy.ToEntity().Start(escalation, firedAt, now)
+
+	query = `INSERT INTO alert_escalations
+		(alert_id, policy_id, step, fired_at, next_notify_at, notified_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (alert_id) DO UPDATE
+		SET policy_id = EXCLUDED.policy_id, step = EXCLUDED.step, fired_at = EXCLUDED.fired_at,
+			next_notify_at = EXCLUDED.next_notify_at, notified_at = EXCLUDED.notified_at, updated_at = EXCLUDED.updated_at`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		alert.ID,
+		escalation.PolicyID,
+		escalation.Step,
+		escalation.FiredAt,
+		escalation.NextNotifyAt,
+		escalation.NotifiedAt,
+		now,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to startAlertEscalation Upsert",
+			slog.Any("err", err),
+			slog.Any("alertID", alert.ID),
+		)
+		return util.NewErrInternalServer("failed to start alert escalation")
+	}
+
+	return nil
+}
+
+func (r *repository) GetAlertEscalation(ctx context.Context, alertID string) (*entities.AlertEscalation, error) {
+	var model AlertEscalation
+
+	query := fmt.Sprintf(`SELECT %s FROM alert_escalations WHERE alert_id = $1`, alertEscalationColumns)
+	err := r.db.GetContext(ctx, &model, query, alertID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("alert escalation not found")
+		}
+
+		slog.Error(
+			"Failed to GetAlertEscalation",
+			slog.Any("err", err),
+			slog.Any("alertID", alertID),
+		)
+		return nil, util.NewErrInternalServer("failed to get alert escalation")
+	}
+
+	return model.ToEntity(), nil
+}
+
+// GetDueAlertEscalations returns the escalations whose next notification is due, with their alert,
+// in the order they are due.
+func (r *repository) GetDueAlertEscalations(ctx context.Context, now time.Time, limit int) ([]*entities.AlertEscalation, error) {
+	query := fmt.Sprintf(`SELECT %s, e.policy_id AS escalation_policy_id, e.step AS escalation_step,
+			e.fired_at AS escalation_fired_at, e.next_notify_at AS escalation_next_notify_at,
+			e.notified_at AS escalation_notified_at
+		FROM alert_escalations e
+		JOIN alert_states a ON a.id = e.alert_id
+		JOIN alert_rules r ON r.id = a.rule_id
+		WHERE e.next_notify_at <= $1 AND %s
+		ORDER BY e.next_notify_at LIMIT %d`, alertColumns, alertNotDeletedQuery, limit)
+
+	var model []DueAlertEscalation
+	err := r.db.SelectContext(ctx, &model, query, now)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDueAlertEscalations",
+			slog.Any("err", err),
+		)
+		return nil, util.NewErrInternalServer("failed to get due alert escalations")
+	}
+
+	escalations := []*entities.AlertEscalation{}
+	for _, v := range model {
+		escalations = append(escalations, v.ToEntity())
+	}
+
+	return escalations, nil
+}
+
+// SaveAlertEscalations stores the progress of escalations and, when it is not nil, the notification that
+// made it, in one transaction.
+func (r *repository) SaveAlertEscalations(ctx context.Context, escalations []*entities.AlertEscalation, notification *entities.EscalationNotification) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		if notification != nil {
+			query := `INSERT INTO escalation_notifications
+				(policy_id, step, repeat, group_key, alert_ids, emails, webhook_ids, error, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+
+			err := tx.QueryRowxContext(
+				ctx,
+				query,
+				notification.PolicyID,
+				notification.Step,
+				notification.Repeat,
+				notification.GroupKey,
+				pq.Array(notification.AlertIDs),
+				pq.Array(notification.Emails),
+				pq.Array(notification.WebhookIDs),
+				notification.Error,
+				notification.CreatedAt,
+			).Scan(&notification.ID)
+			if err != nil {
+				slog.Error(
+					"Failed to SaveAlertEscalations Insert",
+					slog.Any("err", err),
+					slog.Any("policyID", notification.PolicyID),
+				)
+				return util.NewErrInternalServer("failed to save alert escalations")
+			}
+		}
+
+		query := `UPDATE alert_escalations
+			SET step = $1, fired_at = $2, next_notify_at = $3, notified_at = $4, updated_at = $5
+			WHERE alert_id = $6`
+
+		for _, v := range escalations {
+			_, err := tx.ExecContext(ctx, query, v.Step, v.FiredAt, v.NextNotifyAt, v.NotifiedAt, nowUTC, v.AlertID)
+			if err != nil {
+				slog.Error(
+					"Failed to SaveAlertEscalations Update",
+					slog.Any("err", err),
+					slog.Any("alertID", v.AlertID),
+				)
+				return util.NewErrInternalServer("failed to save alert escalations")
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetEscalationNotificationTimes returns when the notifications of a policy after a time were sent, oldest first.
+func (r *repository) GetEscalationNotificationTimes(ctx context.Context, policyID string, after time.Time) ([]time.Time, error) {
+	times := []time.Time{}
+
+	query := `SELECT created_at FROM escalation_notifications WHERE policy_id = $1 AND created_at > $2 ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &times, query, policyID, after)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationNotificationTimes",
+			slog.Any("err", err),
+			slog.Any("policyID", policyID),
+		)
+		return nil, util.NewErrInternalServer("failed to get escalation notifications")
+	}
+
+	return times, nil
+}
+
+// GetLastEscalationNotificationAt returns when a group of a policy was last notified, nil when never.
+func (r *repository) GetLastEscalationNotificationAt(ctx context.Context, policyID, groupKey string) (*time.Time, error) {
+	var last *time.Time
+
+	query := `SELECT MAX(created_at) FROM escalation_notifications WHERE policy_id = $1 AND group_key = $2`
+	err := r.db.GetContext(ctx, &last, query, policyID, groupKey)
+	if err != nil {
+		slog.Error(
+			"Failed to GetLastEscalationNotificationAt",
+			slog.Any("err", err),
+			slog.Any("policyID", policyID),
+		)
+		return nil, util.NewErrInternalServer("failed to get last escalation notification")
+	}
+
+	return last, nil
+}
+
+func (r *repository) GetEscalationNotificationList(ctx context.Context, params entities.GetEscalationNotificationListParams) ([]*entities.EscalationNotification, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"step", "created_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM escalation_notifications"
+	queryData := fmt.Sprintf("SELECT %s FROM escalation_notifications", escalationNotificationColumns)
+
+	args := map[string]any{
+		"policy_id": params.PolicyID,
+	}
+	whereQueries := []string{"policy_id = :policy_id"}
+	if params.AlertID != "" {
+		args["alert_id"] = params.AlertID
+		whereQueries = append(whereQueries, ":alert_id = ANY(CAST(alert_ids AS TEXT[]))")
+	}
+
+	whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+	queryCount += whereQuery
+	queryData += whereQuery
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationNotificationList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation notification list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationNotificationList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation notification list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationNotificationList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation notification list")
+	}
+	defer stmtData.Close()
+
+	var model []EscalationNotification
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEscalationNotificationList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get escalation notification list")
+	}
+
+	notifications := []*entities.EscalationNotification{}
+	for _, v := range model {
+		notifications = append(notifications, v.ToEntity())
+	}
+
+	return notifications, total, nil
+}
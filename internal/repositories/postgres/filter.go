@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"sort"
+	"strings"
+)
+
+// attributeFilterQueries builds containment conditions on a jsonb column for attribute filters.
+// A value that is also a JSON number or boolean matches both its string and typed form.
+func attributeFilterQueries(column string, filters map[string]string, args map[string]any) []string {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	queries := []string{}
+	for i, key := range keys {
+		value := filters[key]
+		argName := fmt.Sprintf("attr_%d", i)
+
+		asString, _ := json.Marshal(map[string]any{key: value})
+		args[argName] = string(asString)
+
+		var typed any
+		if json.Unmarshal([]byte(value), &typed) == nil {
+			switch typed.(type) {
+			case float64, bool:
+				asTyped, _ := json.Marshal(map[string]any{key: typed})
+				args[argName+"_typed"] = string(asTyped)
+				queries = append(queries, fmt.Sprintf("(%s @> :%s OR %s @> :%s_typed)", column, argName, column, argName))
+				continue
+			}
+		}
+
+		queries = append(queries, fmt.Sprintf("%s @> :%s", column, argName))
+	}
+
+	return queries
+}
+
+// labelSelectorQueries translates label selector requirements into conditions on a jsonb column.
+// As in Kubernetes, != and notin also match rows that do not have the label at all.
+func labelSelectorQueries(column string, requirements []entities.LabelRequirement, args map[string]any) []string {
+	queries := []string{}
+
+	for i, req := range requirements {
+		argName := fmt.Sprintf("selector_%d", i)
+
+		switch req.Operator {
+		case entities.SELECTOR_OPERATOR_EQUALS, entities.SELECTOR_OPERATOR_NOT_EQUALS:
+			label, _ := json.Marshal(map[string]string{req.Key: req.Values[0]})
+			args[argName] = string(label)
+
+			query := fmt.Sprintf("%s @> :%s", column, argName)
+			if req.Operator == entities.SELECTOR_OPERATOR_NOT_EQUALS {
+				query = fmt.Sprintf("NOT (%s)", query)
+			}
+			queries = append(queries, query)
+
+		case entities.SELECTOR_OPERATOR_IN, entities.SELECTOR_OPERATOR_NOT_IN:
+			args[argName] = req.Key
+
+			valueArgs := []string{}
+			for j, v := range req.Values {
+				valueArg := fmt.Sprintf("%s_%d", argName, j)
+				args[valueArg] = v
+				valueArgs = append(valueArgs, ":"+valueArg)
+			}
+
+			query := fmt.Sprintf("%s ->> :%s IN (%s)", column, argName, strings.Join(valueArgs, ", "))
+			if req.Operator == entities.SELECTOR_OPERATOR_NOT_IN {
+				query = fmt.Sprintf("NOT COALESCE(%s, FALSE)", query)
+			}
+			queries = append(queries, query)
+
+		case entities.SELECTOR_OPERATOR_EXISTS:
+			args[argName] = req.Key
+			queries = append(queries, fmt.Sprintf("%s ? :%s", column, argName))
+
+		case entities.SELECTOR_OPERATOR_NOT_EXISTS:
+			args[argName] = req.Key
+			queries = append(queries, fmt.Sprintf("NOT (%s ? :%s)", column, argName))
+		}
+	}
+
+	return queries
+}
+
+// locationFilterQueries restricts a zone_id column to the zones below the given organization, site or zone.
+func locationFilterQueries(zoneColumn string, location entities.LocationFilter, args map[string]any) []string {
+	queries := []string{}
+
+	if location.ZoneID != "" {
+		args["zone_id"] = location.ZoneID
+		queries = append(queries, fmt.Sprintf("%s = :zone_id", zoneColumn))
+	}
+	if location.SiteID != "" {
+		args["site_id"] = location.SiteID
+		queries = append(queries, fmt.Sprintf("%s IN (SELECT id FROM zones WHERE site_id = :site_id)", zoneColumn))
+	}
+	if location.OrganizationID != "" {
+		args["organization_id"] = location.OrganizationID
+		queries = append(queries, fmt.Sprintf(`%s IN (SELECT z.id FROM zones z
+			JOIN sites s ON s.id = z.site_id WHERE s.organization_id = :organization_id)`, zoneColumn))
+	}
+
+	return queries
+}
+
+// geoDistanceQuery is the haversine distance in meters between the columns and the :near_lat/:near_lng args.
+func geoDistanceQuery(latColumn, lngColumn string) string {
+	return fmt.Sprintf(`(6371000 * 2 * ASIN(SQRT(
+		POWER(SIN(RADIANS(%[1]s - :near_lat) / 2), 2) +
+		COS(RADIANS(:near_lat)) * COS(RADIANS(%[1]s)) * POWER(SIN(RADIANS(%[2]s - :near_lng) / 2), 2))))`, latColumn, lngColumn)
+}
+
+// geoFilterQueries restricts latitude/longitude columns to a bounding box and/or a radius around a point.
+// Rows without a location never match a geo filter.
+func geoFilterQueries(latColumn, lngColumn string, geo entities.GeoFilter, args map[string]any) []string {
+	queries := []string{}
+
+	if geo.Near != nil {
+		args["near_lat"] = geo.Near.Latitude
+		args["near_lng"] = geo.Near.Longitude
+	}
+
+	if geo.Near != nil && geo.Radius > 0 {
+		// a latitude band around the point lets the index discard most rows before the distance is computed
+		band := geo.Radius / 111320
+		args["near_min_lat"] = geo.Near.Latitude - band
+		args["near_max_lat"] = geo.Near.Latitude + band
+		args["near_radius"] = geo.Radius
+		queries = append(queries,
+			fmt.Sprintf("%s BETWEEN :near_min_lat AND :near_max_lat", latColumn),
+			fmt.Sprintf("%s <= :near_radius", geoDistanceQuery(latColumn, lngColumn)),
+		)
+	}
+
+	if box := geo.BoundingBox; box != nil {
+		args["bbox_min_lat"] = box.MinLatitude
+		args["bbox_max_lat"] = box.MaxLatitude
+		args["bbox_min_lng"] = box.MinLongitude
+		args["bbox_max_lng"] = box.MaxLongitude
+		queries = append(queries, fmt.Sprintf("%s BETWEEN :bbox_min_lat AND :bbox_max_lat", latColumn))
+
+		if box.MinLongitude <= box.MaxLongitude {
+			queries = append(queries, fmt.Sprintf("%s BETWEEN :bbox_min_lng AND :bbox_max_lng", lngColumn))
+		} else {
+			queries = append(queries, fmt.Sprintf("(%[1]s >= :bbox_min_lng OR %[1]s <= :bbox_max_lng)", lngColumn))
+		}
+	}
+
+	return queries
+}
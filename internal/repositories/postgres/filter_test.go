@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestLabelSelectorQueries(t *testing.T) {
+	tests := []struct {
+		selector string
+		queries  []string
+		args     map[string]any
+	}{
+		{
+			selector: "site=north",
+			queries:  []string{"d.labels @> :selector_0"},
+			args:     map[string]any{"selector_0": `{"site":"north"}`},
+		},
+		{
+			selector: "env!=test",
+			queries:  []string{"NOT (d.labels @> :selector_0)"},
+			args:     map[string]any{"selector_0": `{"env":"test"}`},
+		},
+		{
+			selector: "tier in (a,b)",
+			queries:  []string{"d.labels ->> :selector_0 IN (:selector_0_0, :selector_0_1)"},
+			args:     map[string]any{"selector_0": "tier", "selector_0_0": "a", "selector_0_1": "b"},
+		},
+		{
+			selector: "tier notin (a)",
+			queries:  []string{"NOT COALESCE(d.labels ->> :selector_0 IN (:selector_0_0), FALSE)"},
+			args:     map[string]any{"selector_0": "tier", "selector_0_0": "a"},
+		},
+		{
+			selector: "legacy",
+			queries:  []string{"d.labels ? :selector_0"},
+			args:     map[string]any{"selector_0": "legacy"},
+		},
+		{
+			selector: "!legacy",
+			queries:  []string{"NOT (d.labels ? :selector_0)"},
+			args:     map[string]any{"selector_0": "legacy"},
+		},
+		{
+			// values are passed as args and escaped as JSON, never spliced into the query
+			selector: `example.com/team=o.p_s-1`,
+			queries:  []string{"d.labels @> :selector_0"},
+			args:     map[string]any{"selector_0": `{"example.com/team":"o.p_s-1"}`},
+		},
+		{
+			selector: "site=north,env!=test,tier in (a,b),!legacy",
+			queries: []string{
+				"d.labels @> :selector_0",
+				"NOT (d.labels @> :selector_1)",
+				"d.labels ->> :selector_2 IN (:selector_2_0, :selector_2_1)",
+				"NOT (d.labels ? :selector_3)",
+			},
+			args: map[string]any{
+				"selector_0":   `{"site":"north"}`,
+				"selector_1":   `{"env":"test"}`,
+				"selector_2":   "tier",
+				"selector_2_0": "a",
+				"selector_2_1": "b",
+				"selector_3":   "legacy",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			requirements, errs := util.ParseLabelSelector(tt.selector)
+			if len(errs) > 0 {
+				t.Fatalf("ParseLabelSelector() errors = %v", errs)
+			}
+
+			args := map[string]any{}
+			queries := labelSelectorQueries("d.labels", requirements, args)
+			if !reflect.DeepEqual(queries, tt.queries) {
+				t.Errorf("labelSelectorQueries() = %q, want %q", queries, tt.queries)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+
+	if queries := labelSelectorQueries("d.labels", []entities.LabelRequirement{}, map[string]any{}); len(queries) != 0 {
+		t.Errorf("labelSelectorQueries() of no requirements = %q, want none", queries)
+	}
+}
+
+// TestLabelSelectorQueriesBind checks the jsonb ? operator is kept when the named args are bound as $N,
+// as PrepareNamed does for the list queries.
+func TestLabelSelectorQueriesBind(t *testing.T) {
+	requirements, _ := util.ParseLabelSelector("legacy,!retired,tier notin (a,b)")
+
+	args := map[string]any{}
+	query := strings.Join(labelSelectorQueries("labels", requirements, args), " AND ")
+
+	bound, boundArgs, err := sqlx.BindNamed(sqlx.DOLLAR, query, args)
+	if err != nil {
+		t.Fatalf("BindNamed() error = %v", err)
+	}
+
+	want := "labels ? $1 AND NOT (labels ? $2) AND NOT COALESCE(labels ->> $3 IN ($4, $5), FALSE)"
+	if bound != want {
+		t.Errorf("query = %q, want %q", bound, want)
+	}
+	if wantArgs := []any{"legacy", "retired", "tier", "a", "b"}; !reflect.DeepEqual(boundArgs, wantArgs) {
+		t.Errorf("args = %v, want %v", boundArgs, wantArgs)
+	}
+}
@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const deviceGroupColumns = `id, name, description, type, filter, created_at, updated_at`
+
+type DeviceGroup struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Type        string    `db:"type"`
+	Filter      []byte    `db:"filter"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (g *DeviceGroup) ToEntity() *entities.DeviceGroup {
+	group := &entities.DeviceGroup{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Type:        entities.DeviceGroupType(g.Type),
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+
+	if len(g.Filter) > 0 {
+		var filter entities.DeviceGroupFilter
+		if err := json.Unmarshal(g.Filter, &filter); err == nil {
+			group.Filter = &filter
+		}
+	}
+
+	return group
+}
+
+// groupFilter returns the saved filter of a dynamic group as a jsonb value, or NULL for static groups.
+func groupFilter(payload entities.DeviceGroup) (any, error) {
+	if payload.Type != entities.DEVICE_GROUP_TYPE_DYNAMIC || payload.Filter == nil {
+		return nil, nil
+	}
+
+	filter, err := json.Marshal(payload.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (r *repository) CreateDeviceGroup(ctx context.Context, payload entities.DeviceGroup) (string, error) {
+	var groupID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	filter, err := groupFilter(payload)
+	if err != nil {
+		return groupID, util.NewErrInvalidRequest("invalid device group filter")
+	}
+
+	query := `INSERT INTO device_groups
+		(name, description, type, filter, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	err = r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		payload.Type,
+		filter,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&groupID)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateDeviceGroup",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return groupID, util.NewErrInternalServer("failed to create device group")
+	}
+
+	return groupID, nil
+}
+
+func (r *repository) UpdateDeviceGroup(ctx context.Context, groupID string, payload entities.DeviceGroup) error {
+	filter, err := groupFilter(payload)
+	if err != nil {
+		return util.NewErrInvalidRequest("invalid device group filter")
+	}
+
+	query := `UPDATE device_groups
+		SET name = $1, description = $2, type = $3, filter = $4, updated_at = $5
+		WHERE id = $6`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		payload.Type,
+		filter,
+		time.Now().UTC(),
+		groupID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateDeviceGroup",
+			slog.Any("err", err),
+			slog.Any("groupID", groupID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update device group")
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteDeviceGroup(ctx context.Context, groupID string) error {
+	query := `DELETE FROM device_groups WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, groupID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteDeviceGroup",
+			slog.Any("err", err),
+			slog.Any("groupID", groupID),
+		)
+		return util.NewErrInternalServer("failed to delete device group")
+	}
+
+	return nil
+}
+
+func (r *repository) GetDeviceGroup(ctx context.Context, groupID string) (*entities.DeviceGroup, error) {
+	var model DeviceGroup
+
+	query := fmt.Sprintf(`SELECT %s FROM device_groups WHERE id = $1`, deviceGroupColumns)
+	err := r.db.GetContext(ctx, &model, query, groupID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("device group not found")
+		}
+
+		slog.Error(
+			"Failed to GetDeviceGroup",
+			slog.Any("err", err),
+			slog.Any("groupID", groupID),
+		)
+		return nil, util.NewErrInternalServer("failed to get device group")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetDeviceGroupList(ctx context.Context, params entities.GetDeviceGroupListParams) ([]*entities.DeviceGroup, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM device_groups"
+	queryData := fmt.Sprintf("SELECT %s FROM device_groups", deviceGroupColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceGroupList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device group list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceGroupList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device group list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceGroupList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device group list")
+	}
+	defer stmtData.Close()
+
+	var model []DeviceGroup
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceGroupList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device group list")
+	}
+
+	groups := []*entities.DeviceGroup{}
+	for _, v := range model {
+		groups = append(groups, v.ToEntity())
+	}
+
+	return groups, total, nil
+}
+
+// AddDeviceGroupMembers adds existing devices to a static group, unknown device IDs are ignored.
+func (r *repository) AddDeviceGroupMembers(ctx context.Context, groupID string, deviceIDs []string) error {
+	query := `INSERT INTO device_group_members (group_id, device_id, created_at)
+		SELECT $1, id, $2 FROM devices WHERE id = ANY($3) AND deleted_at IS NULL
+		ON CONFLICT (group_id, device_id) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, groupID, time.Now().UTC(), pq.Array(deviceIDs))
+	if err != nil {
+		slog.Error(
+			"Failed to AddDeviceGroupMembers",
+			slog.Any("err", err),
+			slog.Any("groupID", groupID),
+			slog.Any("deviceIDs", deviceIDs),
+		)
+		return util.NewErrInternalServer("failed to add device group members")
+	}
+
+	return nil
+}
+
+func (r *repository) RemoveDeviceGroupMember(ctx context.Context, groupID, deviceID string) error {
+	query := `DELETE FROM device_group_members WHERE group_id = $1 AND device_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, groupID, deviceID)
+	if err != nil {
+		slog.Error(
+			"Failed to RemoveDeviceGroupMember",
+			slog.Any("err", err),
+			slog.Any("groupID", groupID),
+			slog.Any("deviceID", deviceID),
+		)
+		return util.NewErrInternalServer("failed to remove device group member")
+	}
+
+	return nil
+}
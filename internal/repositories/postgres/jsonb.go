@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// JSONB maps a jsonb object column to a Go map.
+type JSONB map[string]any
+
+func (j JSONB) Value() (driver.Value, error) {
+	if j == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(j)
+}
+
+func (j *JSONB) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		*j = JSONB{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for JSONB")
+	}
+
+	result := JSONB{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*j = result
+
+	return nil
+}
+
+// StringJSONB maps a jsonb object column with string values, such as labels, to a Go map.
+type StringJSONB map[string]string
+
+func (j StringJSONB) Value() (driver.Value, error) {
+	if j == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(j)
+}
+
+func (j *StringJSONB) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		*j = StringJSONB{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for StringJSONB")
+	}
+
+	result := StringJSONB{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*j = result
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const maintenanceWindowColumns = `id, device_id, group_id, name, description, starts_at, ends_at,
+	recurrence, recurrence_until, created_at, updated_at`
+
+type MaintenanceWindow struct {
+	ID              string     `db:"id"`
+	DeviceID        *string    `db:"device_id"`
+	GroupID         *string    `db:"group_id"`
+	Name            string     `db:"name"`
+	Description     string     `db:"description"`
+	StartsAt        time.Time  `db:"starts_at"`
+	EndsAt          time.Time  `db:"ends_at"`
+	Recurrence      string     `db:"recurrence"`
+	RecurrenceUntil *time.Time `db:"recurrence_until"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+}
+
+func (m *MaintenanceWindow) ToEntity() *entities.MaintenanceWindow {
+	window := &entities.MaintenanceWindow{
+		ID:              m.ID,
+		DeviceID:        m.DeviceID,
+		GroupID:         m.GroupID,
+		Name:            m.Name,
+		Description:     m.Description,
+		StartsAt:        m.StartsAt,
+		EndsAt:          m.EndsAt,
+		Recurrence:      entities.MaintenanceRecurrence(m.Recurrence),
+		RecurrenceUntil: m.RecurrenceUntil,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+	window.Active = window.ActiveAt(time.Now())
+
+	return window
+}
+
+func (r *repository) CreateMaintenanceWindow(ctx context.Context, payload entities.MaintenanceWindow) (string, error) {
+	var windowID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO maintenance_windows
+		(device_id, group_id, name, description, starts_at, ends_at, recurrence, recurrence_until, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.DeviceID,
+		payload.GroupID,
+		payload.Name,
+		payload.Description,
+		payload.StartsAt,
+		payload.EndsAt,
+		payload.Recurrence,
+		payload.RecurrenceUntil,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&windowID)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateMaintenanceWindow",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return windowID, util.NewErrInternalServer("failed to create maintenance window")
+	}
+
+	return windowID, nil
+}
+
+func (r *repository) UpdateMaintenanceWindow(ctx context.Context, windowID string, payload entities.MaintenanceWindow) error {
+	query := `UPDATE maintenance_windows
+		SET device_id = $1, group_id = $2, name = $3, description = $4, starts_at = $5, ends_at = $6,
+			recurrence = $7, recurrence_until = $8, updated_at = $9
+		WHERE id = $10`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.DeviceID,
+		payload.GroupID,
+		payload.Name,
+		payload.Description,
+		payload.StartsAt,
+		payload.EndsAt,
+		payload.Recurrence,
+		payload.RecurrenceUntil,
+		time.Now().UTC(),
+		windowID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateMaintenanceWindow",
+			slog.Any("err", err),
+			slog.Any("windowID", windowID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update maintenance window")
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteMaintenanceWindow(ctx context.Context, windowID string) error {
+	query := `DELETE FROM maintenance_windows WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, windowID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteMaintenanceWindow",
+			slog.Any("err", err),
+			slog.Any("windowID", windowID),
+		)
+		return util.NewErrInternalServer("failed to delete maintenance window")
+	}
+
+	return nil
+}
+
+func (r *repository) GetMaintenanceWindow(ctx context.Context, windowID string) (*entities.MaintenanceWindow, error) {
+	var model MaintenanceWindow
+
+	query := fmt.Sprintf(`SELECT %s FROM maintenance_windows WHERE id = $1`, maintenanceWindowColumns)
+	err := r.db.GetContext(ctx, &model, query, windowID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("maintenance window not found")
+		}
+
+		slog.Error(
+			"Failed to GetMaintenanceWindow",
+			slog.Any("err", err),
+			slog.Any("windowID", windowID),
+		)
+		return nil, util.NewErrInternalServer("failed to get maintenance window")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetMaintenanceWindowList(ctx context.Context, params entities.GetMaintenanceWindowListParams) ([]*entities.MaintenanceWindow, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "starts_at", "ends_at", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM maintenance_windows"
+	queryData := fmt.Sprintf("SELECT %s FROM maintenance_windows", maintenanceWindowColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.DeviceID != "" {
+		args["device_id"] = params.DeviceID
+		whereQueries = append(whereQueries, "device_id = :device_id")
+	}
+	if params.GroupID != "" {
+		args["group_id"] = params.GroupID
+		whereQueries = append(whereQueries, "group_id = :group_id")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetMaintenanceWindowList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get maintenance window list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetMaintenanceWindowList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get maintenance window list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetMaintenanceWindowList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get maintenance window list")
+	}
+	defer stmtData.Close()
+
+	var model []MaintenanceWindow
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetMaintenanceWindowList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get maintenance window list")
+	}
+
+	windows := []*entities.MaintenanceWindow{}
+	for _, v := range model {
+		windows = append(windows, v.ToEntity())
+	}
+
+	return windows, total, nil
+}
+
+// GetDeviceMaintenanceWindows returns the windows of the device itself and of every group it belongs to,
+// static groups through their members and dynamic groups through their saved filter.
+func (r *repository) GetDeviceMaintenanceWindows(ctx context.Context, deviceID string) ([]*entities.MaintenanceWindow, error) {
+	query := fmt.Sprintf(`SELECT %s, (SELECT g.type FROM device_groups g WHERE g.id = w.group_id) AS group_type,
+			(SELECT g.filter FROM device_groups g WHERE g.id = w.group_id) AS group_filter
+		FROM maintenance_windows w
+		WHERE w.device_id = $1
+			OR w.group_id IN (SELECT group_id FROM device_group_members WHERE device_id = $1)
+			OR w.group_id IN (SELECT id FROM device_groups WHERE type = $2)
+		ORDER BY w.starts_at`, maintenanceWindowColumns)
+
+	var model []struct {
+		MaintenanceWindow
+		GroupType   *string `db:"group_type"`
+		GroupFilter []byte  `db:"group_filter"`
+	}
+	err := r.db.SelectContext(ctx, &model, query, deviceID, entities.DEVICE_GROUP_TYPE_DYNAMIC)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceMaintenanceWindows",
+			slog.Any("err", err),
+			slog.Any("deviceID", deviceID),
+		)
+		return nil, util.NewErrInternalServer("failed to get device maintenance windows")
+	}
+
+	// the windows of static groups are already limited to the groups of the device
+	dynamicGroups := map[string]*entities.DeviceGroup{}
+	for _, v := range model {
+		if v.GroupID != nil && v.GroupType != nil && entities.DeviceGroupType(*v.GroupType) == entities.DEVICE_GROUP_TYPE_DYNAMIC {
+			group := DeviceGroup{ID: *v.GroupID, Type: *v.GroupType, Filter: v.GroupFilter}
+			dynamicGroups[*v.GroupID] = group.ToEntity()
+		}
+	}
+
+	members, err := r.getDynamicGroupMemberships(ctx, dynamicGroups, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := []*entities.MaintenanceWindow{}
+	for _, v := range model {
+		if v.GroupID != nil && dynamicGroups[*v.GroupID] != nil && !members[*v.GroupID] {
+			continue
+		}
+		windows = append(windows, v.ToEntity())
+	}
+
+	return windows, nil
+}
+
+// namedArgPattern matches the named arguments of a query.
+var namedArgPattern = regexp.MustCompile(`:\w+`)
+
+// dynamicGroupMembershipQuery builds a query returning the IDs of the dynamic groups whose filter matches the
+// device. The arguments of every group's filter are prefixed, so the filters do not share argument names.
+func dynamicGroupMembershipQuery(groups map[string]*entities.DeviceGroup, deviceID string) (string, map[string]any) {
+	groupIDs := make([]string, 0, len(groups))
+	for id := range groups {
+		groupIDs = append(groupIDs, id)
+	}
+	sort.Strings(groupIDs)
+
+	args := map[string]any{"member_device_id": deviceID}
+	queries := []string{}
+	for i, id := range groupIDs {
+		prefix := fmt.Sprintf("group_%d_", i)
+
+		groupArgs := map[string]any{}
+		where := strings.Join(deviceFilterQueries(util.DeviceGroupListParams(groups[id]), groupArgs), " AND ")
+		where = namedArgPattern.ReplaceAllStringFunc(where, func(arg string) string {
+			if _, ok := groupArgs[arg[1:]]; !ok {
+				return arg
+			}
+			return ":" + prefix + arg[1:]
+		})
+		for k, v := range groupArgs {
+			args[prefix+k] = v
+		}
+		args[prefix+"id"] = id
+
+		queries = append(queries, fmt.Sprintf("SELECT CAST(:%sid AS text) FROM devices WHERE %s AND id = :member_device_id", prefix, where))
+	}
+
+	return strings.Join(queries, " UNION ALL "), args
+}
+
+// getDynamicGroupMemberships reports which of the dynamic groups have the device among their members, checking
+// the filters of all groups in one query.
+func (r *repository) getDynamicGroupMemberships(ctx context.Context, groups map[string]*entities.DeviceGroup, deviceID string) (map[string]bool, error) {
+	members := map[string]bool{}
+	if len(groups) == 0 {
+		return members, nil
+	}
+
+	query, args := dynamicGroupMembershipQuery(groups, deviceID)
+
+	stmt, err := r.db.PrepareNamed(query)
+	if err != nil {
+		slog.Error(
+			"Failed to getDynamicGroupMemberships PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("deviceID", deviceID),
+		)
+		return nil, util.NewErrInternalServer("failed to get device group members")
+	}
+	defer stmt.Close()
+
+	var memberIDs []string
+	err = stmt.SelectContext(ctx, &memberIDs, args)
+	if err != nil {
+		slog.Error(
+			"Failed to getDynamicGroupMemberships SelectContext",
+			slog.Any("err", err),
+			slog.Any("deviceID", deviceID),
+		)
+		return nil, util.NewErrInternalServer("failed to get device group members")
+	}
+
+	for _, id := range memberIDs {
+		members[id] = true
+	}
+
+	return members, nil
+}
+
+// IsDeviceInMaintenance reports whether any maintenance window of the device is active at the given time.
+func (r *repository) IsDeviceInMaintenance(ctx context.Context, deviceID string, at time.Time) (bool, error) {
+	windows, err := r.GetDeviceMaintenanceWindows(ctx, deviceID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, window := range windows {
+		if window.ActiveAt(at) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
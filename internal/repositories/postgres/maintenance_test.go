@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"go-api/internal/entities"
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TestDynamicGroupMembershipQuery checks every group's filter is bound to its own args, though the filters of
+// different groups use the same arg names.
+func TestDynamicGroupMembershipQuery(t *testing.T) {
+	groups := map[string]*entities.DeviceGroup{
+		"b": {ID: "b", Type: entities.DEVICE_GROUP_TYPE_DYNAMIC, Filter: &entities.DeviceGroupFilter{Search: "pump", Selector: "legacy"}},
+		"a": {ID: "a", Type: entities.DEVICE_GROUP_TYPE_DYNAMIC, Filter: &entities.DeviceGroupFilter{Search: "valve"}},
+	}
+
+	query, args := dynamicGroupMembershipQuery(groups, "device")
+
+	bound, boundArgs, err := sqlx.BindNamed(sqlx.DOLLAR, query, args)
+	if err != nil {
+		t.Fatalf("BindNamed() error = %v", err)
+	}
+
+	want := "SELECT CAST($1 AS text) FROM devices WHERE deleted_at IS NULL AND (name LIKE $2 OR description LIKE $3) AND id = $4" +
+		" UNION ALL " +
+		"SELECT CAST($5 AS text) FROM devices WHERE deleted_at IS NULL AND (name LIKE $6 OR description LIKE $7) AND labels ? $8 AND id = $9"
+	if bound != want {
+		t.Errorf("query = %q, want %q", bound, want)
+	}
+	wantArgs := []any{"a", "%valve%", "%valve%", "device", "b", "%pump%", "%pump%", "legacy", "device"}
+	if !reflect.DeepEqual(boundArgs, wantArgs) {
+		t.Errorf("args = %v, want %v", boundArgs, wantArgs)
+	}
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const organizationColumns = `id, name, description, created_at, updated_at`
+
+type Organization struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (o *Organization) ToEntity() *entities.Organization {
+	return &entities.Organization{
+		ID:          o.ID,
+		Name:        o.Name,
+		Description: o.Description,
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+	}
+}
+
+func (r *repository) CreateOrganization(ctx context.Context, payload entities.Organization) (string, error) {
+	var organizationID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO organizations
+		(name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&organizationID)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateOrganization",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return organizationID, util.NewErrInternalServer("failed to create organization")
+	}
+
+	return organizationID, nil
+}
+
+func (r *repository) UpdateOrganization(ctx context.Context, organizationID string, payload entities.Organization) error {
+	query := `UPDATE organizations
+		SET name = $1, description = $2, updated_at = $3
+		WHERE id = $4`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.Description,
+		time.Now().UTC(),
+		organizationID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateOrganization",
+			slog.Any("err", err),
+			slog.Any("organizationID", organizationID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update organization")
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteOrganization(ctx context.Context, organizationID string) error {
+	query := `DELETE FROM organizations WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, organizationID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return util.NewErrConflict("organization still has sites")
+		}
+
+		slog.Error(
+			"Failed to DeleteOrganization",
+			slog.Any("err", err),
+			slog.Any("organizationID", organizationID),
+		)
+		return util.NewErrInternalServer("failed to delete organization")
+	}
+
+	return nil
+}
+
+func (r *repository) GetOrganization(ctx context.Context, organizationID string) (*entities.Organization, error) {
+	var model Organization
+
+	query := fmt.Sprintf(`SELECT %s FROM organizations WHERE id = $1`, organizationColumns)
+	err := r.db.GetContext(ctx, &model, query, organizationID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("organization not found")
+		}
+
+		slog.Error(
+			"Failed to GetOrganization",
+			slog.Any("err", err),
+			slog.Any("organizationID", organizationID),
+		)
+		return nil, util.NewErrInternalServer("failed to get organization")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetOrganizationList(ctx context.Context, params entities.GetOrganizationListParams) ([]*entities.Organization, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM organizations"
+	queryData := fmt.Sprintf("SELECT %s FROM organizations", organizationColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetOrganizationList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get organization list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetOrganizationList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get organization list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetOrganizationList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get organization list")
+	}
+	defer stmtData.Close()
+
+	var model []Organization
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetOrganizationList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get organization list")
+	}
+
+	organizations := []*entities.Organization{}
+	for _, v := range model {
+		organizations = append(organizations, v.ToEntity())
+	}
+
+	return organizations, total, nil
+}
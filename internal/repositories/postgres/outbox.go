@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type OutboxEvent struct {
+	ID         int64     `db:"id"`
+	Type       string    `db:"type"`
+	ResourceID string    `db:"resource_id"`
+	Data       JSONB     `db:"data"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (o *OutboxEvent) ToEntity() *entities.OutboxEvent {
+	return &entities.OutboxEvent{
+		ID:         o.ID,
+		Type:       o.Type,
+		ResourceID: o.ResourceID,
+		Data:       o.Data,
+		CreatedAt:  o.CreatedAt,
+	}
+}
+
+// insertOutboxEvent stores an event in tx, so it is delivered exactly when the change causing it is committed.
+func insertOutboxEvent(ctx context.Context, tx *sqlx.Tx, eventType, resourceID string, data any, now time.Time) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		slog.Error(
+			"Failed to insertOutboxEvent Marshal",
+			slog.Any("err", err),
+			slog.String("eventType", eventType),
+		)
+		return util.NewErrInternalServer("failed to store event")
+	}
+
+	query := `INSERT INTO event_outbox (type, resource_id, data, created_at) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(ctx, query, eventType, resourceID, string(encoded), now)
+	if err != nil {
+		slog.Error(
+			"Failed to insertOutboxEvent",
+			slog.Any("err", err),
+			slog.String("eventType", eventType),
+			slog.String("resourceID", resourceID),
+		)
+		return util.NewErrInternalServer("failed to store event")
+	}
+
+	return nil
+}
+
+// CreateOutboxWebhookDeliveries takes up to limit events from the outbox, oldest first, and stores the deliveries
+// the deliveries function returns for them in the same transaction. Events taken by a concurrent call are
+// skipped. The number of events taken is returned.
+func (r *repository) CreateOutboxWebhookDeliveries(ctx context.Context, limit int, deliveries func(event *entities.OutboxEvent) []entities.WebhookDelivery) (int, error) {
+	var model []OutboxEvent
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		query := `SELECT id, type, resource_id, data, created_at FROM event_outbox
+			ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`
+
+		err := tx.SelectContext(ctx, &model, query, limit)
+		if err != nil {
+			slog.Error(
+				"Failed to CreateOutboxWebhookDeliveries Select",
+				slog.Any("err", err),
+			)
+			return util.NewErrInternalServer("failed to create webhook deliveries")
+		}
+
+		queryInsert := `INSERT INTO webhook_deliveries
+			(webhook_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $6)`
+
+		eventIDs := []int64{}
+		for _, v := range model {
+			eventIDs = append(eventIDs, v.ID)
+
+			for _, delivery := range deliveries(v.ToEntity()) {
+				_, err := tx.ExecContext(
+					ctx,
+					queryInsert,
+					delivery.WebhookID,
+					delivery.EventType,
+					JSONB(delivery.Payload),
+					entities.WEBHOOK_DELIVERY_STATUS_PENDING,
+					delivery.NextAttemptAt,
+					nowUTC,
+				)
+				if err != nil {
+					slog.Error(
+						"Failed to CreateOutboxWebhookDeliveries Insert",
+						slog.Any("err", err),
+						slog.Any("eventID", v.ID),
+						slog.Any("webhookID", delivery.WebhookID),
+					)
+					return util.NewErrInternalServer("failed to create webhook deliveries")
+				}
+			}
+		}
+
+		if len(eventIDs) == 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM event_outbox WHERE id = ANY($1)`, pq.Array(eventIDs))
+		if err != nil {
+			slog.Error(
+				"Failed to CreateOutboxWebhookDeliveries Delete",
+				slog.Any("err", err),
+			)
+			return util.NewErrInternalServer("failed to create webhook deliveries")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(model), nil
+}
@@ -1,9 +1,20 @@
 package postgres
 
 import (
+	"context"
+	"errors"
 	"go-api/internal/repositories"
+	"go-api/pkg/util"
+	"log/slog"
+	"slices"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
 )
 
 type repository struct {
@@ -15,3 +26,43 @@ func NewRepository(db *sqlx.DB) repositories.IRepository {
 		db: db,
 	}
 }
+
+// withTx runs fn inside a transaction, rolling back when fn returns an error.
+func (r *repository) withTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		slog.Error("Failed to BeginTxx", slog.Any("err", err))
+		return util.NewErrInternalServer("failed to start transaction")
+	}
+
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		slog.Error("Failed to Commit", slog.Any("err", err))
+		return util.NewErrInternalServer("failed to commit transaction")
+	}
+
+	return nil
+}
+
+// isForeignKeyViolation reports whether err is caused by a row that is still referenced.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation
+}
+
+// versionMatches reports whether version is one of versions, nil matching any version.
+func versionMatches(versions []int, version int) bool {
+	return versions == nil || slices.Contains(versions, version)
+}
+
+// isUniqueViolation reports whether err is caused by a duplicate value of a unique column.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
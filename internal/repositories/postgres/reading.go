@@ -0,0 +1,652 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/expr"
+	"go-api/pkg/util"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// sensorReadingColumns returns calibrated values, rawSensorReadingColumns the values as they were sent
+const (
+	sensorReadingColumns    = `id, sensor_id, device_id, COALESCE(calibrated_value, value) AS value, maintenance, recorded_at, created_at`
+	rawSensorReadingColumns = `id, sensor_id, device_id, value, maintenance, recorded_at, created_at`
+)
+
+type SensorReading struct {
+	ID          int64     `db:"id"`
+	SensorID    string    `db:"sensor_id"`
+	DeviceID    string    `db:"device_id"`
+	Value       float64   `db:"value"`
+	Maintenance bool      `db:"maintenance"`
+	RecordedAt  time.Time `db:"recorded_at"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (s *SensorReading) ToEntity() *entities.SensorReading {
+	return &entities.SensorReading{
+		ID:          s.ID,
+		SensorID:    s.SensorID,
+		DeviceID:    s.DeviceID,
+		Value:       s.Value,
+		Maintenance: s.Maintenance,
+		RecordedAt:  s.RecordedAt,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+type LatestSensorReading struct {
+	DeviceID   string    `db:"device_id"`
+	SensorID   string    `db:"sensor_id"`
+	SensorName string    `db:"sensor_name"`
+	SensorType string    `db:"sensor_type"`
+	Value      float64   `db:"value"`
+	RecordedAt time.Time `db:"recorded_at"`
+}
+
+func (l *LatestSensorReading) ToEntity() *entities.LatestSensorReading {
+	return &entities.LatestSensorReading{
+		DeviceID:   l.DeviceID,
+		SensorID:   l.SensorID,
+		SensorName: l.SensorName,
+		SensorType: entities.SensorType(l.SensorType),
+		Value:      l.Value,
+		RecordedAt: l.RecordedAt,
+	}
+}
+
+// CreateSensorReadings stores readings and, in the same transaction, the readings of the virtual sensors
+// that depend on them. Virtual readings computed before a reading was backfilled are computed again.
+// The stored readings are returned first, followed by the computed ones.
+func (r *repository) CreateSensorReadings(ctx context.Context, payload []entities.SensorReading) ([]*entities.SensorReading, error) {
+	readings := []*entities.SensorReading{}
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		insert := readingInserter(ctx, tx)
+
+		recorded := map[string][]time.Time{}
+		sent := map[string][]time.Time{}
+		for _, p := range payload {
+			model, err := insert(p)
+			if err != nil {
+				return err
+			}
+			readings = append(readings, model.ToEntity())
+			sent[p.SensorID] = append(sent[p.SensorID], p.RecordedAt)
+		}
+
+		// a backfilled reading changes the virtual readings computed since, up to the next reading of its sensor
+		for sensorID, times := range sent {
+			sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+			err := dependentRecordedTimes(ctx, tx, sensorID, times[0], &times[len(times)-1], recorded)
+			if err != nil {
+				return err
+			}
+		}
+
+		derived, err := deriveVirtualSensorReadings(ctx, tx, recorded, insert)
+		if err != nil {
+			return err
+		}
+		readings = append(readings, derived...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return readings, nil
+}
+
+// readingInserter returns a function inserting readings within tx, with the calibration of the
+// sensor in effect at recorded_at applied.
+func readingInserter(ctx context.Context, tx *sqlx.Tx) func(entities.SensorReading) (*SensorReading, error) {
+	nowUTC := time.Now().UTC()
+
+	// a reading belongs to the device the sensor was attached to when it was recorded
+	query := fmt.Sprintf(`INSERT INTO sensor_readings
+		(sensor_id, device_id, value, calibrated_value, maintenance, recorded_at, created_at)
+		VALUES ($1, COALESCE(
+			(SELECT device_id FROM sensor_device_history
+				WHERE sensor_id = $1 AND attached_at <= $5 ORDER BY attached_at DESC LIMIT 1),
+			(SELECT device_id FROM sensors WHERE id = $1)
+		), $2, $3, $4, $5, $6) RETURNING %s`, sensorReadingColumns)
+
+	calibrations := map[string][]*entities.SensorCalibration{}
+
+	return func(p entities.SensorReading) (*SensorReading, error) {
+		sensorCalibrationList, ok := calibrations[p.SensorID]
+		if !ok {
+			var err error
+			sensorCalibrationList, err = sensorCalibrations(ctx, tx, p.SensorID)
+			if err != nil {
+				return nil, err
+			}
+			calibrations[p.SensorID] = sensorCalibrationList
+		}
+
+		var calibratedValue *float64
+		if calibration := entities.CalibrationAt(sensorCalibrationList, p.RecordedAt); calibration != nil {
+			value := calibration.Apply(p.Value)
+			calibratedValue = &value
+		}
+
+		var model SensorReading
+		err := tx.GetContext(ctx, &model, query, p.SensorID, p.Value, calibratedValue, p.Maintenance, p.RecordedAt, nowUTC)
+		if err != nil {
+			slog.Error(
+				"Failed to CreateSensorReadings",
+				slog.Any("err", err),
+				slog.Any("payload", p),
+			)
+			return nil, util.NewErrInternalServer("failed to create sensor readings")
+		}
+
+		return &model, nil
+	}
+}
+
+type SensorValue struct {
+	SensorID    string  `db:"sensor_id"`
+	Value       float64 `db:"value"`
+	Maintenance bool    `db:"maintenance"`
+}
+
+// deriveVirtualSensorReadings computes a reading of every virtual sensor depending on the recorded readings,
+// at each recorded_at, from the latest reading at or before it of every referenced sensor. Times recorded for
+// a virtual sensor itself are computed again too. Virtual sensors referencing other virtual sensors are computed
+// after them. A reading replaces an earlier one at the same time, and is left out when a referenced sensor has no
+// reading yet or the expression has no finite result.
+func deriveVirtualSensorReadings(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	recorded map[string][]time.Time,
+	insert func(entities.SensorReading) (*SensorReading, error),
+) ([]*entities.SensorReading, error) {
+	readings := []*entities.SensorReading{}
+
+	model, err := virtualSensors(ctx, tx)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateSensorReadings virtual sensors",
+			slog.Any("err", err),
+		)
+		return nil, util.NewErrInternalServer("failed to create sensor readings")
+	}
+	if len(model) == 0 {
+		return readings, nil
+	}
+
+	deps := map[string][]string{}
+	expressions := map[string]*expr.Expr{}
+	for _, v := range model {
+		e, err := expr.Parse(v.Expression)
+		if err != nil {
+			continue
+		}
+		deps[v.ID] = v.Refs
+		expressions[v.ID] = e
+	}
+
+	changed := []string{}
+	for sensorID := range recorded {
+		changed = append(changed, sensorID)
+	}
+
+	queryValues := `SELECT DISTINCT ON (sensor_id)
+			sensor_id, COALESCE(calibrated_value, value) AS value, maintenance
+		FROM sensor_readings
+		WHERE sensor_id = ANY($1) AND recorded_at <= $2
+		ORDER BY sensor_id, recorded_at DESC`
+
+	queryDelete := `DELETE FROM sensor_readings WHERE sensor_id = $1 AND recorded_at = $2`
+
+	for _, sensorID := range expr.Order(changed, deps) {
+		for _, recordedAt := range recordedTimes(append([]string{sensorID}, deps[sensorID]...), recorded) {
+			var sensorValues []SensorValue
+			err := tx.SelectContext(ctx, &sensorValues, queryValues, pq.Array(deps[sensorID]), recordedAt)
+			if err != nil {
+				slog.Error(
+					"Failed to CreateSensorReadings virtual sensor values",
+					slog.Any("err", err),
+					slog.Any("sensorID", sensorID),
+				)
+				return nil, util.NewErrInternalServer("failed to create sensor readings")
+			}
+
+			values := map[string]float64{}
+			maintenance := false
+			for _, v := range sensorValues {
+				values[v.SensorID] = v.Value
+				maintenance = maintenance || v.Maintenance
+			}
+
+			_, err = tx.ExecContext(ctx, queryDelete, sensorID, recordedAt)
+			if err != nil {
+				slog.Error(
+					"Failed to CreateSensorReadings virtual sensor delete",
+					slog.Any("err", err),
+					slog.Any("sensorID", sensorID),
+				)
+				return nil, util.NewErrInternalServer("failed to create sensor readings")
+			}
+
+			value, err := expressions[sensorID].Eval(values)
+			if err != nil {
+				continue
+			}
+
+			reading, err := insert(entities.SensorReading{
+				SensorID:    sensorID,
+				Value:       value,
+				Maintenance: maintenance,
+				RecordedAt:  recordedAt,
+			})
+			if err != nil {
+				return nil, err
+			}
+			readings = append(readings, reading.ToEntity())
+			recorded[sensorID] = append(recorded[sensorID], recordedAt)
+		}
+	}
+
+	return readings, nil
+}
+
+// rederiveVirtualSensorReadings computes again the readings recorded at or after from of every virtual sensor
+// depending on the sensor, directly or through other virtual sensors, after the values of the sensor changed.
+func rederiveVirtualSensorReadings(ctx context.Context, tx *sqlx.Tx, sensorID string, from time.Time) error {
+	recorded := map[string][]time.Time{}
+	err := dependentRecordedTimes(ctx, tx, sensorID, from, nil, recorded)
+	if err != nil {
+		return err
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+
+	_, err = deriveVirtualSensorReadings(ctx, tx, recorded, readingInserter(ctx, tx))
+	return err
+}
+
+// dependentRecordedTimes adds to recorded the times of the readings of the sensor, and of every virtual sensor
+// depending on it, recorded at or after from. With until, only the readings before the first reading of the
+// sensor after until are added, later readings of virtual sensors do not use the readings up to until.
+func dependentRecordedTimes(ctx context.Context, tx *sqlx.Tx, sensorID string, from time.Time, until *time.Time, recorded map[string][]time.Time) error {
+	query := `WITH RECURSIVE affected AS (
+			SELECT $1::uuid AS id
+			UNION
+			SELECT s.id FROM sensors s JOIN affected a ON s.expression_refs @> ARRAY[a.id]
+			WHERE s.expression IS NOT NULL AND s.deleted_at IS NULL
+		)
+		SELECT DISTINCT sensor_id, recorded_at FROM sensor_readings
+		WHERE sensor_id IN (SELECT id FROM affected) AND recorded_at >= $2
+			AND ($3::timestamptz IS NULL OR recorded_at < COALESCE(
+				(SELECT MIN(recorded_at) FROM sensor_readings WHERE sensor_id = $1 AND recorded_at > $3),
+				'infinity'
+			))`
+
+	var model []struct {
+		SensorID   string    `db:"sensor_id"`
+		RecordedAt time.Time `db:"recorded_at"`
+	}
+	err := tx.SelectContext(ctx, &model, query, sensorID, from, until)
+	if err != nil {
+		slog.Error(
+			"Failed to dependentRecordedTimes",
+			slog.Any("err", err),
+			slog.Any("sensorID", sensorID),
+		)
+		return util.NewErrInternalServer("failed to compute virtual sensor readings")
+	}
+
+	for _, m := range model {
+		recorded[m.SensorID] = append(recorded[m.SensorID], m.RecordedAt)
+	}
+
+	return nil
+}
+
+// recordedTimes returns the distinct times readings of the given sensors were recorded at, in order.
+func recordedTimes(sensorIDs []string, recorded map[string][]time.Time) []time.Time {
+	times := []time.Time{}
+	seen := map[int64]bool{}
+	for _, sensorID := range sensorIDs {
+		for _, t := range recorded[sensorID] {
+			if !seen[t.UnixNano()] {
+				seen[t.UnixNano()] = true
+				times = append(times, t)
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	return times
+}
+
+func (r *repository) GetSensorReadingList(ctx context.Context, params entities.GetSensorReadingListParams) ([]*entities.SensorReading, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"recorded_at", "value"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	// readings are ordered by time by default, not by insertion
+	if orderBy == "created_at DESC" {
+		orderBy = "recorded_at DESC"
+	}
+
+	columns := sensorReadingColumns
+	if params.Raw {
+		columns = rawSensorReadingColumns
+	}
+
+	queryCount := "SELECT COUNT(id) FROM sensor_readings"
+	queryData := fmt.Sprintf("SELECT %s FROM sensor_readings", columns)
+
+	args := map[string]any{}
+	// readings of a device leave out its deleted sensors
+	whereQueries := []string{"sensor_id NOT IN (SELECT id FROM sensors WHERE deleted_at IS NOT NULL)"}
+	if params.SensorID != "" {
+		args["sensor_id"] = params.SensorID
+		whereQueries = append(whereQueries, "sensor_id = :sensor_id")
+	}
+	if params.DeviceID != "" {
+		args["device_id"] = params.DeviceID
+		whereQueries = append(whereQueries, "device_id = :device_id")
+	}
+	if params.From != nil {
+		args["from"] = *params.From
+		whereQueries = append(whereQueries, "recorded_at >= :from")
+	}
+	if params.To != nil {
+		args["to"] = *params.To
+		whereQueries = append(whereQueries, "recorded_at < :to")
+	}
+	if params.ExcludeMaintenance {
+		whereQueries = append(whereQueries, "NOT maintenance")
+	}
+
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorReadingList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor reading list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorReadingList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor reading list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorReadingList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor reading list")
+	}
+	defer stmtData.Close()
+
+	var model []SensorReading
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorReadingList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get sensor reading list")
+	}
+
+	readings := []*entities.SensorReading{}
+	for _, v := range model {
+		readings = append(readings, v.ToEntity())
+	}
+
+	return readings, total, nil
+}
+
+// GetLatestSensorReadings returns the most recent reading of every sensor recorded on the given devices,
+// readings of a moved sensor stay with the device it was attached to.
+func (r *repository) GetLatestSensorReadings(ctx context.Context, deviceIDs []string) ([]*entities.LatestSensorReading, error) {
+	query := `SELECT DISTINCT ON (r.sensor_id)
+			r.device_id, r.sensor_id, s.name AS sensor_name, s.type AS sensor_type,
+			COALESCE(r.calibrated_value, r.value) AS value, r.recorded_at
+		FROM sensor_readings r
+		JOIN sensors s ON s.id = r.sensor_id
+		WHERE r.device_id = ANY($1) AND s.deleted_at IS NULL
+		ORDER BY r.sensor_id, r.recorded_at DESC`
+
+	var model []LatestSensorReading
+	err := r.db.SelectContext(ctx, &model, query, pq.Array(deviceIDs))
+	if err != nil {
+		slog.Error(
+			"Failed to GetLatestSensorReadings",
+			slog.Any("err", err),
+			slog.Any("deviceIDs", deviceIDs),
+		)
+		return nil, util.NewErrInternalServer("failed to get latest sensor readings")
+	}
+
+	readings := []*entities.LatestSensorReading{}
+	for _, v := range model {
+		readings = append(readings, v.ToEntity())
+	}
+
+	return readings, nil
+}
+
+type ReadingAggregate struct {
+	LocationID   string  `db:"location_id"`
+	LocationName string  `db:"location_name"`
+	SensorType   string  `db:"sensor_type"`
+	Count        int64   `db:"count"`
+	Min          float64 `db:"min"`
+	Max          float64 `db:"max"`
+	Avg          float64 `db:"avg"`
+}
+
+func (a *ReadingAggregate) ToEntity() *entities.ReadingAggregate {
+	return &entities.ReadingAggregate{
+		LocationID:   a.LocationID,
+		LocationName: a.LocationName,
+		SensorType:   entities.SensorType(a.SensorType),
+		Count:        a.Count,
+		Min:          a.Min,
+		Max:          a.Max,
+		Avg:          a.Avg,
+	}
+}
+
+// GetReadingAggregates rolls readings up by zone, site or organization, per sensor type.
+// Readings of devices without a zone are not part of any location.
+func (r *repository) GetReadingAggregates(ctx context.Context, params entities.GetReadingAggregateParams) ([]*entities.ReadingAggregate, error) {
+	location := "z"
+	switch params.GroupBy {
+	case entities.AGGREGATE_GROUP_BY_SITE:
+		location = "si"
+	case entities.AGGREGATE_GROUP_BY_ORGANIZATION:
+		location = "o"
+	}
+
+	value := "COALESCE(r.calibrated_value, r.value)"
+	if params.Raw {
+		value = "r.value"
+	}
+
+	query := fmt.Sprintf(`SELECT %[1]s.id AS location_id, %[1]s.name AS location_name, s.type AS sensor_type,
+			COUNT(r.id) AS count, MIN(%[2]s) AS min, MAX(%[2]s) AS max, AVG(%[2]s) AS avg
+		FROM sensor_readings r
+		JOIN sensors s ON s.id = r.sensor_id
+		JOIN devices d ON d.id = r.device_id
+		JOIN zones z ON z.id = d.zone_id
+		JOIN sites si ON si.id = z.site_id
+		JOIN organizations o ON o.id = si.organization_id`, location, value)
+
+	args := map[string]any{}
+	whereQueries := append([]string{"s.deleted_at IS NULL", "d.deleted_at IS NULL"}, locationFilterQueries("d.zone_id", params.Location, args)...)
+	if params.SensorType != "" {
+		args["sensor_type"] = params.SensorType
+		whereQueries = append(whereQueries, "s.type = :sensor_type")
+	}
+	if params.From != nil {
+		args["from"] = *params.From
+		whereQueries = append(whereQueries, "r.recorded_at >= :from")
+	}
+	if params.To != nil {
+		args["to"] = *params.To
+		whereQueries = append(whereQueries, "r.recorded_at < :to")
+	}
+	if params.ExcludeMaintenance {
+		whereQueries = append(whereQueries, "NOT r.maintenance")
+	}
+	if len(whereQueries) > 0 {
+		query += fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+	}
+
+	query += fmt.Sprintf(" GROUP BY %[1]s.id, %[1]s.name, s.type ORDER BY %[1]s.name, s.type", location)
+
+	stmt, err := r.db.PrepareNamed(query)
+	if err != nil {
+		slog.Error(
+			"Failed to GetReadingAggregates PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, util.NewErrInternalServer("failed to get reading aggregates")
+	}
+	defer stmt.Close()
+
+	var model []ReadingAggregate
+	err = stmt.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetReadingAggregates SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, util.NewErrInternalServer("failed to get reading aggregates")
+	}
+
+	aggregates := []*entities.ReadingAggregate{}
+	for _, v := range model {
+		aggregates = append(aggregates, v.ToEntity())
+	}
+
+	return aggregates, nil
+}
+
+type ConditionValue struct {
+	SensorID   string    `db:"sensor_id"`
+	DeviceID   string    `db:"device_id"`
+	SensorName string    `db:"sensor_name"`
+	SensorType string    `db:"sensor_type"`
+	Value      float64   `db:"value"`
+	RecordedAt time.Time `db:"recorded_at"`
+}
+
+func (c *ConditionValue) ToEntity() *entities.ConditionValue {
+	return &entities.ConditionValue{
+		SensorID:   c.SensorID,
+		DeviceID:   c.DeviceID,
+		SensorName: c.SensorName,
+		SensorType: entities.SensorType(c.SensorType),
+		Value:      c.Value,
+		RecordedAt: c.RecordedAt,
+	}
+}
+
+const conditionValueQuery = `SELECT %s r.sensor_id, r.device_id, s.name AS sensor_name, s.type AS sensor_type,
+		COALESCE(r.calibrated_value, r.value) AS value, r.recorded_at
+	FROM sensor_readings r
+	JOIN sensors s ON s.id = r.sensor_id
+	WHERE (r.sensor_id = ANY($1) OR LOWER(s.name) = ANY($2) OR s.type = ANY($3)) AND NOT r.maintenance
+		AND s.deleted_at IS NULL`
+
+// GetConditionValues returns the latest reading recorded before the given time of every selected sensor,
+// readings recorded during maintenance are left out.
+func (r *repository) GetConditionValues(ctx context.Context, sensors entities.ConditionSensors, before time.Time) ([]*entities.ConditionValue, error) {
+	query := fmt.Sprintf(conditionValueQuery, "DISTINCT ON (r.sensor_id)") +
+		` AND r.recorded_at < $4 ORDER BY r.sensor_id, r.recorded_at DESC`
+
+	var model []ConditionValue
+	err := r.db.SelectContext(ctx, &model, query, pq.Array(sensors.IDs), pq.Array(sensors.Names), pq.Array(sensors.Types), before)
+	if err != nil {
+		slog.Error(
+			"Failed to GetConditionValues",
+			slog.Any("err", err),
+			slog.Any("sensors", sensors),
+		)
+		return nil, util.NewErrInternalServer("failed to get condition values")
+	}
+
+	values := []*entities.ConditionValue{}
+	for _, v := range model {
+		values = append(values, v.ToEntity())
+	}
+
+	return values, nil
+}
+
+// GetConditionValueHistory returns up to limit readings of the selected sensors recorded from from until to,
+// in recorded order. Readings recorded during maintenance are left out.
+func (r *repository) GetConditionValueHistory(ctx context.Context, sensors entities.ConditionSensors, from, to time.Time, limit int) ([]*entities.ConditionValue, error) {
+	query := fmt.Sprintf(conditionValueQuery, "") +
+		fmt.Sprintf(` AND r.recorded_at >= $4 AND r.recorded_at < $5 ORDER BY r.recorded_at, r.sensor_id LIMIT %d`, limit)
+
+	var model []ConditionValue
+	err := r.db.SelectContext(ctx, &model, query, pq.Array(sensors.IDs), pq.Array(sensors.Names), pq.Array(sensors.Types), from, to)
+	if err != nil {
+		slog.Error(
+			"Failed to GetConditionValueHistory",
+			slog.Any("err", err),
+			slog.Any("sensors", sensors),
+		)
+		return nil, util.NewErrInternalServer("failed to get condition values")
+	}
+
+	values := []*entities.ConditionValue{}
+	for _, v := range model {
+		values = append(values, v.ToEntity())
+	}
+
+	return values, nil
+}
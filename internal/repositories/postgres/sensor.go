@@ -6,20 +6,31 @@ import (
 	"errors"
 	"fmt"
 	"go-api/internal/entities"
+	"go-api/pkg/expr"
 	"go-api/pkg/util"
 	"log/slog"
 	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+const sensorColumns = `id, device_id, type, name, description, attributes, labels,
+	COALESCE(expression, '') AS expression, version, created_at, updated_at`
+
 type Sensor struct {
-	ID          string    `db:"id"`
-	DeviceID    string    `db:"device_id"`
-	Type        string    `db:"type"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID          string      `db:"id"`
+	DeviceID    string      `db:"device_id"`
+	Type        string      `db:"type"`
+	Name        string      `db:"name"`
+	Description string      `db:"description"`
+	Attributes  JSONB       `db:"attributes"`
+	Labels      StringJSONB `db:"labels"`
+	Expression  string      `db:"expression"`
+	Version     int         `db:"version"`
+	CreatedAt   time.Time   `db:"created_at"`
+	UpdatedAt   time.Time   `db:"updated_at"`
 }
 
 func (s *Sensor) ToEntity() *entities.Sensor {
@@ -29,6 +40,11 @@ func (s *Sensor) ToEntity() *entities.Sensor {
 		Type:        entities.SensorType(s.Type),
 		Name:        s.Name,
 		Description: s.Description,
+		Attributes:  s.Attributes,
+		Labels:      s.Labels,
+		Virtual:     s.Expression != "",
+		Expression:  s.Expression,
+		Version:     s.Version,
 		CreatedAt:   s.CreatedAt,
 		UpdatedAt:   s.UpdatedAt,
 	}
@@ -37,25 +53,90 @@ func (s *Sensor) ToEntity() *entities.Sensor {
 func (r *repository) CreateSensor(ctx context.Context, payload entities.Sensor) (string, error) {
 	var sensorID string
 
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		sensorID, err = insertSensor(ctx, tx, payload)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return sensorID, nil
+}
+
+// sensorExpression returns the expression of a virtual sensor and the sensor IDs it references,
+// the expression is NULL for physical sensors.
+func sensorExpression(expression string) (any, pq.StringArray, error) {
+	if expression == "" {
+		return nil, pq.StringArray{}, nil
+	}
+
+	e, err := expr.Parse(expression)
+	if err != nil {
+		return nil, nil, util.NewErrInvalidRequest(fmt.Sprintf("invalid sensor expression %s", err.Error()))
+	}
+
+	return expression, pq.StringArray(e.Refs()), nil
+}
+
+// insertSensor inserts a sensor and records its creation in the audit log of the transaction.
+func insertSensor(ctx context.Context, tx *sqlx.Tx, payload entities.Sensor) (string, error) {
+	var sensorID string
+
 	nowUTC := time.Now().UTC()
 	payload.CreatedAt = nowUTC
 	payload.UpdatedAt = nowUTC
 
-	query := `INSERT INTO sensors 
-		(device_id, type, name, description, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	expression, refs, err := sensorExpression(payload.Expression)
+	if err != nil {
+		return sensorID, err
+	}
 
-	err := r.db.QueryRowxContext(
+	// the device is kept out of the trash until the transaction ends
+	var found int
+	err = tx.GetContext(ctx, &found, `SELECT 1 FROM devices WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, payload.DeviceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sensorID, util.NewErrNotFound("device not found")
+		}
+
+		slog.Error(
+			"Failed to CreateSensor device",
+			slog.Any("err", err),
+			slog.Any("deviceID", payload.DeviceID),
+		)
+		return sensorID, util.NewErrInternalServer("failed to create sensor")
+	}
+
+	// the first association of the sensor starts when it is created
+	query := `WITH sensor AS (
+			INSERT INTO sensors 
+			(device_id, type, name, description, attributes, labels, expression, expression_refs, created_at, updated_at) 
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, device_id, created_at
+		)
+		INSERT INTO sensor_device_history (sensor_id, device_id, attached_at, created_at)
+		SELECT id, device_id, created_at, created_at FROM sensor RETURNING sensor_id`
+
+	err = tx.QueryRowxContext(
 		ctx,
 		query,
 		payload.DeviceID,
 		payload.Type,
 		payload.Name,
 		payload.Description,
+		JSONB(payload.Attributes),
+		StringJSONB(payload.Labels),
+		expression,
+		refs,
 		payload.CreatedAt,
 		payload.UpdatedAt,
 	).Scan(&sensorID)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return sensorID, util.NewErrNotFound("device not found")
+		}
+
 		slog.Error(
 			"Failed to CreateSensor",
 			slog.Any("err", err),
@@ -64,55 +145,210 @@ func (r *repository) CreateSensor(ctx context.Context, payload entities.Sensor)
 		return sensorID, util.NewErrInternalServer("failed to create sensor")
 	}
 
+	err = recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_CREATE, []string{sensorID}, nil)
+	if err != nil {
+		return sensorID, err
+	}
+
 	return sensorID, nil
 }
 
-func (r *repository) UpdateSensor(ctx context.Context, sensorID string, payload entities.Sensor) error {
+// UpdateSensor updates a sensor when its version is one of versions, any version when versions is nil.
+func (r *repository) UpdateSensor(ctx context.Context, sensorID string, payload entities.Sensor, versions []int) error {
+	expression, refs, err := sensorExpression(payload.Expression)
+	if err != nil {
+		return err
+	}
+
 	query := `UPDATE sensors 
-		SET name = $1, description = $2, updated_at = $3 
-		WHERE id = $4`
+		SET name = $1, description = $2, attributes = $3, labels = $4, expression = $5, expression_refs = $6, updated_at = $7, 
+			version = version + 1 
+		WHERE id = $8 AND deleted_at IS NULL`
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		before, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, []string{sensorID})
+		if err != nil {
+			return err
+		}
+		version, ok := activeVersion(before[sensorID])
+		if !ok {
+			return util.NewErrNotFound("sensor not found")
+		}
+		if !versionMatches(versions, version) {
+			return util.NewErrPreconditionFailed("sensor has been modified")
+		}
 
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		payload.Name,
-		payload.Description,
-		time.Now().UTC(),
-		sensorID,
-	)
-	if err != nil {
-		slog.Error(
-			"Failed to UpdateSensor",
-			slog.Any("err", err),
-			slog.Any("sensorID", sensorID),
-			slog.Any("payload", payload),
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			payload.Name,
+			payload.Description,
+			JSONB(payload.Attributes),
+			StringJSONB(payload.Labels),
+			expression,
+			refs,
+			time.Now().UTC(),
+			sensorID,
 		)
-		return util.NewErrInternalServer("failed to update sensor")
-	}
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateSensor",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+				slog.Any("payload", payload),
+			)
+			return util.NewErrInternalServer("failed to update sensor")
+		}
 
-	return nil
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_UPDATE, []string{sensorID}, before)
+	})
 }
 
-func (r *repository) DeleteSensor(ctx context.Context, sensorID string) error {
-	query := `DELETE FROM sensors WHERE id = $1`
+// DeleteSensor moves a sensor to the trash when its version is one of versions, any version when versions is nil.
+func (r *repository) DeleteSensor(ctx context.Context, sensorID string, versions []int) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var version int
+		err := tx.GetContext(ctx, &version, `SELECT version FROM sensors WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, sensorID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("sensor not found")
+			}
+
+			slog.Error(
+				"Failed to DeleteSensor",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to delete sensor")
+		}
+		if !versionMatches(versions, version) {
+			return util.NewErrPreconditionFailed("sensor has been modified")
+		}
 
-	_, err := r.db.ExecContext(ctx, query, sensorID)
-	if err != nil {
-		slog.Error(
-			"Failed to DeleteSensor",
-			slog.Any("err", err),
-			slog.Any("sensorID", sensorID),
-		)
-		return util.NewErrInternalServer("failed to delete sensor")
-	}
+		var dependents []string
+		err = tx.SelectContext(ctx, &dependents, `SELECT id FROM sensors WHERE $1 = ANY(expression_refs) AND deleted_at IS NULL`, sensorID)
+		if err != nil {
+			slog.Error(
+				"Failed to DeleteSensor dependents",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to delete sensor")
+		}
+		if len(dependents) > 0 {
+			return util.NewErrConflict(fmt.Sprintf("sensor is referenced by virtual sensors %s", strings.Join(dependents, ", ")))
+		}
+
+		before, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, []string{sensorID})
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE sensors SET deleted_at = $1, version = version + 1 WHERE id = $2`
+
+		_, err = tx.ExecContext(ctx, query, time.Now().UTC(), sensorID)
+		if err != nil {
+			slog.Error(
+				"Failed to DeleteSensor",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to delete sensor")
+		}
+
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_DELETE, []string{sensorID}, before)
+	})
+}
+
+// RestoreSensor takes a sensor out of the trash. Its device and, for a virtual sensor, the sensors of its
+// expression must not be in the trash.
+func (r *repository) RestoreSensor(ctx context.Context, sensorID string) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var sensor struct {
+			DeviceDeleted bool           `db:"device_deleted"`
+			Refs          pq.StringArray `db:"expression_refs"`
+		}
+
+		query := `SELECT d.deleted_at IS NOT NULL AS device_deleted, s.expression_refs
+			FROM sensors s
+			JOIN devices d ON d.id = s.device_id
+			WHERE s.id = $1 AND s.deleted_at IS NOT NULL
+			FOR UPDATE OF s`
+		err := tx.GetContext(ctx, &sensor, query, sensorID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("deleted sensor not found")
+			}
+
+			slog.Error(
+				"Failed to RestoreSensor",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to restore sensor")
+		}
+		if sensor.DeviceDeleted {
+			return util.NewErrConflict("the device of the sensor is deleted, restore the device first")
+		}
+
+		if len(sensor.Refs) > 0 {
+			var missing []string
+			err = tx.SelectContext(ctx, &missing, `SELECT id FROM sensors WHERE id = ANY($1) AND deleted_at IS NOT NULL`, sensor.Refs)
+			if err != nil {
+				slog.Error(
+					"Failed to RestoreSensor references",
+					slog.Any("err", err),
+					slog.Any("sensorID", sensorID),
+				)
+				return util.NewErrInternalServer("failed to restore sensor")
+			}
+			if len(missing) > 0 {
+				return util.NewErrConflict(fmt.Sprintf("sensor references deleted sensors (%s)", strings.Join(missing, ", ")))
+			}
+
+			// the expressions of other sensors may have changed to reference it while it was deleted
+			virtual, err := virtualSensors(ctx, tx)
+			if err != nil {
+				slog.Error(
+					"Failed to RestoreSensor virtual sensors",
+					slog.Any("err", err),
+					slog.Any("sensorID", sensorID),
+				)
+				return util.NewErrInternalServer("failed to restore sensor")
+			}
+
+			deps := map[string][]string{sensorID: sensor.Refs}
+			for _, v := range virtual {
+				deps[v.ID] = v.Refs
+			}
+			if cycle := expr.FindCycle(sensorID, deps); cycle != nil {
+				return util.NewErrConflict(fmt.Sprintf("sensor expression would have a reference cycle (%s)", strings.Join(cycle, " -> ")))
+			}
+		}
+
+		before, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, []string{sensorID})
+		if err != nil {
+			return err
+		}
 
-	return err
+		_, err = tx.ExecContext(ctx, `UPDATE sensors SET deleted_at = NULL, version = version + 1 WHERE id = $1`, sensorID)
+		if err != nil {
+			slog.Error(
+				"Failed to RestoreSensor",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to restore sensor")
+		}
+
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_RESTORE, []string{sensorID}, before)
+	})
 }
 
 func (r *repository) GetSensor(ctx context.Context, sensorID string) (*entities.Sensor, error) {
 	var model Sensor
 
-	query := `SELECT id, device_id, type, name, description, created_at, updated_at FROM sensors WHERE id = $1`
+	query := fmt.Sprintf(`SELECT %s FROM sensors WHERE id = $1 AND deleted_at IS NULL`, sensorColumns)
 	err := r.db.GetContext(ctx, &model, query, sensorID)
 
 	if err != nil {
@@ -139,16 +375,41 @@ func (r *repository) GetSensorList(ctx context.Context, params entities.GetSenso
 	)
 
 	queryCount := "SELECT COUNT(id) FROM sensors"
-	queryData := "SELECT id, device_id, type, name, description, created_at, updated_at FROM sensors"
+	queryData := fmt.Sprintf("SELECT %s FROM sensors", sensorColumns)
 
-	whereQueries := []string{}
+	args := map[string]any{}
+	whereQueries := []string{"deleted_at IS NULL"}
 	if params.Search != "" {
-		params.Search = fmt.Sprintf("%%%s%%", params.Search)
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
 		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
 	}
 	if params.DeviceID != "" {
+		args["device_id"] = params.DeviceID
 		whereQueries = append(whereQueries, "device_id = :device_id")
 	}
+	if params.DeviceIDs != nil {
+		args["device_ids"] = pq.Array(params.DeviceIDs)
+		whereQueries = append(whereQueries, "device_id = ANY(:device_ids)")
+	}
+	if locationQueries := locationFilterQueries("zone_id", params.Location, args); len(locationQueries) > 0 {
+		whereQueries = append(whereQueries, fmt.Sprintf(
+			"device_id IN (SELECT id FROM devices WHERE %s)",
+			strings.Join(locationQueries, " AND "),
+		))
+	}
+	if params.Virtual != nil {
+		if *params.Virtual {
+			whereQueries = append(whereQueries, "expression IS NOT NULL")
+		} else {
+			whereQueries = append(whereQueries, "expression IS NULL")
+		}
+	}
+	if len(params.Attributes) > 0 {
+		whereQueries = append(whereQueries, attributeFilterQueries("attributes", params.Attributes, args)...)
+	}
+	if len(params.Selector) > 0 {
+		whereQueries = append(whereQueries, labelSelectorQueries("labels", params.Selector, args)...)
+	}
 	if len(whereQueries) > 0 {
 		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
 
@@ -171,10 +432,7 @@ func (r *repository) GetSensorList(ctx context.Context, params entities.GetSenso
 	}
 	defer stmtCount.Close()
 
-	err = stmtCount.GetContext(ctx, &total, map[string]any{
-		"device_id": params.DeviceID,
-		"keyword":   params.Search,
-	})
+	err = stmtCount.GetContext(ctx, &total, args)
 	if err != nil {
 		slog.Error(
 			"Failed to GetSensorList Count GetContext",
@@ -202,10 +460,7 @@ func (r *repository) GetSensorList(ctx context.Context, params entities.GetSenso
 	defer stmtData.Close()
 
 	var model []Sensor
-	err = stmtData.SelectContext(ctx, &model, map[string]any{
-		"device_id": params.DeviceID,
-		"keyword":   params.Search,
-	})
+	err = stmtData.SelectContext(ctx, &model, args)
 	if err != nil {
 		slog.Error(
 			"Failed to GetSensorList Data SelectContext",
@@ -222,3 +477,197 @@ func (r *repository) GetSensorList(ctx context.Context, params entities.GetSenso
 
 	return sensors, total, nil
 }
+
+type SensorDeviceAssociation struct {
+	ID         int64      `db:"id"`
+	SensorID   string     `db:"sensor_id"`
+	DeviceID   string     `db:"device_id"`
+	AttachedAt time.Time  `db:"attached_at"`
+	DetachedAt *time.Time `db:"detached_at"`
+}
+
+func (a *SensorDeviceAssociation) ToEntity() *entities.SensorDeviceAssociation {
+	return &entities.SensorDeviceAssociation{
+		ID:         a.ID,
+		SensorID:   a.SensorID,
+		DeviceID:   a.DeviceID,
+		AttachedAt: a.AttachedAt,
+		DetachedAt: a.DetachedAt,
+	}
+}
+
+// MoveSensor attaches a sensor to another device from movedAt on. The current association is closed,
+// a new one is opened and readings recorded from movedAt on are attributed to the new device.
+func (r *repository) MoveSensor(ctx context.Context, sensorID, deviceID string, movedAt time.Time) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var current SensorDeviceAssociation
+
+		query := `SELECT h.id, h.sensor_id, h.device_id, h.attached_at, h.detached_at
+			FROM sensors s
+			JOIN sensor_device_history h ON h.sensor_id = s.id AND h.detached_at IS NULL
+			WHERE s.id = $1 AND s.deleted_at IS NULL
+			FOR UPDATE`
+		err := tx.GetContext(ctx, &current, query, sensorID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("sensor not found")
+			}
+
+			slog.Error(
+				"Failed to MoveSensor Get",
+				slog.Any("err", err),
+				slog.Any("sensorID", sensorID),
+			)
+			return util.NewErrInternalServer("failed to move sensor")
+		}
+
+		if current.DeviceID == deviceID {
+			return util.NewErrInvalidRequest("sensor is already attached to the device")
+		}
+		if movedAt.Before(current.AttachedAt) {
+			return util.NewErrInvalidRequest("moved_at is before the sensor was attached to its current device")
+		}
+
+		// the device is kept out of the trash until the transaction ends
+		var found int
+		err = tx.GetContext(ctx, &found, `SELECT 1 FROM devices WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, deviceID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return util.NewErrNotFound("device not found")
+			}
+
+			slog.Error(
+				"Failed to MoveSensor device",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to move sensor")
+		}
+
+		before, err := auditSnapshot(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, []string{sensorID})
+		if err != nil {
+			return err
+		}
+
+		nowUTC := time.Now().UTC()
+		queries := []struct {
+			query string
+			args  []any
+		}{
+			{
+				`UPDATE sensor_device_history SET detached_at = $1 WHERE id = $2`,
+				[]any{movedAt, current.ID},
+			},
+			{
+				`INSERT INTO sensor_device_history (sensor_id, device_id, attached_at, created_at) VALUES ($1, $2, $3, $4)`,
+				[]any{sensorID, deviceID, movedAt, nowUTC},
+			},
+			{
+				`UPDATE sensors SET device_id = $1, updated_at = $2, version = version + 1 WHERE id = $3`,
+				[]any{deviceID, nowUTC, sensorID},
+			},
+			{
+				`UPDATE sensor_readings SET device_id = $1 WHERE sensor_id = $2 AND recorded_at >= $3`,
+				[]any{deviceID, sensorID, movedAt},
+			},
+		}
+
+		for _, q := range queries {
+			_, err = tx.ExecContext(ctx, q.query, q.args...)
+			if err != nil {
+				if isForeignKeyViolation(err) {
+					return util.NewErrNotFound("device not found")
+				}
+
+				slog.Error(
+					"Failed to MoveSensor",
+					slog.Any("err", err),
+					slog.Any("sensorID", sensorID),
+					slog.Any("deviceID", deviceID),
+				)
+				return util.NewErrInternalServer("failed to move sensor")
+			}
+		}
+
+		return recordAudit(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_UPDATE, []string{sensorID}, before)
+	})
+}
+
+// GetSensorDeviceHistory returns every device association of a sensor, the current one first.
+func (r *repository) GetSensorDeviceHistory(ctx context.Context, sensorID string) ([]*entities.SensorDeviceAssociation, error) {
+	query := `SELECT id, sensor_id, device_id, attached_at, detached_at FROM sensor_device_history
+		WHERE sensor_id = $1 ORDER BY attached_at DESC`
+
+	var model []SensorDeviceAssociation
+	err := r.db.SelectContext(ctx, &model, query, sensorID)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSensorDeviceHistory",
+			slog.Any("err", err),
+			slog.Any("sensorID", sensorID),
+		)
+		return nil, util.NewErrInternalServer("failed to get sensor device history")
+	}
+
+	history := []*entities.SensorDeviceAssociation{}
+	for _, v := range model {
+		history = append(history, v.ToEntity())
+	}
+
+	return history, nil
+}
+
+// GetExistingSensorIDs returns which of the given sensor IDs exist.
+func (r *repository) GetExistingSensorIDs(ctx context.Context, sensorIDs []string) ([]string, error) {
+	query := `SELECT id FROM sensors WHERE id = ANY($1) AND deleted_at IS NULL`
+
+	existing := []string{}
+	err := r.db.SelectContext(ctx, &existing, query, pq.Array(sensorIDs))
+	if err != nil {
+		slog.Error(
+			"Failed to GetExistingSensorIDs",
+			slog.Any("err", err),
+			slog.Any("sensorIDs", sensorIDs),
+		)
+		return nil, util.NewErrInternalServer("failed to get sensors")
+	}
+
+	return existing, nil
+}
+
+type VirtualSensor struct {
+	ID         string         `db:"id"`
+	Expression string         `db:"expression"`
+	Refs       pq.StringArray `db:"expression_refs"`
+}
+
+func virtualSensors(ctx context.Context, q sqlx.QueryerContext) ([]VirtualSensor, error) {
+	query := `SELECT id, expression, expression_refs FROM sensors WHERE expression IS NOT NULL AND deleted_at IS NULL`
+
+	var model []VirtualSensor
+	err := sqlx.SelectContext(ctx, q, &model, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// GetVirtualSensorRefs maps every virtual sensor ID to the sensor IDs its expression references.
+func (r *repository) GetVirtualSensorRefs(ctx context.Context) (map[string][]string, error) {
+	model, err := virtualSensors(ctx, r.db)
+	if err != nil {
+		slog.Error(
+			"Failed to GetVirtualSensorRefs",
+			slog.Any("err", err),
+		)
+		return nil, util.NewErrInternalServer("failed to get virtual sensors")
+	}
+
+	refs := map[string][]string{}
+	for _, v := range model {
+		refs[v.ID] = v.Refs
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/pkg/util"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type DeviceShadow struct {
+	DeviceID  string    `db:"device_id"`
+	Desired   JSONB     `db:"desired"`
+	Reported  JSONB     `db:"reported"`
+	Version   int64     `db:"version"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (d *DeviceShadow) ToEntity() *entities.DeviceShadow {
+	shadow := &entities.DeviceShadow{
+		DeviceID:  d.DeviceID,
+		Desired:   d.Desired,
+		Reported:  d.Reported,
+		Version:   d.Version,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+	shadow.Delta = shadow.ComputeDelta()
+
+	return shadow
+}
+
+func (r *repository) GetDeviceShadow(ctx context.Context, deviceID string) (*entities.DeviceShadow, error) {
+	var model DeviceShadow
+
+	query := `SELECT device_id, desired, reported, version, created_at, updated_at FROM device_shadows WHERE device_id = $1`
+	err := r.db.GetContext(ctx, &model, query, deviceID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// a device without shadow document has an empty one
+			model = DeviceShadow{
+				DeviceID: deviceID,
+				Desired:  JSONB{},
+				Reported: JSONB{},
+			}
+			return model.ToEntity(), nil
+		}
+
+		slog.Error(
+			"Failed to GetDeviceShadow",
+			slog.Any("err", err),
+			slog.Any("deviceID", deviceID),
+		)
+		return nil, util.NewErrInternalServer("failed to get device shadow")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) UpdateDeviceShadow(ctx context.Context, deviceID string, section entities.ShadowSection, payload entities.UpdateDeviceShadowPayload) error {
+	column := "desired"
+	if section == entities.SHADOW_SECTION_REPORTED {
+		column = "reported"
+	}
+
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		nowUTC := time.Now().UTC()
+
+		query := `INSERT INTO device_shadows (device_id, created_at, updated_at)
+			VALUES ($1, $2, $2) ON CONFLICT (device_id) DO NOTHING`
+
+		_, err := tx.ExecContext(ctx, query, deviceID, nowUTC)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateDeviceShadow Insert",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to update device shadow")
+		}
+
+		var model DeviceShadow
+		query = `SELECT device_id, desired, reported, version, created_at, updated_at
+			FROM device_shadows WHERE device_id = $1 FOR UPDATE`
+
+		err = tx.GetContext(ctx, &model, query, deviceID)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateDeviceShadow Select",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+			)
+			return util.NewErrInternalServer("failed to update device shadow")
+		}
+
+		if payload.Version != nil && *payload.Version != model.Version {
+			return util.NewErrPreconditionFailed("device shadow has been modified")
+		}
+
+		current := model.Desired
+		if section == entities.SHADOW_SECTION_REPORTED {
+			current = model.Reported
+		}
+		state := JSONB(entities.MergeShadowState(current, payload.State))
+
+		query = fmt.Sprintf(`UPDATE device_shadows
+			SET %s = $1, version = version + 1, updated_at = $2
+			WHERE device_id = $3
+			RETURNING device_id, desired, reported, version, created_at, updated_at`, column)
+
+		err = tx.GetContext(ctx, &model, query, state, nowUTC, deviceID)
+		if err != nil {
+			slog.Error(
+				"Failed to UpdateDeviceShadow Update",
+				slog.Any("err", err),
+				slog.Any("deviceID", deviceID),
+				slog.Any("payload", payload),
+			)
+			return util.NewErrInternalServer("failed to update device shadow")
+		}
+
+		return insertOutboxEvent(ctx, tx, events.DEVICE_SHADOW_UPDATED, deviceID, model.ToEntity(), nowUTC)
+	})
+}
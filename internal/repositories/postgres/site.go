@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const siteColumns = `id, organization_id, name, description, created_at, updated_at`
+
+type Site struct {
+	ID             string    `db:"id"`
+	OrganizationID string    `db:"organization_id"`
+	Name           string    `db:"name"`
+	Description    string    `db:"description"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (s *Site) ToEntity() *entities.Site {
+	return &entities.Site{
+		ID:             s.ID,
+		OrganizationID: s.OrganizationID,
+		Name:           s.Name,
+		Description:    s.Description,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+func (r *repository) CreateSite(ctx context.Context, payload entities.Site) (string, error) {
+	var siteID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO sites
+		(organization_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.OrganizationID,
+		payload.Name,
+		payload.Description,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&siteID)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateSite",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return siteID, util.NewErrInternalServer("failed to create site")
+	}
+
+	return siteID, nil
+}
+
+func (r *repository) UpdateSite(ctx context.Context, siteID string, payload entities.Site) error {
+	query := `UPDATE sites
+		SET organization_id = $1, name = $2, description = $3, updated_at = $4
+		WHERE id = $5`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.OrganizationID,
+		payload.Name,
+		payload.Description,
+		time.Now().UTC(),
+		siteID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateSite",
+			slog.Any("err", err),
+			slog.Any("siteID", siteID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update site")
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteSite(ctx context.Context, siteID string) error {
+	query := `DELETE FROM sites WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, siteID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return util.NewErrConflict("site still has zones")
+		}
+
+		slog.Error(
+			"Failed to DeleteSite",
+			slog.Any("err", err),
+			slog.Any("siteID", siteID),
+		)
+		return util.NewErrInternalServer("failed to delete site")
+	}
+
+	return nil
+}
+
+func (r *repository) GetSite(ctx context.Context, siteID string) (*entities.Site, error) {
+	var model Site
+
+	query := fmt.Sprintf(`SELECT %s FROM sites WHERE id = $1`, siteColumns)
+	err := r.db.GetContext(ctx, &model, query, siteID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("site not found")
+		}
+
+		slog.Error(
+			"Failed to GetSite",
+			slog.Any("err", err),
+			slog.Any("siteID", siteID),
+		)
+		return nil, util.NewErrInternalServer("failed to get site")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetSiteList(ctx context.Context, params entities.GetSiteListParams) ([]*entities.Site, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM sites"
+	queryData := fmt.Sprintf("SELECT %s FROM sites", siteColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if params.OrganizationID != "" {
+		args["organization_id"] = params.OrganizationID
+		whereQueries = append(whereQueries, "organization_id = :organization_id")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSiteList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get site list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSiteList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get site list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSiteList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get site list")
+	}
+	defer stmtData.Close()
+
+	var model []Site
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetSiteList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get site list")
+	}
+
+	sites := []*entities.Site{}
+	for _, v := range model {
+		sites = append(sites, v.ToEntity())
+	}
+
+	return sites, total, nil
+}
@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const deviceTemplateColumns = `id, slug, name, description, device, sensors, created_at, updated_at`
+
+type DeviceTemplate struct {
+	ID          string    `db:"id"`
+	Slug        string    `db:"slug"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Device      []byte    `db:"device"`
+	Sensors     []byte    `db:"sensors"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (t *DeviceTemplate) ToEntity() *entities.DeviceTemplate {
+	template := &entities.DeviceTemplate{
+		ID:          t.ID,
+		Slug:        t.Slug,
+		Name:        t.Name,
+		Description: t.Description,
+		Sensors:     []entities.DeviceTemplateSensor{},
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+
+	if len(t.Device) > 0 {
+		_ = json.Unmarshal(t.Device, &template.Device)
+	}
+	if len(t.Sensors) > 0 {
+		_ = json.Unmarshal(t.Sensors, &template.Sensors)
+	}
+
+	return template
+}
+
+// templateDocuments returns the device defaults and sensor list of a template as jsonb values.
+func templateDocuments(payload entities.DeviceTemplate) ([]byte, []byte, error) {
+	device, err := json.Marshal(payload.Device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sensors := payload.Sensors
+	if sensors == nil {
+		sensors = []entities.DeviceTemplateSensor{}
+	}
+	sensorList, err := json.Marshal(sensors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return device, sensorList, nil
+}
+
+func (r *repository) CreateDeviceTemplate(ctx context.Context, payload entities.DeviceTemplate) (string, error) {
+	var templateID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	device, sensors, err := templateDocuments(payload)
+	if err != nil {
+		return templateID, util.NewErrInvalidRequest("invalid device template")
+	}
+
+	query := `INSERT INTO device_templates
+		(slug, name, description, device, sensors, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	err = r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.Slug,
+		payload.Name,
+		payload.Description,
+		device,
+		sensors,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&templateID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return templateID, util.NewErrConflict("device template slug already exists")
+		}
+
+		slog.Error(
+			"Failed to CreateDeviceTemplate",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return templateID, util.NewErrInternalServer("failed to create device template")
+	}
+
+	return templateID, nil
+}
+
+func (r *repository) UpdateDeviceTemplate(ctx context.Context, templateID string, payload entities.DeviceTemplate) error {
+	device, sensors, err := templateDocuments(payload)
+	if err != nil {
+		return util.NewErrInvalidRequest("invalid device template")
+	}
+
+	query := `UPDATE device_templates
+		SET slug = $1, name = $2, description = $3, device = $4, sensors = $5, updated_at = $6
+		WHERE id = $7`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		payload.Slug,
+		payload.Name,
+		payload.Description,
+		device,
+		sensors,
+		time.Now().UTC(),
+		templateID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return util.NewErrConflict("device template slug already exists")
+		}
+
+		slog.Error(
+			"Failed to UpdateDeviceTemplate",
+			slog.Any("err", err),
+			slog.Any("templateID", templateID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update device template")
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteDeviceTemplate(ctx context.Context, templateID string) error {
+	query := `DELETE FROM device_templates WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, templateID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteDeviceTemplate",
+			slog.Any("err", err),
+			slog.Any("templateID", templateID),
+		)
+		return util.NewErrInternalServer("failed to delete device template")
+	}
+
+	return nil
+}
+
+func (r *repository) GetDeviceTemplate(ctx context.Context, templateID string) (*entities.DeviceTemplate, error) {
+	return r.getDeviceTemplateBy(ctx, "id", templateID)
+}
+
+func (r *repository) GetDeviceTemplateBySlug(ctx context.Context, slug string) (*entities.DeviceTemplate, error) {
+	return r.getDeviceTemplateBy(ctx, "slug", slug)
+}
+
+func (r *repository) getDeviceTemplateBy(ctx context.Context, column, value string) (*entities.DeviceTemplate, error) {
+	var model DeviceTemplate
+
+	query := fmt.Sprintf(`SELECT %s FROM device_templates WHERE %s = $1`, deviceTemplateColumns, column)
+	err := r.db.GetContext(ctx, &model, query, value)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("device template not found")
+		}
+
+		slog.Error(
+			"Failed to GetDeviceTemplate",
+			slog.Any("err", err),
+			slog.Any(column, value),
+		)
+		return nil, util.NewErrInternalServer("failed to get device template")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetDeviceTemplateList(ctx context.Context, params entities.GetDeviceTemplateListParams) ([]*entities.DeviceTemplate, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"slug", "name", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM device_templates"
+	queryData := fmt.Sprintf("SELECT %s FROM device_templates", deviceTemplateColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(slug LIKE :keyword OR name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceTemplateList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device template list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceTemplateList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device template list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceTemplateList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device template list")
+	}
+	defer stmtData.Close()
+
+	var model []DeviceTemplate
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetDeviceTemplateList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get device template list")
+	}
+
+	templates := []*entities.DeviceTemplate{}
+	for _, v := range model {
+		templates = append(templates, v.ToEntity())
+	}
+
+	return templates, total, nil
+}
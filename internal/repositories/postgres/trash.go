@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// trashQuery lists the deleted devices and sensors as one table.
+const trashQuery = `(
+		SELECT 'device' AS type, id, name, NULL::uuid AS device_id, created_at, deleted_at
+		FROM devices WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'sensor' AS type, id, name, device_id, created_at, deleted_at
+		FROM sensors WHERE deleted_at IS NOT NULL
+	) t`
+
+type TrashItem struct {
+	Type      string    `db:"type"`
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	DeviceID  *string   `db:"device_id"`
+	CreatedAt time.Time `db:"created_at"`
+	DeletedAt time.Time `db:"deleted_at"`
+}
+
+func (t *TrashItem) ToEntity() *entities.TrashItem {
+	return &entities.TrashItem{
+		Type:      entities.TrashItemType(t.Type),
+		ID:        t.ID,
+		Name:      t.Name,
+		DeviceID:  t.DeviceID,
+		CreatedAt: t.CreatedAt,
+		DeletedAt: t.DeletedAt,
+	}
+}
+
+// GetTrashList returns the deleted devices and sensors, the latest deleted first unless another sort is given.
+func (r *repository) GetTrashList(ctx context.Context, params entities.GetTrashListParams) ([]*entities.TrashItem, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"type", "name", "created_at", "deleted_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+	if params.Sort == "" {
+		orderBy = "deleted_at DESC"
+	}
+
+	queryCount := fmt.Sprintf("SELECT COUNT(id) FROM %s", trashQuery)
+	queryData := fmt.Sprintf("SELECT type, id, name, device_id, created_at, deleted_at FROM %s", trashQuery)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Type != "" {
+		args["type"] = params.Type
+		whereQueries = append(whereQueries, "type = :type")
+	}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "name LIKE :keyword")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetTrashList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get trash list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetTrashList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get trash list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetTrashList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get trash list")
+	}
+	defer stmtData.Close()
+
+	var model []TrashItem
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetTrashList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get trash list")
+	}
+
+	items := []*entities.TrashItem{}
+	for _, v := range model {
+		items = append(items, v.ToEntity())
+	}
+
+	return items, total, nil
+}
+
+// PurgeTrash deletes for good the devices and sensors deleted before the given time, with everything that
+// belongs to them but their audit log, in one transaction.
+func (r *repository) PurgeTrash(ctx context.Context, before time.Time) (*entities.PurgedTrash, error) {
+	purged := &entities.PurgedTrash{}
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		// the alerts of a composite rule on a device have no sensor to cascade from
+		_, err := tx.ExecContext(
+			ctx,
+			`DELETE FROM alert_states WHERE sensor_id IS NULL 
+			AND device_id IN (SELECT id FROM devices WHERE deleted_at < $1)`,
+			before,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to PurgeTrash alerts",
+				slog.Any("err", err),
+				slog.Any("before", before),
+			)
+			return util.NewErrInternalServer("failed to purge trash")
+		}
+
+		// the deleted rows are returned as JSON for the audit log
+		sensors, err := auditRows(
+			ctx,
+			tx,
+			`DELETE FROM sensors s WHERE s.deleted_at < $1 RETURNING s.id, to_jsonb(s) AS data`,
+			before,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to PurgeTrash sensors",
+				slog.Any("err", err),
+				slog.Any("before", before),
+			)
+			return util.NewErrInternalServer("failed to purge trash")
+		}
+		purged.Sensors = int64(len(sensors))
+
+		devices, err := auditRows(
+			ctx,
+			tx,
+			`DELETE FROM devices d WHERE d.deleted_at < $1 
+			AND NOT EXISTS (SELECT 1 FROM sensors s WHERE s.device_id = d.id)
+			RETURNING d.id, to_jsonb(d) AS data`,
+			before,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to PurgeTrash devices",
+				slog.Any("err", err),
+				slog.Any("before", before),
+			)
+			return util.NewErrInternalServer("failed to purge trash")
+		}
+		purged.Devices = int64(len(devices))
+
+		err = insertAuditEntries(ctx, tx, entities.AUDIT_RESOURCE_SENSOR, entities.AUDIT_ACTION_PURGE, sensors, nil)
+		if err != nil {
+			return err
+		}
+
+		return insertAuditEntries(ctx, tx, entities.AUDIT_RESOURCE_DEVICE, entities.AUDIT_ACTION_PURGE, devices, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,585 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	webhookColumns         = `id, name, url, secret, events, enabled, created_at, updated_at`
+	webhookDeliveryColumns = `id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code,
+	last_error, delivered_at, redelivery_of, created_at, updated_at`
+	webhookAttemptColumns = `id, delivery_id, status_code, error, response_body, duration_ms, created_at`
+)
+
+type Webhook struct {
+	ID        string         `db:"id"`
+	Name      string         `db:"name"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"`
+	Events    pq.StringArray `db:"events"`
+	Enabled   bool           `db:"enabled"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func (w *Webhook) ToEntity() *entities.Webhook {
+	events := []string{}
+	events = append(events, w.Events...)
+
+	return &entities.Webhook{
+		ID:        w.ID,
+		Name:      w.Name,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Events:    events,
+		Enabled:   w.Enabled,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+type WebhookDelivery struct {
+	ID             string     `db:"id"`
+	WebhookID      string     `db:"webhook_id"`
+	EventType      string     `db:"event_type"`
+	Payload        JSONB      `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  *time.Time `db:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code"`
+	LastError      string     `db:"last_error"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	RedeliveryOf   *string    `db:"redelivery_of"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+func (w *WebhookDelivery) ToEntity() *entities.WebhookDelivery {
+	return &entities.WebhookDelivery{
+		ID:             w.ID,
+		WebhookID:      w.WebhookID,
+		EventType:      w.EventType,
+		Payload:        w.Payload,
+		Status:         entities.WebhookDeliveryStatus(w.Status),
+		Attempts:       w.Attempts,
+		NextAttemptAt:  w.NextAttemptAt,
+		LastStatusCode: w.LastStatusCode,
+		LastError:      w.LastError,
+		DeliveredAt:    w.DeliveredAt,
+		RedeliveryOf:   w.RedeliveryOf,
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
+	}
+}
+
+type WebhookAttempt struct {
+	ID           string    `db:"id"`
+	DeliveryID   string    `db:"delivery_id"`
+	StatusCode   *int      `db:"status_code"`
+	Error        string    `db:"error"`
+	ResponseBody string    `db:"response_body"`
+	DurationMs   int       `db:"duration_ms"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (w *WebhookAttempt) ToEntity() *entities.WebhookAttempt {
+	return &entities.WebhookAttempt{
+		ID:           w.ID,
+		DeliveryID:   w.DeliveryID,
+		StatusCode:   w.StatusCode,
+		Error:        w.Error,
+		ResponseBody: w.ResponseBody,
+		DurationMs:   w.DurationMs,
+		CreatedAt:    w.CreatedAt,
+	}
+}
+
+func (r *repository) CreateWebhook(ctx context.Context, payload entities.Webhook) (string, error) {
+	var webhookID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO webhooks
+		(name, url, secret, events, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.URL,
+		payload.Secret,
+		pq.Array(payload.Events),
+		payload.Enabled,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&webhookID)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateWebhook",
+			slog.Any("err", err),
+			slog.Any("name", payload.Name),
+			slog.Any("url", payload.URL),
+		)
+		return webhookID, util.NewErrInternalServer("failed to create webhook")
+	}
+
+	return webhookID, nil
+}
+
+func (r *repository) UpdateWebhook(ctx context.Context, webhookID string, payload entities.Webhook) error {
+	query := `UPDATE webhooks
+		SET name = $1, url = $2, secret = $3, events = $4, enabled = $5, updated_at = $6
+		WHERE id = $7`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.Name,
+		payload.URL,
+		payload.Secret,
+		pq.Array(payload.Events),
+		payload.Enabled,
+		time.Now().UTC(),
+		webhookID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateWebhook",
+			slog.Any("err", err),
+			slog.Any("webhookID", webhookID),
+		)
+		return util.NewErrInternalServer("failed to update webhook")
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes a webhook with its delivery log, pending retries are dropped.
+func (r *repository) DeleteWebhook(ctx context.Context, webhookID string) error {
+	query := `DELETE FROM webhooks WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, webhookID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteWebhook",
+			slog.Any("err", err),
+			slog.Any("webhookID", webhookID),
+		)
+		return util.NewErrInternalServer("failed to delete webhook")
+	}
+
+	return nil
+}
+
+func (r *repository) GetWebhook(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+	var model Webhook
+
+	query := fmt.Sprintf(`SELECT %s FROM webhooks WHERE id = $1`, webhookColumns)
+	err := r.db.GetContext(ctx, &model, query, webhookID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("webhook not found")
+		}
+
+		slog.Error(
+			"Failed to GetWebhook",
+			slog.Any("err", err),
+			slog.Any("webhookID", webhookID),
+		)
+		return nil, util.NewErrInternalServer("failed to get webhook")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetWebhookList(ctx context.Context, params entities.GetWebhookListParams) ([]*entities.Webhook, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM webhooks"
+	queryData := fmt.Sprintf("SELECT %s FROM webhooks", webhookColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR url LIKE :keyword)")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook list")
+	}
+	defer stmtData.Close()
+
+	var model []Webhook
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook list")
+	}
+
+	webhooks := []*entities.Webhook{}
+	for _, v := range model {
+		webhooks = append(webhooks, v.ToEntity())
+	}
+
+	return webhooks, total, nil
+}
+
+func (r *repository) GetEnabledWebhooks(ctx context.Context) ([]*entities.Webhook, error) {
+	query := fmt.Sprintf(`SELECT %s FROM webhooks WHERE enabled`, webhookColumns)
+
+	var model []Webhook
+	err := r.db.SelectContext(ctx, &model, query)
+	if err != nil {
+		slog.Error(
+			"Failed to GetEnabledWebhooks",
+			slog.Any("err", err),
+		)
+		return nil, util.NewErrInternalServer("failed to get webhooks")
+	}
+
+	webhooks := []*entities.Webhook{}
+	for _, v := range model {
+		webhooks = append(webhooks, v.ToEntity())
+	}
+
+	return webhooks, nil
+}
+
+func (r *repository) CreateWebhookDelivery(ctx context.Context, payload entities.WebhookDelivery) (string, error) {
+	var deliveryID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO webhook_deliveries
+		(webhook_id, event_type, payload, status, next_attempt_at, redelivery_of, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.WebhookID,
+		payload.EventType,
+		JSONB(payload.Payload),
+		entities.WEBHOOK_DELIVERY_STATUS_PENDING,
+		payload.NextAttemptAt,
+		payload.RedeliveryOf,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&deliveryID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return deliveryID, util.NewErrNotFound("webhook not found")
+		}
+
+		slog.Error(
+			"Failed to CreateWebhookDelivery",
+			slog.Any("err", err),
+			slog.Any("webhookID", payload.WebhookID),
+			slog.Any("eventType", payload.EventType),
+		)
+		return deliveryID, util.NewErrInternalServer("failed to create webhook delivery")
+	}
+
+	return deliveryID, nil
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries due at now and moves their next attempt to
+// the end of the lease, so a sender that dies mid request does not lose them and other senders skip them.
+// Deliveries of disabled webhooks are left pending until the webhook is enabled again.
+func (r *repository) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	query := fmt.Sprintf(`UPDATE webhook_deliveries
+		SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $2 AND next_attempt_at <= $3
+				AND webhook_id IN (SELECT id FROM webhooks WHERE enabled)
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s`, webhookDeliveryColumns)
+
+	var model []WebhookDelivery
+	err := r.db.SelectContext(
+		ctx,
+		&model,
+		query,
+		now.Add(lease),
+		entities.WEBHOOK_DELIVERY_STATUS_PENDING,
+		now,
+		limit,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to ClaimWebhookDeliveries",
+			slog.Any("err", err),
+		)
+		return nil, util.NewErrInternalServer("failed to get due webhook deliveries")
+	}
+
+	deliveries := []*entities.WebhookDelivery{}
+	for _, v := range model {
+		deliveries = append(deliveries, v.ToEntity())
+	}
+
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt adds an attempt to the delivery log and stores the resulting state of the delivery.
+func (r *repository) RecordWebhookAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookAttempt) error {
+	return r.withTx(ctx, func(tx *sqlx.Tx) error {
+		queryAttempt := `INSERT INTO webhook_delivery_attempts
+			(delivery_id, status_code, error, response_body, duration_ms, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`
+
+		_, err := tx.ExecContext(
+			ctx,
+			queryAttempt,
+			delivery.ID,
+			attempt.StatusCode,
+			attempt.Error,
+			attempt.ResponseBody,
+			attempt.DurationMs,
+			attempt.CreatedAt,
+		)
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return util.NewErrNotFound("webhook delivery not found")
+			}
+
+			slog.Error(
+				"Failed to RecordWebhookAttempt Insert",
+				slog.Any("err", err),
+				slog.Any("deliveryID", delivery.ID),
+			)
+			return util.NewErrInternalServer("failed to record webhook attempt")
+		}
+
+		queryDelivery := `UPDATE webhook_deliveries
+			SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5,
+				delivered_at = $6, updated_at = $7
+			WHERE id = $8`
+
+		_, err = tx.ExecContext(
+			ctx,
+			queryDelivery,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.LastStatusCode,
+			delivery.LastError,
+			delivery.DeliveredAt,
+			attempt.CreatedAt,
+			delivery.ID,
+		)
+		if err != nil {
+			slog.Error(
+				"Failed to RecordWebhookAttempt Update",
+				slog.Any("err", err),
+				slog.Any("deliveryID", delivery.ID),
+			)
+			return util.NewErrInternalServer("failed to record webhook attempt")
+		}
+
+		return nil
+	})
+}
+
+// GetWebhookDelivery returns a delivery with its attempts, oldest first.
+func (r *repository) GetWebhookDelivery(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+	var model WebhookDelivery
+
+	query := fmt.Sprintf(`SELECT %s FROM webhook_deliveries WHERE id = $1`, webhookDeliveryColumns)
+	err := r.db.GetContext(ctx, &model, query, deliveryID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("webhook delivery not found")
+		}
+
+		slog.Error(
+			"Failed to GetWebhookDelivery",
+			slog.Any("err", err),
+			slog.Any("deliveryID", deliveryID),
+		)
+		return nil, util.NewErrInternalServer("failed to get webhook delivery")
+	}
+
+	queryAttempts := fmt.Sprintf(`SELECT %s FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY created_at`, webhookAttemptColumns)
+
+	var attempts []WebhookAttempt
+	err = r.db.SelectContext(ctx, &attempts, queryAttempts, deliveryID)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookDelivery Attempts",
+			slog.Any("err", err),
+			slog.Any("deliveryID", deliveryID),
+		)
+		return nil, util.NewErrInternalServer("failed to get webhook delivery")
+	}
+
+	delivery := model.ToEntity()
+	delivery.AttemptLog = []*entities.WebhookAttempt{}
+	for _, v := range attempts {
+		delivery.AttemptLog = append(delivery.AttemptLog, v.ToEntity())
+	}
+
+	return delivery, nil
+}
+
+func (r *repository) GetWebhookDeliveryList(ctx context.Context, params entities.GetWebhookDeliveryListParams) ([]*entities.WebhookDelivery, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"status", "event_type", "next_attempt_at", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM webhook_deliveries"
+	queryData := fmt.Sprintf("SELECT %s FROM webhook_deliveries", webhookDeliveryColumns)
+
+	args := map[string]any{
+		"webhook_id": params.WebhookID,
+	}
+	whereQueries := []string{"webhook_id = :webhook_id"}
+	if params.Status != "" {
+		args["status"] = params.Status
+		whereQueries = append(whereQueries, "status = :status")
+	}
+	if params.EventType != "" {
+		args["event_type"] = params.EventType
+		whereQueries = append(whereQueries, "event_type = :event_type")
+	}
+
+	whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+	queryCount += whereQuery
+	queryData += whereQuery
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookDeliveryList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook delivery list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookDeliveryList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook delivery list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookDeliveryList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook delivery list")
+	}
+	defer stmtData.Close()
+
+	var model []WebhookDelivery
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetWebhookDeliveryList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get webhook delivery list")
+	}
+
+	deliveries := []*entities.WebhookDelivery{}
+	for _, v := range model {
+		deliveries = append(deliveries, v.ToEntity())
+	}
+
+	return deliveries, total, nil
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/pkg/util"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const zoneColumns = `id, site_id, name, description, created_at, updated_at`
+
+type Zone struct {
+	ID          string    `db:"id"`
+	SiteID      string    `db:"site_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func (z *Zone) ToEntity() *entities.Zone {
+	return &entities.Zone{
+		ID:          z.ID,
+		SiteID:      z.SiteID,
+		Name:        z.Name,
+		Description: z.Description,
+		CreatedAt:   z.CreatedAt,
+		UpdatedAt:   z.UpdatedAt,
+	}
+}
+
+func (r *repository) CreateZone(ctx context.Context, payload entities.Zone) (string, error) {
+	var zoneID string
+
+	nowUTC := time.Now().UTC()
+	payload.CreatedAt = nowUTC
+	payload.UpdatedAt = nowUTC
+
+	query := `INSERT INTO zones
+		(site_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		payload.SiteID,
+		payload.Name,
+		payload.Description,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	).Scan(&zoneID)
+	if err != nil {
+		slog.Error(
+			"Failed to CreateZone",
+			slog.Any("err", err),
+			slog.Any("payload", payload),
+		)
+		return zoneID, util.NewErrInternalServer("failed to create zone")
+	}
+
+	return zoneID, nil
+}
+
+func (r *repository) UpdateZone(ctx context.Context, zoneID string, payload entities.Zone) error {
+	query := `UPDATE zones
+		SET site_id = $1, name = $2, description = $3, updated_at = $4
+		WHERE id = $5`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		payload.SiteID,
+		payload.Name,
+		payload.Description,
+		time.Now().UTC(),
+		zoneID,
+	)
+	if err != nil {
+		slog.Error(
+			"Failed to UpdateZone",
+			slog.Any("err", err),
+			slog.Any("zoneID", zoneID),
+			slog.Any("payload", payload),
+		)
+		return util.NewErrInternalServer("failed to update zone")
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteZone(ctx context.Context, zoneID string) error {
+	query := `DELETE FROM zones WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, zoneID)
+	if err != nil {
+		slog.Error(
+			"Failed to DeleteZone",
+			slog.Any("err", err),
+			slog.Any("zoneID", zoneID),
+		)
+		return util.NewErrInternalServer("failed to delete zone")
+	}
+
+	return nil
+}
+
+func (r *repository) GetZone(ctx context.Context, zoneID string) (*entities.Zone, error) {
+	var model Zone
+
+	query := fmt.Sprintf(`SELECT %s FROM zones WHERE id = $1`, zoneColumns)
+	err := r.db.GetContext(ctx, &model, query, zoneID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, util.NewErrNotFound("zone not found")
+		}
+
+		slog.Error(
+			"Failed to GetZone",
+			slog.Any("err", err),
+			slog.Any("zoneID", zoneID),
+		)
+		return nil, util.NewErrInternalServer("failed to get zone")
+	}
+
+	return model.ToEntity(), nil
+}
+
+func (r *repository) GetZoneList(ctx context.Context, params entities.GetZoneListParams) ([]*entities.Zone, int64, error) {
+	var (
+		total          int64
+		availableSorts = []string{"name", "created_at", "updated_at"}
+		orderBy        = util.SortValidation(params.Sort, availableSorts)
+	)
+
+	queryCount := "SELECT COUNT(id) FROM zones"
+	queryData := fmt.Sprintf("SELECT %s FROM zones", zoneColumns)
+
+	args := map[string]any{}
+	whereQueries := []string{}
+	if params.Search != "" {
+		args["keyword"] = fmt.Sprintf("%%%s%%", params.Search)
+		whereQueries = append(whereQueries, "(name LIKE :keyword OR description LIKE :keyword)")
+	}
+	if params.SiteID != "" {
+		args["site_id"] = params.SiteID
+		whereQueries = append(whereQueries, "site_id = :site_id")
+	}
+	if params.OrganizationID != "" {
+		args["organization_id"] = params.OrganizationID
+		whereQueries = append(whereQueries, "site_id IN (SELECT id FROM sites WHERE organization_id = :organization_id)")
+	}
+	if len(whereQueries) > 0 {
+		whereQuery := fmt.Sprintf(" WHERE %s", strings.Join(whereQueries, " AND "))
+
+		queryCount += whereQuery
+		queryData += whereQuery
+	}
+
+	queryData += fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", orderBy, params.Limit, params.Offset)
+
+	// COUNT ROWS
+
+	stmtCount, err := r.db.PrepareNamed(queryCount)
+	if err != nil {
+		slog.Error(
+			"Failed to GetZoneList Count PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get zone list")
+	}
+	defer stmtCount.Close()
+
+	err = stmtCount.GetContext(ctx, &total, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetZoneList Count GetContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get zone list")
+	}
+
+	if total == 0 {
+		return nil, total, nil
+	}
+
+	// SELECT ROWS
+
+	stmtData, err := r.db.PrepareNamed(queryData)
+	if err != nil {
+		slog.Error(
+			"Failed to GetZoneList Data PrepareNamed",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get zone list")
+	}
+	defer stmtData.Close()
+
+	var model []Zone
+	err = stmtData.SelectContext(ctx, &model, args)
+	if err != nil {
+		slog.Error(
+			"Failed to GetZoneList Data SelectContext",
+			slog.Any("err", err),
+			slog.Any("params", params),
+		)
+		return nil, total, util.NewErrInternalServer("failed to get zone list")
+	}
+
+	zones := []*entities.Zone{}
+	for _, v := range model {
+		zones = append(zones, v.ToEntity())
+	}
+
+	return zones, total, nil
+}
@@ -3,18 +3,145 @@ package repositories
 import (
 	"context"
 	"go-api/internal/entities"
+	"time"
 )
 
 type IRepository interface {
 	CreateDevice(ctx context.Context, payload entities.Device) (string, error)
-	UpdateDevice(ctx context.Context, deviceID string, payload entities.Device) error
-	DeleteDevice(ctx context.Context, deviceID string) error
+	UpdateDevice(ctx context.Context, deviceID string, payload entities.Device, versions []int) error
+	DeleteDevice(ctx context.Context, deviceID string, cascade bool, versions []int) error
+	RestoreDevice(ctx context.Context, deviceID string) error
 	GetDevice(ctx context.Context, deviceID string) (*entities.Device, error)
 	GetDeviceList(ctx context.Context, params entities.GetDeviceListParams) ([]*entities.Device, int64, error)
+	GetDeviceIDList(ctx context.Context, params entities.GetDeviceListParams) ([]string, error)
+	UpdateDevicesStatus(ctx context.Context, deviceIDs []string, status entities.DeviceStatus) (int64, error)
+	CreateDeviceWithSensors(ctx context.Context, device entities.Device, sensors []entities.Sensor) (string, error)
+	CloneDevice(ctx context.Context, deviceID string, names []string) ([]*entities.ClonedDevice, error)
 
 	CreateSensor(ctx context.Context, payload entities.Sensor) (string, error)
-	UpdateSensor(ctx context.Context, deviceID string, payload entities.Sensor) error
-	DeleteSensor(ctx context.Context, deviceID string) error
+	UpdateSensor(ctx context.Context, sensorID string, payload entities.Sensor, versions []int) error
+	DeleteSensor(ctx context.Context, sensorID string, versions []int) error
+	RestoreSensor(ctx context.Context, sensorID string) error
 	GetSensor(ctx context.Context, deviceID string) (*entities.Sensor, error)
 	GetSensorList(ctx context.Context, params entities.GetSensorListParams) ([]*entities.Sensor, int64, error)
+	MoveSensor(ctx context.Context, sensorID, deviceID string, movedAt time.Time) error
+	GetSensorDeviceHistory(ctx context.Context, sensorID string) ([]*entities.SensorDeviceAssociation, error)
+	GetExistingSensorIDs(ctx context.Context, sensorIDs []string) ([]string, error)
+	GetVirtualSensorRefs(ctx context.Context) (map[string][]string, error)
+
+	GetTrashList(ctx context.Context, params entities.GetTrashListParams) ([]*entities.TrashItem, int64, error)
+	PurgeTrash(ctx context.Context, before time.Time) (*entities.PurgedTrash, error)
+
+	GetAuditEntryList(ctx context.Context, params entities.GetAuditEntryListParams) ([]*entities.AuditEntry, int64, error)
+
+	GetDeviceShadow(ctx context.Context, deviceID string) (*entities.DeviceShadow, error)
+	UpdateDeviceShadow(ctx context.Context, deviceID string, section entities.ShadowSection, payload entities.UpdateDeviceShadowPayload) error
+
+	CreateDeviceCommand(ctx context.Context, payload entities.DeviceCommand) (string, error)
+	GetDeviceCommand(ctx context.Context, commandID string) (*entities.DeviceCommand, error)
+	GetDeviceCommandList(ctx context.Context, params entities.GetDeviceCommandListParams) ([]*entities.DeviceCommand, int64, error)
+	DeliverDeviceCommands(ctx context.Context, deviceID string) ([]*entities.DeviceCommand, error)
+	AcknowledgeDeviceCommand(ctx context.Context, commandID string, payload entities.AckDeviceCommandPayload) error
+	ExpireDeviceCommands(ctx context.Context) ([]*entities.DeviceCommand, error)
+
+	CreateSensorReadings(ctx context.Context, payload []entities.SensorReading) ([]*entities.SensorReading, error)
+	GetSensorReadingList(ctx context.Context, params entities.GetSensorReadingListParams) ([]*entities.SensorReading, int64, error)
+	GetLatestSensorReadings(ctx context.Context, deviceIDs []string) ([]*entities.LatestSensorReading, error)
+	GetReadingAggregates(ctx context.Context, params entities.GetReadingAggregateParams) ([]*entities.ReadingAggregate, error)
+
+	CreateSensorCalibration(ctx context.Context, payload entities.SensorCalibration) (string, error)
+	UpdateSensorCalibration(ctx context.Context, calibrationID string, payload entities.SensorCalibration) error
+	DeleteSensorCalibration(ctx context.Context, calibrationID string) error
+	GetSensorCalibration(ctx context.Context, calibrationID string) (*entities.SensorCalibration, error)
+	GetSensorCalibrationList(ctx context.Context, sensorID string) ([]*entities.SensorCalibration, error)
+
+	CreateDeviceGroup(ctx context.Context, payload entities.DeviceGroup) (string, error)
+	UpdateDeviceGroup(ctx context.Context, groupID string, payload entities.DeviceGroup) error
+	DeleteDeviceGroup(ctx context.Context, groupID string) error
+	GetDeviceGroup(ctx context.Context, groupID string) (*entities.DeviceGroup, error)
+	GetDeviceGroupList(ctx context.Context, params entities.GetDeviceGroupListParams) ([]*entities.DeviceGroup, int64, error)
+	AddDeviceGroupMembers(ctx context.Context, groupID string, deviceIDs []string) error
+	RemoveDeviceGroupMember(ctx context.Context, groupID, deviceID string) error
+
+	CreateDeviceTemplate(ctx context.Context, payload entities.DeviceTemplate) (string, error)
+	UpdateDeviceTemplate(ctx context.Context, templateID string, payload entities.DeviceTemplate) error
+	DeleteDeviceTemplate(ctx context.Context, templateID string) error
+	GetDeviceTemplate(ctx context.Context, templateID string) (*entities.DeviceTemplate, error)
+	GetDeviceTemplateBySlug(ctx context.Context, slug string) (*entities.DeviceTemplate, error)
+	GetDeviceTemplateList(ctx context.Context, params entities.GetDeviceTemplateListParams) ([]*entities.DeviceTemplate, int64, error)
+
+	CreateMaintenanceWindow(ctx context.Context, payload entities.MaintenanceWindow) (string, error)
+	UpdateMaintenanceWindow(ctx context.Context, windowID string, payload entities.MaintenanceWindow) error
+	DeleteMaintenanceWindow(ctx context.Context, windowID string) error
+	GetMaintenanceWindow(ctx context.Context, windowID string) (*entities.MaintenanceWindow, error)
+	GetMaintenanceWindowList(ctx context.Context, params entities.GetMaintenanceWindowListParams) ([]*entities.MaintenanceWindow, int64, error)
+	GetDeviceMaintenanceWindows(ctx context.Context, deviceID string) ([]*entities.MaintenanceWindow, error)
+	IsDeviceInMaintenance(ctx context.Context, deviceID string, at time.Time) (bool, error)
+
+	CreateOrganization(ctx context.Context, payload entities.Organization) (string, error)
+	UpdateOrganization(ctx context.Context, organizationID string, payload entities.Organization) error
+	DeleteOrganization(ctx context.Context, organizationID string) error
+	GetOrganization(ctx context.Context, organizationID string) (*entities.Organization, error)
+	GetOrganizationList(ctx context.Context, params entities.GetOrganizationListParams) ([]*entities.Organization, int64, error)
+
+	CreateSite(ctx context.Context, payload entities.Site) (string, error)
+	UpdateSite(ctx context.Context, siteID string, payload entities.Site) error
+	DeleteSite(ctx context.Context, siteID string) error
+	GetSite(ctx context.Context, siteID string) (*entities.Site, error)
+	GetSiteList(ctx context.Context, params entities.GetSiteListParams) ([]*entities.Site, int64, error)
+
+	CreateZone(ctx context.Context, payload entities.Zone) (string, error)
+	UpdateZone(ctx context.Context, zoneID string, payload entities.Zone) error
+	DeleteZone(ctx context.Context, zoneID string) error
+	GetZone(ctx context.Context, zoneID string) (*entities.Zone, error)
+	GetZoneList(ctx context.Context, params entities.GetZoneListParams) ([]*entities.Zone, int64, error)
+
+	CreateAlertRule(ctx context.Context, payload entities.AlertRule) (string, error)
+	UpdateAlertRule(ctx context.Context, ruleID string, payload entities.AlertRule) error
+	DeleteAlertRule(ctx context.Context, ruleID string) error
+	GetAlertRule(ctx context.Context, ruleID string) (*entities.AlertRule, error)
+	GetAlertRuleList(ctx context.Context, params entities.GetAlertRuleListParams) ([]*entities.AlertRule, int64, error)
+	GetSensorAlertRules(ctx context.Context, sensorID string) ([]*entities.AlertRule, error)
+	GetEnabledAlertRules(ctx context.Context, condition entities.AlertCondition) ([]*entities.AlertRule, error)
+	GetAlertRuleSensors(ctx context.Context, rule *entities.AlertRule) ([]*entities.AlertRuleSensor, error)
+	UpdateAlert(ctx context.Context, ruleID, sensorID, deviceID string, update func(alert *entities.Alert) ([]*entities.AlertEvent, error)) (*entities.Alert, error)
+	UpdateAlertByID(ctx context.Context, alertID string, update func(alert *entities.Alert) ([]*entities.AlertEvent, error)) (*entities.Alert, error)
+	UpdateCompositeAlert(ctx context.Context, ruleID, deviceID string, update func(alert *entities.Alert) ([]*entities.AlertEvent, error)) (*entities.Alert, error)
+	GetConditionValues(ctx context.Context, sensors entities.ConditionSensors, before time.Time) ([]*entities.ConditionValue, error)
+	GetConditionValueHistory(ctx context.Context, sensors entities.ConditionSensors, from, to time.Time, limit int) ([]*entities.ConditionValue, error)
+	GetAlert(ctx context.Context, alertID string) (*entities.Alert, error)
+	GetAlertList(ctx context.Context, params entities.GetAlertListParams) ([]*entities.Alert, int64, error)
+	GetAlertEventList(ctx context.Context, params entities.GetAlertEventListParams) ([]*entities.AlertEvent, int64, error)
+
+	CreateWebhook(ctx context.Context, payload entities.Webhook) (string, error)
+	UpdateWebhook(ctx context.Context, webhookID string, payload entities.Webhook) error
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	GetWebhook(ctx context.Context, webhookID string) (*entities.Webhook, error)
+	GetWebhookList(ctx context.Context, params entities.GetWebhookListParams) ([]*entities.Webhook, int64, error)
+	GetEnabledWebhooks(ctx context.Context) ([]*entities.Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, payload entities.WebhookDelivery) (string, error)
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error)
+	CreateOutboxWebhookDeliveries(ctx context.Context, limit int, deliveries func(event *entities.OutboxEvent) []entities.WebhookDelivery) (int, error)
+	RecordWebhookAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookAttempt) error
+	GetWebhookDelivery(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error)
+	GetWebhookDeliveryList(ctx context.Context, params entities.GetWebhookDeliveryListParams) ([]*entities.WebhookDelivery, int64, error)
+
+	CreateEscalationPolicy(ctx context.Context, payload entities.EscalationPolicy) (string, error)
+	UpdateEscalationPolicy(ctx context.Context, policyID string, payload entities.EscalationPolicy) error
+	DeleteEscalationPolicy(ctx context.Context, policyID string) error
+	GetEscalationPolicy(ctx context.Context, policyID string) (*entities.EscalationPolicy, error)
+	GetEscalationPolicyList(ctx context.Context, params entities.GetEscalationPolicyListParams) ([]*entities.EscalationPolicy, int64, error)
+	GetAlertEscalation(ctx context.Context, alertID string) (*entities.AlertEscalation, error)
+	GetDueAlertEscalations(ctx context.Context, now time.Time, limit int) ([]*entities.AlertEscalation, error)
+	SaveAlertEscalations(ctx context.Context, escalations []*entities.AlertEscalation, notification *entities.EscalationNotification) error
+	GetEscalationNotificationTimes(ctx context.Context, policyID string, after time.Time) ([]time.Time, error)
+	GetLastEscalationNotificationAt(ctx context.Context, policyID, groupKey string) (*time.Time, error)
+	GetEscalationNotificationList(ctx context.Context, params entities.GetEscalationNotificationListParams) ([]*entities.EscalationNotification, int64, error)
+
+	PutAnomalyDetector(ctx context.Context, payload entities.AnomalyDetector, reset bool) error
+	DeleteAnomalyDetector(ctx context.Context, sensorID string) error
+	GetAnomalyDetector(ctx context.Context, sensorID string) (*entities.AnomalyDetector, error)
+	GetAnomalyDetectorSensorIDs(ctx context.Context, sensorIDs []string) ([]string, error)
+	UpdateAnomalyDetector(ctx context.Context, sensorID string, update func(detector *entities.AnomalyDetector) []*entities.SensorAnomaly) ([]*entities.SensorAnomaly, error)
+	GetSensorAnomalyList(ctx context.Context, params entities.GetSensorAnomalyListParams) ([]*entities.SensorAnomaly, int64, error)
 }
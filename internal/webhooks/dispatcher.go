@@ -0,0 +1,398 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/events"
+	"go-api/internal/repositories"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	SIGNATURE_HEADER = "X-Webhook-Signature"
+	EVENT_HEADER     = "X-Webhook-Event"
+	DELIVERY_HEADER  = "X-Webhook-Delivery"
+
+	// MAX_ATTEMPTS is the number of requests after which a delivery is given up as failed.
+	MAX_ATTEMPTS = 8
+)
+
+const (
+	retryBase = 30 * time.Second
+	retryMax  = 6 * time.Hour
+
+	deliveryTimeout     = 10 * time.Second
+	deliveryBatch       = 50
+	deliveryConcurrency = 10
+	outboxBatch         = 100
+
+	// deliveryLease keeps a claimed delivery from being sent twice. A batch is sent deliveryConcurrency requests
+	// at a time, the lease outlasts twice the request timeouts of the whole batch.
+	deliveryLease = 2 * deliveryBatch / deliveryConcurrency * deliveryTimeout
+
+	// maxResponseBody is the number of response bytes kept in the delivery log.
+	maxResponseBody = 1024
+)
+
+// Sign returns the signature header value of a body, the hex HMAC-SHA256 of the body keyed with the secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before the next attempt of a delivery that failed attempts times,
+// doubling from 30 seconds up to 6 hours.
+func Backoff(attempts int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMax {
+			return retryMax
+		}
+	}
+	return delay
+}
+
+// Dispatcher turns the events of the outbox into a delivery for every webhook they match and sends these with
+// retries. Events are stored with the change causing them and deliveries before they are sent, so neither is
+// lost by a restart.
+type Dispatcher struct {
+	repo   repositories.IRepository
+	client *http.Client
+}
+
+func NewDispatcher(repo repositories.IRepository, client *http.Client) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: client,
+	}
+}
+
+// Run delivers the due deliveries whenever the broker publishes an event, until ctx is cancelled. The broker
+// only wakes it up, the events come from the outbox, so an event the broker drops is delivered by the next run.
+func (d *Dispatcher) Run(ctx context.Context, broker *events.Broker) {
+	received, unsubscribe := broker.Subscribe(nil)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-received:
+		}
+
+		// one run covers every event published meanwhile
+		for len(received) > 0 {
+			<-received
+		}
+
+		err := d.DeliverDue(ctx, time.Now().UTC())
+		if err != nil {
+			slog.Error(
+				"Failed to deliver webhooks",
+				slog.Any("err", err),
+			)
+		}
+	}
+}
+
+// deliveries returns a delivery of an outbox event for every webhook whose filter accepts it, due at now.
+func deliveries(webhooks []*entities.Webhook, event *entities.OutboxEvent, now time.Time) []entities.WebhookDelivery {
+	result := []entities.WebhookDelivery{}
+
+	var payload map[string]any
+	for _, webhook := range webhooks {
+		if !subscribed(webhook, event.Type) {
+			continue
+		}
+
+		if payload == nil {
+			payload = map[string]any{
+				"type":        event.Type,
+				"resource_id": event.ResourceID,
+				"data":        event.Data,
+				"created_at":  event.CreatedAt,
+			}
+		}
+
+		result = append(result, entities.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        entities.WEBHOOK_DELIVERY_STATUS_PENDING,
+			NextAttemptAt: &now,
+		})
+	}
+
+	return result
+}
+
+// Deliver sends an event to one webhook whatever its event filter, with the retries of any delivery.
+func (d *Dispatcher) Deliver(ctx context.Context, webhook *entities.Webhook, event events.Event) error {
+	payload, err := eventPayload(event)
+	if err != nil {
+		return err
+	}
+
+	return d.deliver(ctx, webhook, event.Type, payload)
+}
+
+// deliver records a delivery and makes its first attempt in the background.
+func (d *Dispatcher) deliver(ctx context.Context, webhook *entities.Webhook, eventType string, payload map[string]any) error {
+	var err error
+
+	next := time.Now().UTC().Add(deliveryLease)
+	delivery := &entities.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        entities.WEBHOOK_DELIVERY_STATUS_PENDING,
+		NextAttemptAt: &next,
+	}
+	delivery.ID, err = d.repo.CreateWebhookDelivery(ctx, *delivery)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		err := d.send(ctx, webhook, delivery)
+		if err != nil {
+			slog.Error(
+				"Failed to send webhook delivery",
+				slog.Any("err", err),
+				slog.String("deliveryID", delivery.ID),
+			)
+		}
+	}()
+
+	return nil
+}
+
+// DeliverDue turns the events of the outbox into deliveries, then sends the pending deliveries of enabled webhooks
+// whose next attempt is due, deliveryConcurrency at a time.
+func (d *Dispatcher) DeliverDue(ctx context.Context, now time.Time) error {
+	err := d.createOutboxDeliveries(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	due, err := d.repo.ClaimWebhookDeliveries(ctx, now, deliveryLease, deliveryBatch)
+	if err != nil {
+		return err
+	}
+
+	webhooks := map[string]*entities.Webhook{}
+	for _, delivery := range due {
+		if _, ok := webhooks[delivery.WebhookID]; ok {
+			continue
+		}
+		webhooks[delivery.WebhookID], err = d.repo.GetWebhook(ctx, delivery.WebhookID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, deliveryConcurrency)
+	for _, delivery := range due {
+		webhook := webhooks[delivery.WebhookID]
+		// a webhook disabled since the claim keeps its deliveries pending until it is enabled again
+		if !webhook.Enabled {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery *entities.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := d.send(ctx, webhook, delivery)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// createOutboxDeliveries empties the outbox, storing the deliveries of its events to the enabled webhooks.
+func (d *Dispatcher) createOutboxDeliveries(ctx context.Context, now time.Time) error {
+	webhooks, err := d.repo.GetEnabledWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		taken, err := d.repo.CreateOutboxWebhookDeliveries(ctx, outboxBatch, func(event *entities.OutboxEvent) []entities.WebhookDelivery {
+			return deliveries(webhooks, event, now)
+		})
+		if err != nil {
+			return err
+		}
+		if taken < outboxBatch {
+			return nil
+		}
+	}
+}
+
+// Redeliver sends the event of a delivery again as a new delivery, which is retried like any other.
+func (d *Dispatcher) Redeliver(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+	original, err := d.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := d.repo.GetWebhook(ctx, original.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := time.Now().UTC().Add(deliveryLease)
+	delivery := &entities.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        entities.WEBHOOK_DELIVERY_STATUS_PENDING,
+		NextAttemptAt: &next,
+		RedeliveryOf:  &original.ID,
+	}
+	delivery.ID, err = d.repo.CreateWebhookDelivery(ctx, *delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.send(ctx, webhook, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.repo.GetWebhookDelivery(ctx, delivery.ID)
+}
+
+// send makes one attempt of a delivery and records it. A failed request is not an error, the delivery
+// is scheduled for a retry, or marked as failed after MAX_ATTEMPTS.
+func (d *Dispatcher) send(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) error {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	start := time.Now()
+	attempt := entities.WebhookAttempt{
+		DeliveryID: delivery.ID,
+	}
+
+	statusCode, responseBody, err := d.post(reqCtx, webhook, delivery, body)
+	if err != nil {
+		attempt.Error = err.Error()
+	} else {
+		attempt.StatusCode = &statusCode
+		attempt.ResponseBody = responseBody
+	}
+
+	nowUTC := time.Now().UTC()
+	attempt.CreatedAt = nowUTC
+	attempt.DurationMs = int(time.Since(start).Milliseconds())
+
+	delivery.Attempts++
+	delivery.LastStatusCode = attempt.StatusCode
+	switch {
+	case err == nil && statusCode >= 200 && statusCode < 300:
+		delivery.Status = entities.WEBHOOK_DELIVERY_STATUS_SUCCEEDED
+		delivery.DeliveredAt = &nowUTC
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	default:
+		delivery.LastError = attempt.Error
+		if err == nil {
+			delivery.LastError = fmt.Sprintf("unexpected status code %d", statusCode)
+		}
+
+		next := nowUTC.Add(Backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		if delivery.Attempts >= MAX_ATTEMPTS {
+			delivery.Status = entities.WEBHOOK_DELIVERY_STATUS_FAILED
+			delivery.NextAttemptAt = nil
+		}
+	}
+
+	return d.repo.RecordWebhookAttempt(ctx, *delivery, attempt)
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EVENT_HEADER, delivery.EventType)
+	req.Header.Set(DELIVERY_HEADER, delivery.ID)
+	req.Header.Set(SIGNATURE_HEADER, Sign(webhook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return resp.StatusCode, string(responseBody), nil
+}
+
+// subscribed reports whether the webhook's event filter accepts the event type, an empty filter accepts all.
+func subscribed(webhook *entities.Webhook, eventType string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, pattern := range webhook.Events {
+		if events.Matches(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventPayload converts an event to the JSON object stored with its deliveries and sent as the request body.
+func eventPayload(event events.Event) (map[string]any, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]any{}
+	err = json.Unmarshal(data, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,450 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-api/internal/entities"
+	"go-api/internal/repositories"
+	"go-api/pkg/util"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRepository keeps webhooks and deliveries in memory, the other methods of the interface are not used
+// by the dispatcher and panic.
+type fakeRepository struct {
+	repositories.IRepository
+
+	mu         sync.Mutex
+	webhooks   map[string]*entities.Webhook
+	outbox     []*entities.OutboxEvent
+	deliveries map[string]*entities.WebhookDelivery
+	attempts   []entities.WebhookAttempt
+	claims     int
+}
+
+func newFakeRepository(webhooks ...*entities.Webhook) *fakeRepository {
+	repo := &fakeRepository{
+		webhooks:   map[string]*entities.Webhook{},
+		deliveries: map[string]*entities.WebhookDelivery{},
+	}
+	for _, webhook := range webhooks {
+		repo.webhooks[webhook.ID] = webhook
+	}
+	return repo
+}
+
+func (r *fakeRepository) GetWebhook(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[webhookID]
+	if !ok {
+		return nil, util.NewErrNotFound("webhook not found")
+	}
+	return webhook, nil
+}
+
+func (r *fakeRepository) GetEnabledWebhooks(ctx context.Context) ([]*entities.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhooks := []*entities.Webhook{}
+	for _, webhook := range r.webhooks {
+		if webhook.Enabled {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (r *fakeRepository) CreateOutboxWebhookDeliveries(ctx context.Context, limit int, deliveries func(event *entities.OutboxEvent) []entities.WebhookDelivery) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	taken := r.outbox[:min(limit, len(r.outbox))]
+	r.outbox = r.outbox[len(taken):]
+	for _, event := range taken {
+		for _, delivery := range deliveries(event) {
+			delivery.ID = fmt.Sprintf("delivery-%d", len(r.deliveries)+1)
+			r.deliveries[delivery.ID] = &delivery
+		}
+	}
+	return len(taken), nil
+}
+
+func (r *fakeRepository) CreateWebhookDelivery(ctx context.Context, payload entities.WebhookDelivery) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payload.ID = fmt.Sprintf("delivery-%d", len(r.deliveries)+1)
+	r.deliveries[payload.ID] = &payload
+	return payload.ID, nil
+}
+
+// ClaimWebhookDeliveries claims up to limit pending deliveries of enabled webhooks whatever their next attempt.
+func (r *fakeRepository) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.claims++
+	claimed := []*entities.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		webhook, ok := r.webhooks[delivery.WebhookID]
+		if !ok || !webhook.Enabled {
+			continue
+		}
+		if delivery.Status == entities.WEBHOOK_DELIVERY_STATUS_PENDING && len(claimed) < limit {
+			copied := *delivery
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *fakeRepository) RecordWebhookAttempt(ctx context.Context, delivery entities.WebhookDelivery, attempt entities.WebhookAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deliveries[delivery.ID] = &delivery
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *fakeRepository) GetWebhookDelivery(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery, ok := r.deliveries[deliveryID]
+	if !ok {
+		return nil, util.NewErrNotFound("webhook delivery not found")
+	}
+	copied := *delivery
+	return &copied, nil
+}
+
+func (r *fakeRepository) delivery(deliveryID string) entities.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return *r.deliveries[deliveryID]
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts a receiver answering every request with status and recording what it received.
+func newReceiver(t *testing.T, status int) (*httptest.Server, func() []receivedRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		received []receivedRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		received = append(received, receivedRequest{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+		fmt.Fprint(w, "received")
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedRequest{}, received...)
+	}
+}
+
+func pendingDelivery(repo *fakeRepository, webhookID string) string {
+	deliveryID, _ := repo.CreateWebhookDelivery(context.Background(), entities.WebhookDelivery{
+		WebhookID: webhookID,
+		EventType: "device.created",
+		Payload:   map[string]any{"type": "device.created", "resource_id": "device-1"},
+		Status:    entities.WEBHOOK_DELIVERY_STATUS_PENDING,
+	})
+	return deliveryID
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	server, received := newReceiver(t, http.StatusOK)
+	webhook := &entities.Webhook{ID: "webhook-1", URL: server.URL, Secret: "s3cret", Enabled: true}
+	repo := newFakeRepository(webhook)
+	deliveryID := pendingDelivery(repo, webhook.ID)
+
+	err := NewDispatcher(repo, server.Client()).DeliverDue(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+
+	requests := received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+
+	if got, want := req.header.Get(SIGNATURE_HEADER), Sign(webhook.Secret, req.body); got != want {
+		t.Errorf("%s = %q, want %q", SIGNATURE_HEADER, got, want)
+	}
+	if got := req.header.Get(EVENT_HEADER); got != "device.created" {
+		t.Errorf("%s = %q, want %q", EVENT_HEADER, got, "device.created")
+	}
+	if got := req.header.Get(DELIVERY_HEADER); got != deliveryID {
+		t.Errorf("%s = %q, want %q", DELIVERY_HEADER, got, deliveryID)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(req.body, &payload); err != nil || payload["resource_id"] != "device-1" {
+		t.Errorf("body = %s, want the delivery payload", req.body)
+	}
+
+	delivery := repo.delivery(deliveryID)
+	if delivery.Status != entities.WEBHOOK_DELIVERY_STATUS_SUCCEEDED || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Errorf("delivery = %+v, want succeeded", delivery)
+	}
+}
+
+func TestDeliverDueEmptiesOutbox(t *testing.T) {
+	server, received := newReceiver(t, http.StatusOK)
+	all := &entities.Webhook{ID: "webhook-1", URL: server.URL, Secret: "s3cret", Enabled: true}
+	alerts := &entities.Webhook{ID: "webhook-2", URL: server.URL, Secret: "s3cret", Events: []string{"alert.*"}, Enabled: true}
+	disabled := &entities.Webhook{ID: "webhook-3", URL: server.URL, Secret: "s3cret"}
+	repo := newFakeRepository(all, alerts, disabled)
+
+	// more events than one outbox batch
+	for i := 0; i < outboxBatch+1; i++ {
+		repo.outbox = append(repo.outbox, &entities.OutboxEvent{
+			ID:         int64(i + 1),
+			Type:       "device.shadow.updated",
+			ResourceID: "device-1",
+			Data:       map[string]any{"version": float64(i + 1)},
+		})
+	}
+	repo.outbox = append(repo.outbox, &entities.OutboxEvent{ID: outboxBatch + 2, Type: "alert.firing", ResourceID: "alert-1"})
+
+	err := NewDispatcher(repo, server.Client()).DeliverDue(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+
+	if len(repo.outbox) != 0 {
+		t.Errorf("outbox has %d events left, want none", len(repo.outbox))
+	}
+
+	perWebhook := map[string]int{}
+	for _, delivery := range repo.deliveries {
+		perWebhook[delivery.WebhookID]++
+		if delivery.EventType == "alert.firing" && delivery.Payload["resource_id"] != "alert-1" {
+			t.Errorf("payload = %v, want the alert event", delivery.Payload)
+		}
+	}
+	want := map[string]int{all.ID: outboxBatch + 2, alerts.ID: 1}
+	for webhookID, n := range want {
+		if perWebhook[webhookID] != n {
+			t.Errorf("%s has %d deliveries, want %d", webhookID, perWebhook[webhookID], n)
+		}
+	}
+	if perWebhook[disabled.ID] != 0 {
+		t.Errorf("disabled webhook has %d deliveries, want none", perWebhook[disabled.ID])
+	}
+
+	if got := len(received()); got != deliveryBatch {
+		t.Errorf("received %d requests, want the first %d due", got, deliveryBatch)
+	}
+}
+
+func TestDeliverDueRetriesUntilFailed(t *testing.T) {
+	server, received := newReceiver(t, http.StatusInternalServerError)
+	webhook := &entities.Webhook{ID: "webhook-1", URL: server.URL, Secret: "s3cret", Enabled: true}
+	repo := newFakeRepository(webhook)
+	deliveryID := pendingDelivery(repo, webhook.ID)
+	dispatcher := NewDispatcher(repo, server.Client())
+
+	for attempts := 1; attempts <= MAX_ATTEMPTS; attempts++ {
+		err := dispatcher.DeliverDue(context.Background(), time.Now())
+		if err != nil {
+			t.Fatalf("DeliverDue() error = %v", err)
+		}
+
+		delivery := repo.delivery(deliveryID)
+		attempt := repo.attempts[len(repo.attempts)-1]
+		if delivery.Attempts != attempts || len(repo.attempts) != attempts {
+			t.Fatalf("after %d tries: attempts = %d, recorded = %d", attempts, delivery.Attempts, len(repo.attempts))
+		}
+		if attempt.StatusCode == nil || *attempt.StatusCode != http.StatusInternalServerError || attempt.ResponseBody != "received" {
+			t.Errorf("attempt %d = %+v, want the 500 response", attempts, attempt)
+		}
+		if delivery.LastError != "unexpected status code 500" {
+			t.Errorf("attempt %d: last error = %q", attempts, delivery.LastError)
+		}
+
+		if attempts < MAX_ATTEMPTS {
+			if delivery.Status != entities.WEBHOOK_DELIVERY_STATUS_PENDING {
+				t.Fatalf("attempt %d: status = %s, want pending", attempts, delivery.Status)
+			}
+			want := attempt.CreatedAt.Add(Backoff(attempts))
+			if delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.Equal(want) {
+				t.Errorf("attempt %d: next attempt = %v, want %v", attempts, delivery.NextAttemptAt, want)
+			}
+		} else {
+			if delivery.Status != entities.WEBHOOK_DELIVERY_STATUS_FAILED || delivery.NextAttemptAt != nil {
+				t.Errorf("attempt %d: delivery = %+v, want failed without a next attempt", attempts, delivery)
+			}
+		}
+	}
+
+	// a failed delivery is not claimed again
+	err := dispatcher.DeliverDue(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if got := len(received()); got != MAX_ATTEMPTS {
+		t.Errorf("received %d requests, want %d", got, MAX_ATTEMPTS)
+	}
+}
+
+func TestDeliverDueParksDisabledWebhooks(t *testing.T) {
+	server, received := newReceiver(t, http.StatusOK)
+	webhook := &entities.Webhook{ID: "webhook-1", URL: server.URL, Secret: "s3cret"}
+	repo := newFakeRepository(webhook)
+	deliveryID := pendingDelivery(repo, webhook.ID)
+	dispatcher := NewDispatcher(repo, server.Client())
+
+	err := dispatcher.DeliverDue(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if got := len(received()); got != 0 {
+		t.Fatalf("received %d requests for a disabled webhook, want none", got)
+	}
+	if got := repo.delivery(deliveryID); got.Status != entities.WEBHOOK_DELIVERY_STATUS_PENDING || got.Attempts != 0 {
+		t.Errorf("delivery = %+v, want pending without attempts", got)
+	}
+
+	webhook.Enabled = true
+	err = dispatcher.DeliverDue(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if got := repo.delivery(deliveryID); got.Status != entities.WEBHOOK_DELIVERY_STATUS_SUCCEEDED {
+		t.Errorf("delivery = %+v, want succeeded once enabled", got)
+	}
+}
+
+func TestDeliverDueSendsConcurrently(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+		once     sync.Once
+	)
+	// requests are held until deliveryConcurrency of them are in flight, or a second when they are not sent
+	// concurrently
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		if inFlight == deliveryConcurrency {
+			once.Do(func() { close(release) })
+		}
+		mu.Unlock()
+
+		select {
+		case <-release:
+		case <-time.After(time.Second):
+		}
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	webhook := &entities.Webhook{ID: "webhook-1", URL: server.URL, Secret: "s3cret", Enabled: true}
+	repo := newFakeRepository(webhook)
+	for i := 0; i < deliveryBatch; i++ {
+		pendingDelivery(repo, webhook.ID)
+	}
+
+	err := NewDispatcher(repo, server.Client()).DeliverDue(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+
+	if peak != deliveryConcurrency {
+		t.Errorf("peak concurrent requests = %d, want %d", peak, deliveryConcurrency)
+	}
+	if len(repo.attempts) != deliveryBatch {
+		t.Errorf("recorded %d attempts, want %d", len(repo.attempts), deliveryBatch)
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	server, received := newReceiver(t, http.StatusNoContent)
+	webhook := &entities.Webhook{ID: "webhook-1", URL: server.URL, Secret: "s3cret", Enabled: true}
+	repo := newFakeRepository(webhook)
+
+	originalID := pendingDelivery(repo, webhook.ID)
+	original := repo.delivery(originalID)
+	original.Status = entities.WEBHOOK_DELIVERY_STATUS_FAILED
+	original.Attempts = MAX_ATTEMPTS
+	repo.deliveries[originalID] = &original
+
+	delivery, err := NewDispatcher(repo, server.Client()).Redeliver(context.Background(), originalID)
+	if err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+
+	if delivery.ID == originalID {
+		t.Fatalf("Redeliver() reused the delivery %s", originalID)
+	}
+	if delivery.RedeliveryOf == nil || *delivery.RedeliveryOf != originalID {
+		t.Errorf("redelivery of = %v, want %s", delivery.RedeliveryOf, originalID)
+	}
+	if delivery.Status != entities.WEBHOOK_DELIVERY_STATUS_SUCCEEDED || delivery.Attempts != 1 {
+		t.Errorf("delivery = %+v, want succeeded at the first attempt", delivery)
+	}
+	if got := repo.delivery(originalID); got.Status != entities.WEBHOOK_DELIVERY_STATUS_FAILED || got.Attempts != MAX_ATTEMPTS {
+		t.Errorf("original = %+v, want it unchanged", got)
+	}
+
+	requests := received()
+	if len(requests) != 1 || requests[0].header.Get(DELIVERY_HEADER) != delivery.ID {
+		t.Errorf("received %d requests, want 1 for the new delivery", len(requests))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{8, 64 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{50, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package workers
+
+import (
+	"context"
+	"go-api/internal/alerting"
+	"time"
+)
+
+// EvaluateAlerts fires the alerts of no data rules for sensors that stopped sending readings and evaluates
+// the composite rules with the latest readings of their sensors.
+func EvaluateAlerts(evaluator *alerting.Evaluator) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+
+		err := evaluator.EvaluateNoData(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		return evaluator.EvaluateComposite(ctx, now)
+	}
+}
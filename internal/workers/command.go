@@ -0,0 +1,27 @@
+package workers
+
+import (
+	"context"
+	"go-api/internal/events"
+	"go-api/internal/repositories"
+)
+
+// ExpireDeviceCommands marks commands past their TTL as expired and notifies subscribers.
+func ExpireDeviceCommands(repo repositories.IRepository, broker *events.Broker) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		commands, err := repo.ExpireDeviceCommands(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range commands {
+			broker.Publish(events.Event{
+				Type:       events.DEVICE_COMMAND_EXPIRED,
+				ResourceID: c.DeviceID,
+				Data:       c,
+			})
+		}
+
+		return nil
+	}
+}
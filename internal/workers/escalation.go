@@ -0,0 +1,14 @@
+package workers
+
+import (
+	"context"
+	"go-api/internal/escalation"
+	"time"
+)
+
+// EscalateAlerts notifies the escalation steps that are due.
+func EscalateAlerts(escalator *escalation.Escalator) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return escalator.NotifyDue(ctx, time.Now().UTC())
+	}
+}
@@ -0,0 +1,28 @@
+package workers
+
+import (
+	"context"
+	"go-api/internal/notify"
+	"time"
+)
+
+// SendAlertReport emails the alert report once a day, on the first run in the given UTC hour.
+func SendAlertReport(notifier *notify.EmailNotifier, to []string, hour int) func(ctx context.Context) error {
+	var lastSent string
+
+	return func(ctx context.Context) error {
+		now := time.Now().UTC()
+		today := now.Format("2006-01-02")
+		if now.Hour() != hour || lastSent == today {
+			return nil
+		}
+
+		err := notifier.SendReport(ctx, to, now)
+		if err != nil {
+			return err
+		}
+		lastSent = today
+
+		return nil
+	}
+}
@@ -0,0 +1,24 @@
+package workers
+
+import (
+	"context"
+	"go-api/internal/repositories"
+	"log/slog"
+	"time"
+)
+
+// PurgeTrash deletes for good the devices and sensors that have been in the trash longer than retention.
+func PurgeTrash(repo repositories.IRepository, retention time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		purged, err := repo.PurgeTrash(ctx, time.Now().UTC().Add(-retention))
+		if err != nil {
+			return err
+		}
+
+		if purged.Devices > 0 || purged.Sensors > 0 {
+			slog.Info("Purged trash", slog.Int64("devices", purged.Devices), slog.Int64("sensors", purged.Sensors))
+		}
+
+		return nil
+	}
+}
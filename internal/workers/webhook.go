@@ -0,0 +1,14 @@
+package workers
+
+import (
+	"context"
+	"go-api/internal/webhooks"
+	"time"
+)
+
+// DeliverWebhooks retries the webhook deliveries whose next attempt is due.
+func DeliverWebhooks(dispatcher *webhooks.Dispatcher) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return dispatcher.DeliverDue(ctx, time.Now().UTC())
+	}
+}
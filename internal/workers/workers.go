@@ -0,0 +1,29 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Every runs fn right away and then at every interval until ctx is cancelled.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(ctx); err != nil {
+			slog.Error(
+				"Worker run failed",
+				slog.String("worker", name),
+				slog.Any("err", err),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package condition
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MAX_CONDITION_LENGTH keeps conditions small enough to evaluate for every device every minute.
+const MAX_CONDITION_LENGTH = 1000
+
+type RefKind string
+
+var (
+	REF_SENSOR_ID   RefKind = "id"
+	REF_SENSOR_NAME RefKind = "name"
+	REF_SENSOR_TYPE RefKind = "type"
+)
+
+// Ref selects the sensors an operand stands for, by ID, by name (case-insensitive) or by type.
+type Ref struct {
+	Kind  RefKind
+	Value string
+}
+
+func (r Ref) String() string {
+	switch r.Kind {
+	case REF_SENSOR_ID:
+		return "{" + r.Value + "}"
+	case REF_SENSOR_TYPE:
+		return "type:" + r.Value
+	}
+	return strconv.Quote(r.Value)
+}
+
+var (
+	refRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	keywords = map[string]bool{
+		"and": true, "or": true, "not": true, "for": true, "on": true, "the": true, "same": true, "device": true,
+	}
+)
+
+// Condition is a parsed composite condition:
+//
+//	condition  := or ("for" duration)? ("on" "the"? "same" "device")?
+//	or         := and ("OR" and)*
+//	and        := unary ("AND" unary)*
+//	unary      := "NOT" unary | "(" or ")" | comparison
+//	comparison := operand ("<" | "<=" | ">" | ">=" | "==" | "=" | "!=") number
+//	operand    := name | "quoted name" | type:name | {sensor-uuid}
+//
+// Keywords are case-insensitive, a sensor named like a keyword is quoted. The clauses at the end may come
+// in any order, e.g. `soil_moisture < 20 AND type:temperature > 30 for 15m on the same device`.
+type Condition struct {
+	source     string
+	root       node
+	refs       []Ref
+	For        time.Duration
+	SameDevice bool
+}
+
+func Parse(source string) (*Condition, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, errors.New("condition is empty")
+	}
+	if len(source) > MAX_CONDITION_LENGTH {
+		return nil, fmt.Errorf("condition is longer than %d characters", MAX_CONDITION_LENGTH)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Condition{source: source, root: root, refs: p.refs}
+	err = p.parseClauses(c)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+	}
+
+	return c, nil
+}
+
+func (c *Condition) String() string {
+	return c.source
+}
+
+// Refs returns the operands once each, in order of appearance.
+func (c *Condition) Refs() []Ref {
+	refs := []Ref{}
+	seen := map[Ref]bool{}
+	for _, r := range c.refs {
+		if !seen[r] {
+			seen[r] = true
+			refs = append(refs, r)
+		}
+	}
+
+	return refs
+}
+
+// Eval evaluates the condition with the values of the sensors every operand stands for. A comparison holds
+// when the value of any of its sensors satisfies it, an operand without values satisfies none.
+func (c *Condition) Eval(values map[Ref][]float64) bool {
+	return c.root.eval(values)
+}
+
+// TOKENS
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDuration
+	tokenIdent
+	tokenName
+	tokenRef
+	tokenComparator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	value    float64
+	duration time.Duration
+	pos      int
+}
+
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+
+	i := 0
+	for i < len(source) {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '{':
+			end := strings.IndexByte(source[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed sensor reference at position %d", i+1)
+			}
+			id := strings.TrimSpace(source[i+1 : i+end])
+			if !refRegex.MatchString(id) {
+				return nil, fmt.Errorf("invalid sensor reference %q at position %d", id, i+1)
+			}
+			tokens = append(tokens, token{kind: tokenRef, text: strings.ToLower(id), pos: i})
+			i += end + 1
+		case c == '"':
+			end := strings.IndexByte(source[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed sensor name at position %d", i+1)
+			}
+			name := source[i+1 : i+1+end]
+			if strings.TrimSpace(name) == "" {
+				return nil, fmt.Errorf("empty sensor name at position %d", i+1)
+			}
+			tokens = append(tokens, token{kind: tokenName, text: name, pos: i})
+			i += end + 2
+		case isDigit(c) || c == '.' || c == '-':
+			start := i
+			i++
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			// a number followed by a unit is a duration, such as 15m or 1h30m
+			if i < len(source) && isLetter(source[i]) {
+				for i < len(source) && (isLetter(source[i]) || isDigit(source[i])) {
+					i++
+				}
+				d, err := time.ParseDuration(source[start:i])
+				if err != nil || d <= 0 {
+					return nil, fmt.Errorf("invalid duration %q at position %d", source[start:i], start+1)
+				}
+				tokens = append(tokens, token{kind: tokenDuration, text: source[start:i], duration: d, pos: start})
+				continue
+			}
+			value, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", source[start:i], start+1)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], value: value, pos: start})
+		case isLetter(c):
+			start := i
+			for i < len(source) && (isLetter(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start})
+		case strings.IndexByte("<>=!", c) >= 0:
+			start := i
+			i++
+			if i < len(source) && source[i] == '=' {
+				i++
+			}
+			op := source[start:i]
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected %q at position %d", c, start+1)
+			}
+			if op == "=" {
+				op = "=="
+			}
+			tokens = append(tokens, token{kind: tokenComparator, text: op, pos: start})
+		case c == ':':
+			tokens = append(tokens, token{kind: tokenIdent, text: ":", pos: i})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, i+1)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, text: "end of condition", pos: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// PARSER
+
+type parser struct {
+	tokens []token
+	pos    int
+	refs   []Ref
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword reports whether the next token is the keyword, keywords are case-insensitive.
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.isKeyword(keyword) {
+		t := p.peek()
+		return fmt.Errorf("expected %q at position %d", keyword, t.pos+1)
+	}
+	p.next()
+	return nil
+}
+
+// parseOr parses or := and ("OR" and)*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses and := unary ("AND" unary)*
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseUnary parses unary := "NOT" unary | "(" or ")" | comparison
+func (p *parser) parseUnary() (node, error) {
+	if p.isKeyword("not") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d", closing.pos+1)
+		}
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison parses comparison := operand comparator number
+func (p *parser) parseComparison() (node, error) {
+	ref, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.next()
+	if op.kind != tokenComparator {
+		return nil, fmt.Errorf("expected a comparison after %s at position %d", ref, op.pos+1)
+	}
+
+	number := p.next()
+	if number.kind != tokenNumber {
+		return nil, fmt.Errorf("expected a number after %q at position %d", op.text, number.pos+1)
+	}
+
+	p.refs = append(p.refs, ref)
+	return comparisonNode{ref: ref, op: op.text, value: number.value}, nil
+}
+
+// parseOperand parses operand := name | "quoted name" | type:name | {sensor-uuid}
+func (p *parser) parseOperand() (Ref, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenRef:
+		return Ref{Kind: REF_SENSOR_ID, Value: t.text}, nil
+	case tokenName:
+		return Ref{Kind: REF_SENSOR_NAME, Value: strings.ToLower(t.text)}, nil
+	case tokenIdent:
+		if keywords[strings.ToLower(t.text)] {
+			return Ref{}, fmt.Errorf("unexpected %q at position %d, quote a sensor with this name", t.text, t.pos+1)
+		}
+		if strings.EqualFold(t.text, "type") && p.peek().text == ":" {
+			p.next()
+			name := p.next()
+			if name.kind != tokenIdent || name.text == ":" {
+				return Ref{}, fmt.Errorf("expected a sensor type at position %d", name.pos+1)
+			}
+			return Ref{Kind: REF_SENSOR_TYPE, Value: strings.ToLower(name.text)}, nil
+		}
+		return Ref{Kind: REF_SENSOR_NAME, Value: strings.ToLower(t.text)}, nil
+	}
+
+	return Ref{}, fmt.Errorf("expected a sensor at position %d, got %q", t.pos+1, t.text)
+}
+
+// parseClauses parses the "for" and "on the same device" clauses following the condition.
+func (p *parser) parseClauses(c *Condition) error {
+	seenFor, seenScope := false, false
+	for {
+		switch {
+		case p.isKeyword("for") && !seenFor:
+			p.next()
+			t := p.next()
+			if t.kind != tokenDuration {
+				return fmt.Errorf("expected a duration such as 15m after \"for\" at position %d", t.pos+1)
+			}
+			c.For = t.duration
+			seenFor = true
+		case p.isKeyword("on") && !seenScope:
+			p.next()
+			if p.isKeyword("the") {
+				p.next()
+			}
+			if err := p.expectKeyword("same"); err != nil {
+				return err
+			}
+			if err := p.expectKeyword("device"); err != nil {
+				return err
+			}
+			c.SameDevice = true
+			seenScope = true
+		default:
+			return nil
+		}
+	}
+}
+
+// NODES
+
+type node interface {
+	eval(values map[Ref][]float64) bool
+}
+
+type comparisonNode struct {
+	ref   Ref
+	op    string
+	value float64
+}
+
+func (n comparisonNode) eval(values map[Ref][]float64) bool {
+	for _, v := range values[n.ref] {
+		if compare(v, n.op, n.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(v float64, op string, value float64) bool {
+	switch op {
+	case "<":
+		return v < value
+	case "<=":
+		return v <= value
+	case ">":
+		return v > value
+	case ">=":
+		return v >= value
+	case "==":
+		return v == value
+	case "!=":
+		return v != value
+	}
+	return false
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) eval(values map[Ref][]float64) bool {
+	return n.left.eval(values) && n.right.eval(values)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) eval(values map[Ref][]float64) bool {
+	return n.left.eval(values) || n.right.eval(values)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) eval(values map[Ref][]float64) bool {
+	return !n.operand.eval(values)
+}
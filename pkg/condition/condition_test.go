@@ -0,0 +1,197 @@
+package condition
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sensorID = "6f1c2a3e-0b4d-4c5e-8f90-a1b2c3d4e5f6"
+
+func TestEval(t *testing.T) {
+	// a, b and c hold, x and y do not
+	values := map[Ref][]float64{
+		{Kind: REF_SENSOR_NAME, Value: "a"}: {1},
+		{Kind: REF_SENSOR_NAME, Value: "b"}: {1},
+		{Kind: REF_SENSOR_NAME, Value: "c"}: {1},
+		{Kind: REF_SENSOR_NAME, Value: "x"}: {0},
+		{Kind: REF_SENSOR_NAME, Value: "y"}: {0},
+	}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"a == 1", true},
+		{"x == 1", false},
+		// AND binds tighter than OR
+		{"a == 1 OR x == 1 AND y == 1", true},
+		{"x == 1 AND y == 1 OR a == 1", true},
+		{"(a == 1 OR x == 1) AND y == 1", false},
+		{"x == 1 AND (y == 1 OR a == 1)", false},
+		// NOT binds tighter than AND and OR
+		{"NOT x == 1 AND a == 1", true},
+		{"NOT a == 1 AND x == 1", false},
+		{"NOT a == 1 OR b == 1", true},
+		{"NOT (a == 1 OR b == 1)", false},
+		{"NOT NOT a == 1", true},
+		{"NOT (x == 1) AND NOT (y == 1)", true},
+		{"((a == 1) AND ((b == 1) OR x == 1)) AND c == 1", true},
+		{"a == 1 and not x == 1 or y == 1", true},
+		// an operand without values satisfies no comparison
+		{"unknown < 100", false},
+		{"unknown != 0", false},
+		{"NOT unknown > 0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			c, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := c.Eval(values); got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalComparisons(t *testing.T) {
+	temperature := Ref{Kind: REF_SENSOR_TYPE, Value: "temperature"}
+	values := map[Ref][]float64{temperature: {18, 31.5}}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"type:temperature > 30", true},
+		{"type:temperature >= 31.5", true},
+		{"type:temperature > 40", false},
+		{"type:temperature < 20", true},
+		{"type:temperature <= 17.9", false},
+		{"type:temperature = 18", true},
+		{"type:temperature == 25", false},
+		{"type:temperature != 18", true},
+		{"type:temperature > -5", true},
+		{"TYPE:Temperature > 30", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			c, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := c.Eval(values); got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClauses(t *testing.T) {
+	tests := []struct {
+		source   string
+		wantFor  time.Duration
+		wantSame bool
+	}{
+		{"a < 1", 0, false},
+		{"a < 1 for 15m", 15 * time.Minute, false},
+		{"a < 1 FOR 1h30m", 90 * time.Minute, false},
+		{"a < 1 on the same device", 0, true},
+		{"a < 1 on same device for 30s", 30 * time.Second, true},
+		{"a < 1 for 5m ON THE SAME DEVICE", 5 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			c, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if c.For != tt.wantFor || c.SameDevice != tt.wantSame {
+				t.Errorf("Parse() for = %v, same device = %v, want %v, %v", c.For, c.SameDevice, tt.wantFor, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestRefs(t *testing.T) {
+	c, err := Parse(`Soil_Moisture < 20 AND type:Temperature > 30 OR {` + strings.ToUpper(sensorID) + `} > 1 OR "and" < 2 OR soil_moisture > 80`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Ref{
+		{Kind: REF_SENSOR_NAME, Value: "soil_moisture"},
+		{Kind: REF_SENSOR_TYPE, Value: "temperature"},
+		{Kind: REF_SENSOR_ID, Value: sensorID},
+		{Kind: REF_SENSOR_NAME, Value: "and"},
+	}
+	if got := c.Refs(); !slices.Equal(got, want) {
+		t.Errorf("Refs() = %v, want %v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"", "condition is empty"},
+		{"  \t", "condition is empty"},
+		{strings.Repeat("a < 1 OR ", 111) + "a < 1", "condition is longer than 1000 characters"},
+		// malformed
+		{"a <", `expected a number after "<" at position 4`},
+		{"a 1", "expected a comparison after \"a\" at position 3"},
+		{"a < b", `expected a number after "<" at position 5`},
+		{"a < 1 AND", `expected a sensor at position 10, got "end of condition"`},
+		{"a < 1 b < 2", `unexpected "b" at position 7`},
+		{"(a < 1", `expected ")" at position 7`},
+		{"a < 1)", `unexpected ")" at position 6`},
+		{"()", `expected a sensor at position 2, got ")"`},
+		{"NOT", `expected a sensor at position 4, got "end of condition"`},
+		{"a ! 1", `unexpected '!' at position 3`},
+		{"a < 1 & b < 2", `unexpected '&' at position 7`},
+		{"a < 1..2", `invalid number "1..2" at position 5`},
+		{`"a < 1`, "unclosed sensor name at position 1"},
+		{`"  " < 1`, "empty sensor name at position 1"},
+		{"a < 1 for", `expected a duration such as 15m after "for" at position 10`},
+		{"a < 1 for 15", `expected a duration such as 15m after "for" at position 11`},
+		{"a < 1 for 15x", `invalid duration "15x" at position 11`},
+		{"a < 1 for -5m", `invalid duration "-5m" at position 11`},
+		{"a < 1 for 5m for 10m", `unexpected "for" at position 14`},
+		{"a < 1 on the device", `expected "same" at position 14`},
+		{"a < 1 on the same", `expected "device" at position 18`},
+		// unknown references
+		{"and < 1", `unexpected "and" at position 1, quote a sensor with this name`},
+		{"device > 2", `unexpected "device" at position 1, quote a sensor with this name`},
+		{"type: > 1", "expected a sensor type at position 7"},
+		{"type:: > 1", "expected a sensor type at position 6"},
+		{"{sensor-1} < 1", `invalid sensor reference "sensor-1" at position 1`},
+		{"{" + sensorID + " < 1", "unclosed sensor reference at position 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMaxLength(t *testing.T) {
+	source := strings.Repeat("a < 1 OR ", 110) + "a < 1"
+	source += strings.Repeat(" ", MAX_CONDITION_LENGTH-len(source))
+
+	if _, err := Parse(source); err != nil {
+		t.Errorf("Parse() of %d characters error = %v", len(source), err)
+	}
+	if _, err := Parse(source + " "); err == nil {
+		t.Errorf("Parse() of %d characters succeeded", len(source)+1)
+	}
+}
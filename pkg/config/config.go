@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -21,6 +24,19 @@ type Config struct {
 	DatabaseUser string
 	DatabasePass string
 	DatabaseName string
+
+	// email notifications are disabled when SMTPHost is empty
+	SMTPHost        string
+	SMTPPort        string
+	SMTPUser        string
+	SMTPPass        string
+	SMTPFrom        string
+	SMTPBatchWindow time.Duration
+	SMTPReportTo    []string
+	SMTPReportHour  int
+
+	// deleted devices and sensors are purged after TrashRetention
+	TrashRetention time.Duration
 }
 
 func GetConfig() *Config {
@@ -32,5 +48,36 @@ func GetConfig() *Config {
 		DatabaseUser: os.Getenv("DB_USER"),
 		DatabasePass: os.Getenv("DB_PASS"),
 		DatabaseName: os.Getenv("DB_NAME"),
+
+		SMTPHost:        os.Getenv("SMTP_HOST"),
+		SMTPPort:        os.Getenv("SMTP_PORT"),
+		SMTPUser:        os.Getenv("SMTP_USER"),
+		SMTPPass:        os.Getenv("SMTP_PASS"),
+		SMTPFrom:        os.Getenv("SMTP_FROM"),
+		SMTPBatchWindow: time.Duration(getInt("SMTP_BATCH_WINDOW", 60)) * time.Second,
+		SMTPReportTo:    getList("SMTP_REPORT_TO"),
+		SMTPReportHour:  getInt("SMTP_REPORT_HOUR", 7),
+
+		TrashRetention: time.Duration(getInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+	}
+}
+
+// getInt returns the integer value of an environment variable, def when it is unset or invalid.
+func getInt(key string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// getList returns the comma separated values of an environment variable.
+func getList(key string) []string {
+	values := []string{}
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
 	}
+	return values
 }
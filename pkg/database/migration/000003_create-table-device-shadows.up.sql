@@ -0,0 +1,8 @@
+CREATE TABLE "device_shadows" (
+  "device_id"   uuid PRIMARY KEY REFERENCES "devices" ("id") ON DELETE CASCADE,
+  "desired"     JSONB NOT NULL DEFAULT '{}',
+  "reported"    JSONB NOT NULL DEFAULT '{}',
+  "version"     BIGINT NOT NULL DEFAULT 0,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE "device_commands" (
+  "id"              uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "device_id"       uuid NOT NULL REFERENCES "devices" ("id") ON DELETE CASCADE,
+  "name"            VARCHAR(100) NOT NULL,
+  "params"          JSONB NOT NULL DEFAULT '{}',
+  "status"          VARCHAR(20) NOT NULL,
+  "result"          JSONB NOT NULL DEFAULT '{}',
+  "expires_at"      TIMESTAMPTZ NOT NULL,
+  "delivered_at"    TIMESTAMPTZ,
+  "acknowledged_at" TIMESTAMPTZ,
+  "created_at"      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "device_commands_device_id_status_idx" ON "device_commands" ("device_id", "status", "created_at");
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "sensors_attributes_idx";
+DROP INDEX IF EXISTS "devices_attributes_idx";
+
+ALTER TABLE "sensors" DROP COLUMN IF EXISTS "attributes";
+ALTER TABLE "devices" DROP COLUMN IF EXISTS "attributes";
@@ -0,0 +1,5 @@
+ALTER TABLE "devices" ADD COLUMN "attributes" JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE "sensors" ADD COLUMN "attributes" JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX "devices_attributes_idx" ON "devices" USING GIN ("attributes" jsonb_path_ops);
+CREATE INDEX "sensors_attributes_idx" ON "sensors" USING GIN ("attributes" jsonb_path_ops);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "sensors_labels_idx";
+DROP INDEX IF EXISTS "devices_labels_idx";
+
+ALTER TABLE "sensors" DROP COLUMN IF EXISTS "labels";
+ALTER TABLE "devices" DROP COLUMN IF EXISTS "labels";
@@ -0,0 +1,5 @@
+ALTER TABLE "devices" ADD COLUMN "labels" JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE "sensors" ADD COLUMN "labels" JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX "devices_labels_idx" ON "devices" USING GIN ("labels");
+CREATE INDEX "sensors_labels_idx" ON "sensors" USING GIN ("labels");
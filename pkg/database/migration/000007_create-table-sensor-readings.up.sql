@@ -0,0 +1,9 @@
+CREATE TABLE "sensor_readings" (
+  "id"          BIGSERIAL PRIMARY KEY,
+  "sensor_id"   uuid NOT NULL REFERENCES "sensors" ("id") ON DELETE CASCADE,
+  "value"       DOUBLE PRECISION NOT NULL,
+  "recorded_at" TIMESTAMPTZ NOT NULL,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "sensor_readings_sensor_id_recorded_at_idx" ON "sensor_readings" ("sensor_id", "recorded_at" DESC);
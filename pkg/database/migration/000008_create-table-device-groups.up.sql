@@ -0,0 +1,20 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE "device_groups" (
+  "id"          uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "name"        VARCHAR(100) NOT NULL,
+  "description" TEXT NOT NULL,
+  "type"        VARCHAR(20) NOT NULL,
+  "filter"      JSONB,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "device_group_members" (
+  "group_id"    uuid NOT NULL REFERENCES "device_groups" ("id") ON DELETE CASCADE,
+  "device_id"   uuid NOT NULL REFERENCES "devices" ("id") ON DELETE CASCADE,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("group_id", "device_id")
+);
+
+CREATE INDEX "device_group_members_device_id_idx" ON "device_group_members" ("device_id");
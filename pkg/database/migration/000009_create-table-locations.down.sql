@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "devices_zone_id_idx";
+ALTER TABLE "devices" DROP COLUMN IF EXISTS "zone_id";
+
+DROP TABLE IF EXISTS "zones";
+DROP TABLE IF EXISTS "sites";
+DROP TABLE IF EXISTS "organizations";
@@ -0,0 +1,35 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE "organizations" (
+  "id"          uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "name"        VARCHAR(100) NOT NULL,
+  "description" TEXT NOT NULL,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "sites" (
+  "id"              uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "organization_id" uuid NOT NULL REFERENCES "organizations" ("id"),
+  "name"            VARCHAR(100) NOT NULL,
+  "description"     TEXT NOT NULL,
+  "created_at"      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "sites_organization_id_idx" ON "sites" ("organization_id");
+
+CREATE TABLE "zones" (
+  "id"          uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "site_id"     uuid NOT NULL REFERENCES "sites" ("id"),
+  "name"        VARCHAR(100) NOT NULL,
+  "description" TEXT NOT NULL,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "zones_site_id_idx" ON "zones" ("site_id");
+
+ALTER TABLE "devices" ADD COLUMN "zone_id" uuid REFERENCES "zones" ("id") ON DELETE SET NULL;
+
+CREATE INDEX "devices_zone_id_idx" ON "devices" ("zone_id");
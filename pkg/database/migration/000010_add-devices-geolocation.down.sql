@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "devices_latitude_longitude_idx";
+
+ALTER TABLE "devices"
+  DROP COLUMN IF EXISTS "latitude",
+  DROP COLUMN IF EXISTS "longitude",
+  DROP COLUMN IF EXISTS "altitude";
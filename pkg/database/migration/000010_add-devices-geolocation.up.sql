@@ -0,0 +1,6 @@
+ALTER TABLE "devices"
+  ADD COLUMN "latitude"  DOUBLE PRECISION CHECK ("latitude" BETWEEN -90 AND 90),
+  ADD COLUMN "longitude" DOUBLE PRECISION CHECK ("longitude" BETWEEN -180 AND 180),
+  ADD COLUMN "altitude"  DOUBLE PRECISION;
+
+CREATE INDEX "devices_latitude_longitude_idx" ON "devices" ("latitude", "longitude");
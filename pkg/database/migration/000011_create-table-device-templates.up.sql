@@ -0,0 +1,12 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE "device_templates" (
+  "id"          uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "slug"        VARCHAR(100) NOT NULL UNIQUE,
+  "name"        VARCHAR(100) NOT NULL,
+  "description" TEXT NOT NULL,
+  "device"      JSONB NOT NULL DEFAULT '{}',
+  "sensors"     JSONB NOT NULL DEFAULT '[]',
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
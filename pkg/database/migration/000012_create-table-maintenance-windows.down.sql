@@ -0,0 +1,3 @@
+ALTER TABLE "sensor_readings" DROP COLUMN IF EXISTS "maintenance";
+
+DROP TABLE IF EXISTS "maintenance_windows";
@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE "maintenance_windows" (
+  "id"               uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "device_id"        uuid REFERENCES "devices" ("id") ON DELETE CASCADE,
+  "group_id"         uuid REFERENCES "device_groups" ("id") ON DELETE CASCADE,
+  "name"             VARCHAR(100) NOT NULL,
+  "description"      TEXT NOT NULL,
+  "starts_at"        TIMESTAMPTZ NOT NULL,
+  "ends_at"          TIMESTAMPTZ NOT NULL,
+  "recurrence"       VARCHAR(10) NOT NULL DEFAULT 'none',
+  "recurrence_until" TIMESTAMPTZ,
+  "created_at"       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK (("device_id" IS NULL) <> ("group_id" IS NULL)),
+  CHECK ("ends_at" > "starts_at")
+);
+
+CREATE INDEX "maintenance_windows_device_id_idx" ON "maintenance_windows" ("device_id");
+CREATE INDEX "maintenance_windows_group_id_idx" ON "maintenance_windows" ("group_id");
+
+ALTER TABLE "sensor_readings" ADD COLUMN "maintenance" BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+ALTER TABLE "sensor_readings" DROP COLUMN IF EXISTS "calibrated_value";
+
+DROP TABLE IF EXISTS "sensor_calibrations";
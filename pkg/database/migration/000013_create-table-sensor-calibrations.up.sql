@@ -0,0 +1,20 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE "sensor_calibrations" (
+  "id"             uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "sensor_id"      uuid NOT NULL REFERENCES "sensors" ("id") ON DELETE CASCADE,
+  "kind"           VARCHAR(20) NOT NULL,
+  "offset_value"   DOUBLE PRECISION,
+  "scale_value"    DOUBLE PRECISION,
+  "coefficients"   JSONB NOT NULL DEFAULT '[]',
+  "points"         JSONB NOT NULL DEFAULT '[]',
+  "description"    TEXT NOT NULL,
+  "effective_from" TIMESTAMPTZ NOT NULL,
+  "created_at"     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "sensor_calibrations_sensor_id_effective_from_idx" ON "sensor_calibrations" ("sensor_id", "effective_from");
+
+-- value keeps the raw reading, calibrated_value is derived from it and NULL when no calibration applies
+ALTER TABLE "sensor_readings" ADD COLUMN "calibrated_value" DOUBLE PRECISION;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "sensor_readings_device_id_recorded_at_idx";
+
+ALTER TABLE "sensor_readings" DROP COLUMN IF EXISTS "device_id";
+
+DROP TABLE IF EXISTS "sensor_device_history";
@@ -0,0 +1,23 @@
+CREATE TABLE "sensor_device_history" (
+  "id"          BIGSERIAL PRIMARY KEY,
+  "sensor_id"   uuid NOT NULL REFERENCES "sensors" ("id") ON DELETE CASCADE,
+  "device_id"   uuid NOT NULL,
+  "attached_at" TIMESTAMPTZ NOT NULL,
+  "detached_at" TIMESTAMPTZ,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "sensor_device_history_sensor_id_attached_at_idx" ON "sensor_device_history" ("sensor_id", "attached_at" DESC);
+
+-- every existing sensor has been on its current device since it was created
+INSERT INTO "sensor_device_history" ("sensor_id", "device_id", "attached_at")
+SELECT "id", "device_id", "created_at" FROM "sensors";
+
+-- readings keep the device the sensor was attached to when they were recorded
+ALTER TABLE "sensor_readings" ADD COLUMN "device_id" uuid;
+
+UPDATE "sensor_readings" r SET "device_id" = s."device_id" FROM "sensors" s WHERE s."id" = r."sensor_id";
+
+ALTER TABLE "sensor_readings" ALTER COLUMN "device_id" SET NOT NULL;
+
+CREATE INDEX "sensor_readings_device_id_recorded_at_idx" ON "sensor_readings" ("device_id", "recorded_at" DESC);
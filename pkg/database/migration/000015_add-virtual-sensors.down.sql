@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "sensors_expression_refs_idx";
+
+ALTER TABLE "sensors" DROP COLUMN IF EXISTS "expression_refs";
+ALTER TABLE "sensors" DROP COLUMN IF EXISTS "expression";
@@ -0,0 +1,5 @@
+-- a sensor with an expression is virtual, its readings are computed from the referenced sensors
+ALTER TABLE "sensors" ADD COLUMN "expression" TEXT;
+ALTER TABLE "sensors" ADD COLUMN "expression_refs" uuid[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX "sensors_expression_refs_idx" ON "sensors" USING GIN ("expression_refs");
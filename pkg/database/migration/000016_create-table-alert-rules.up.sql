@@ -0,0 +1,41 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE "alert_rules" (
+  "id"              uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "name"            VARCHAR(100) NOT NULL,
+  "description"     TEXT NOT NULL,
+  "sensor_id"       uuid REFERENCES "sensors" ("id") ON DELETE CASCADE,
+  "sensor_type"     VARCHAR(50),
+  "operator"        VARCHAR(2) NOT NULL,
+  "threshold"       DOUBLE PRECISION NOT NULL,
+  "clear_threshold" DOUBLE PRECISION NOT NULL,
+  "for_seconds"     INTEGER NOT NULL DEFAULT 0,
+  "severity"        VARCHAR(10) NOT NULL,
+  "enabled"         BOOLEAN NOT NULL DEFAULT true,
+  "created_at"      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK (("sensor_id" IS NULL) <> ("sensor_type" IS NULL))
+);
+
+CREATE INDEX "alert_rules_sensor_id_idx" ON "alert_rules" ("sensor_id");
+CREATE INDEX "alert_rules_sensor_type_idx" ON "alert_rules" ("sensor_type");
+
+-- one state per rule and sensor, the rows of a type rule are created as its sensors report
+CREATE TABLE "alert_states" (
+  "id"                uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "rule_id"           uuid NOT NULL REFERENCES "alert_rules" ("id") ON DELETE CASCADE,
+  "sensor_id"         uuid NOT NULL REFERENCES "sensors" ("id") ON DELETE CASCADE,
+  "device_id"         uuid NOT NULL,
+  "status"            VARCHAR(10) NOT NULL DEFAULT 'ok',
+  "value"             DOUBLE PRECISION NOT NULL DEFAULT 0,
+  "pending_since"     TIMESTAMPTZ,
+  "fired_at"          TIMESTAMPTZ,
+  "resolved_at"       TIMESTAMPTZ,
+  "last_evaluated_at" TIMESTAMPTZ,
+  "created_at"        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE ("rule_id", "sensor_id")
+);
+
+CREATE INDEX "alert_states_status_idx" ON "alert_states" ("status");
+CREATE INDEX "alert_states_device_id_idx" ON "alert_states" ("device_id");
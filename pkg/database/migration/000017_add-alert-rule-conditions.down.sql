@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "alert_rules_condition_idx";
+
+ALTER TABLE "alert_rules" DROP COLUMN IF EXISTS "window_seconds";
+ALTER TABLE "alert_rules" DROP COLUMN IF EXISTS "condition";
@@ -0,0 +1,5 @@
+-- existing rules are threshold rules, window_seconds is only used by rate of change rules
+ALTER TABLE "alert_rules" ADD COLUMN "condition" VARCHAR(20) NOT NULL DEFAULT 'threshold';
+ALTER TABLE "alert_rules" ADD COLUMN "window_seconds" INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX "alert_rules_condition_idx" ON "alert_rules" ("condition");
@@ -0,0 +1,45 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- an empty events filter subscribes to every event
+CREATE TABLE "webhooks" (
+  "id"          uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "name"        VARCHAR(100) NOT NULL,
+  "url"         TEXT NOT NULL,
+  "secret"      TEXT NOT NULL,
+  "events"      TEXT[] NOT NULL DEFAULT '{}',
+  "enabled"     BOOLEAN NOT NULL DEFAULT true,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- pending deliveries are retried from next_attempt_at, which also leases them to the sender in progress
+CREATE TABLE "webhook_deliveries" (
+  "id"               uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "webhook_id"       uuid NOT NULL REFERENCES "webhooks" ("id") ON DELETE CASCADE,
+  "event_type"       VARCHAR(100) NOT NULL,
+  "payload"          JSONB NOT NULL DEFAULT '{}',
+  "status"           VARCHAR(20) NOT NULL,
+  "attempts"         INTEGER NOT NULL DEFAULT 0,
+  "next_attempt_at"  TIMESTAMPTZ,
+  "last_status_code" INTEGER,
+  "last_error"       TEXT NOT NULL DEFAULT '',
+  "delivered_at"     TIMESTAMPTZ,
+  "redelivery_of"    uuid REFERENCES "webhook_deliveries" ("id") ON DELETE SET NULL,
+  "created_at"       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "webhook_deliveries_webhook_id_idx" ON "webhook_deliveries" ("webhook_id", "created_at" DESC);
+CREATE INDEX "webhook_deliveries_next_attempt_at_idx" ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE TABLE "webhook_delivery_attempts" (
+  "id"            uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "delivery_id"   uuid NOT NULL REFERENCES "webhook_deliveries" ("id") ON DELETE CASCADE,
+  "status_code"   INTEGER,
+  "error"         TEXT NOT NULL DEFAULT '',
+  "response_body" TEXT NOT NULL DEFAULT '',
+  "duration_ms"   INTEGER NOT NULL DEFAULT 0,
+  "created_at"    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "webhook_delivery_attempts_delivery_id_idx" ON "webhook_delivery_attempts" ("delivery_id", "created_at");
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "alert_events";
+DROP FUNCTION IF EXISTS "alert_events_immutable" ();
+
+DROP INDEX IF EXISTS "alert_states_assignee_idx";
+
+ALTER TABLE "alert_states" DROP COLUMN IF EXISTS "resolved_by";
+ALTER TABLE "alert_states" DROP COLUMN IF EXISTS "snoozed_until";
+ALTER TABLE "alert_states" DROP COLUMN IF EXISTS "assignee";
+ALTER TABLE "alert_states" DROP COLUMN IF EXISTS "acknowledged_by";
+ALTER TABLE "alert_states" DROP COLUMN IF EXISTS "acknowledged_at";
@@ -0,0 +1,32 @@
+ALTER TABLE "alert_states" ADD COLUMN "acknowledged_at" TIMESTAMPTZ;
+ALTER TABLE "alert_states" ADD COLUMN "acknowledged_by" VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE "alert_states" ADD COLUMN "assignee" VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE "alert_states" ADD COLUMN "snoozed_until" TIMESTAMPTZ;
+ALTER TABLE "alert_states" ADD COLUMN "resolved_by" VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX "alert_states_assignee_idx" ON "alert_states" ("assignee");
+
+-- the timeline of an alert, status changes and the actions of users, rows are never changed
+CREATE TABLE "alert_events" (
+  "id"            uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "alert_id"      uuid NOT NULL REFERENCES "alert_states" ("id") ON DELETE CASCADE,
+  "type"          VARCHAR(20) NOT NULL,
+  "actor"         VARCHAR(100) NOT NULL,
+  "note"          TEXT NOT NULL DEFAULT '',
+  "status"        VARCHAR(10) NOT NULL,
+  "value"         DOUBLE PRECISION NOT NULL DEFAULT 0,
+  "assignee"      VARCHAR(100) NOT NULL DEFAULT '',
+  "snoozed_until" TIMESTAMPTZ,
+  "created_at"    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "alert_events_alert_id_idx" ON "alert_events" ("alert_id", "created_at");
+
+CREATE FUNCTION "alert_events_immutable" () RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'alert events can not be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "alert_events_immutable" BEFORE UPDATE ON "alert_events"
+  FOR EACH ROW EXECUTE FUNCTION "alert_events_immutable" ();
@@ -0,0 +1 @@
+ALTER TABLE "alert_rules" ADD COLUMN "notify_emails" TEXT[] NOT NULL DEFAULT '{}';
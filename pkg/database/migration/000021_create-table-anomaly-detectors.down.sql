@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "sensor_anomalies";
+DROP TABLE IF EXISTS "anomaly_detectors";
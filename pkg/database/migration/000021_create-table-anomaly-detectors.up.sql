@@ -0,0 +1,32 @@
+-- one detector per sensor, model holds the learned state so detection carries on after a restart
+CREATE TABLE "anomaly_detectors" (
+  "sensor_id"        uuid PRIMARY KEY REFERENCES "sensors" ("id") ON DELETE CASCADE,
+  "enabled"          BOOLEAN NOT NULL DEFAULT true,
+  "sensitivity"      DOUBLE PRECISION NOT NULL,
+  "alpha"            DOUBLE PRECISION NOT NULL,
+  "window_size"      INTEGER NOT NULL,
+  "seasonal"         BOOLEAN NOT NULL DEFAULT false,
+  "model"            JSONB NOT NULL DEFAULT '{}',
+  "last_recorded_at" TIMESTAMPTZ,
+  "created_at"       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- the readings flagged by a detector with their scores
+CREATE TABLE "sensor_anomalies" (
+  "id"          BIGSERIAL PRIMARY KEY,
+  "reading_id"  BIGINT NOT NULL REFERENCES "sensor_readings" ("id") ON DELETE CASCADE,
+  "sensor_id"   uuid NOT NULL REFERENCES "sensors" ("id") ON DELETE CASCADE,
+  "device_id"   uuid NOT NULL,
+  "value"       DOUBLE PRECISION NOT NULL,
+  "expected"    DOUBLE PRECISION NOT NULL,
+  "score"       DOUBLE PRECISION NOT NULL,
+  "z_score"     DOUBLE PRECISION NOT NULL,
+  "ewma_score"  DOUBLE PRECISION NOT NULL,
+  "recorded_at" TIMESTAMPTZ NOT NULL,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "sensor_anomalies_sensor_id_recorded_at_idx" ON "sensor_anomalies" ("sensor_id", "recorded_at" DESC);
+CREATE INDEX "sensor_anomalies_device_id_recorded_at_idx" ON "sensor_anomalies" ("device_id", "recorded_at" DESC);
+CREATE INDEX "sensor_anomalies_reading_id_idx" ON "sensor_anomalies" ("reading_id");
@@ -0,0 +1,12 @@
+DELETE FROM "alert_rules" WHERE "condition" = 'composite';
+
+DROP INDEX IF EXISTS "alert_states_rule_id_idx";
+DROP INDEX IF EXISTS "alert_states_rule_id_device_id_idx";
+
+ALTER TABLE "alert_states" ALTER COLUMN "device_id" SET NOT NULL;
+ALTER TABLE "alert_states" ALTER COLUMN "sensor_id" SET NOT NULL;
+
+ALTER TABLE "alert_rules" DROP CONSTRAINT IF EXISTS "alert_rules_target_check";
+ALTER TABLE "alert_rules" ADD CONSTRAINT "alert_rules_check" CHECK (("sensor_id" IS NULL) <> ("sensor_type" IS NULL));
+
+ALTER TABLE "alert_rules" DROP COLUMN IF EXISTS "expression";
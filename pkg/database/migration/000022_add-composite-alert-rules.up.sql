@@ -0,0 +1,17 @@
+ALTER TABLE "alert_rules" ADD COLUMN "expression" TEXT NOT NULL DEFAULT '';
+
+-- a composite rule targets no sensor, its expression selects the sensors
+ALTER TABLE "alert_rules" DROP CONSTRAINT IF EXISTS "alert_rules_check";
+ALTER TABLE "alert_rules" ADD CONSTRAINT "alert_rules_target_check" CHECK (
+  CASE WHEN "condition" = 'composite'
+    THEN "sensor_id" IS NULL AND "sensor_type" IS NULL AND "expression" <> ''
+    ELSE ("sensor_id" IS NULL) <> ("sensor_type" IS NULL)
+  END
+);
+
+-- the alerts of a composite rule have no sensor, one per device on the same device, otherwise one for the rule
+ALTER TABLE "alert_states" ALTER COLUMN "sensor_id" DROP NOT NULL;
+ALTER TABLE "alert_states" ALTER COLUMN "device_id" DROP NOT NULL;
+
+CREATE UNIQUE INDEX "alert_states_rule_id_device_id_idx" ON "alert_states" ("rule_id", "device_id") WHERE "sensor_id" IS NULL;
+CREATE UNIQUE INDEX "alert_states_rule_id_idx" ON "alert_states" ("rule_id") WHERE "sensor_id" IS NULL AND "device_id" IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "escalation_notifications";
+DROP TABLE IF EXISTS "alert_escalations";
+ALTER TABLE "alert_rules" DROP COLUMN IF EXISTS "escalation_policy_id";
+DROP TABLE IF EXISTS "escalation_policies";
@@ -0,0 +1,50 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- steps is a JSON array of {delay, emails, webhook_ids}, delays are seconds after the alert fired
+CREATE TABLE "escalation_policies" (
+  "id"                     uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "name"                   VARCHAR(100) NOT NULL,
+  "description"            TEXT NOT NULL DEFAULT '',
+  "steps"                  JSONB NOT NULL DEFAULT '[]',
+  "repeat_seconds"         INTEGER NOT NULL DEFAULT 0,
+  "group_by"               TEXT[] NOT NULL DEFAULT '{}',
+  "group_wait_seconds"     INTEGER NOT NULL DEFAULT 0,
+  "group_interval_seconds" INTEGER NOT NULL DEFAULT 0,
+  "dedup_window_seconds"   INTEGER NOT NULL DEFAULT 0,
+  "rate_limit_per_hour"    INTEGER NOT NULL DEFAULT 0,
+  "enabled"                BOOLEAN NOT NULL DEFAULT true,
+  "created_at"             TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"             TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE "alert_rules" ADD COLUMN "escalation_policy_id" uuid REFERENCES "escalation_policies" ("id") ON DELETE SET NULL;
+
+-- step is the number of steps notified since fired_at, next_notify_at is NULL once the escalation stopped
+CREATE TABLE "alert_escalations" (
+  "alert_id"       uuid PRIMARY KEY REFERENCES "alert_states" ("id") ON DELETE CASCADE,
+  "policy_id"      uuid NOT NULL REFERENCES "escalation_policies" ("id") ON DELETE CASCADE,
+  "step"           INTEGER NOT NULL DEFAULT 0,
+  "fired_at"       TIMESTAMPTZ NOT NULL,
+  "next_notify_at" TIMESTAMPTZ,
+  "notified_at"    TIMESTAMPTZ,
+  "created_at"     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at"     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "alert_escalations_next_notify_at_idx" ON "alert_escalations" ("next_notify_at") WHERE "next_notify_at" IS NOT NULL;
+
+CREATE TABLE "escalation_notifications" (
+  "id"          uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "policy_id"   uuid NOT NULL REFERENCES "escalation_policies" ("id") ON DELETE CASCADE,
+  "step"        INTEGER NOT NULL,
+  "repeat"      BOOLEAN NOT NULL DEFAULT false,
+  "group_key"   TEXT NOT NULL DEFAULT '',
+  "alert_ids"   uuid[] NOT NULL DEFAULT '{}',
+  "emails"      TEXT[] NOT NULL DEFAULT '{}',
+  "webhook_ids" uuid[] NOT NULL DEFAULT '{}',
+  "error"       TEXT NOT NULL DEFAULT '',
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "escalation_notifications_policy_id_idx" ON "escalation_notifications" ("policy_id", "created_at" DESC);
+CREATE INDEX "escalation_notifications_group_key_idx" ON "escalation_notifications" ("policy_id", "group_key", "created_at" DESC);
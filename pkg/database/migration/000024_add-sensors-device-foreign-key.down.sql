@@ -0,0 +1 @@
+ALTER TABLE "sensors" DROP CONSTRAINT IF EXISTS "sensors_device_id_fkey";
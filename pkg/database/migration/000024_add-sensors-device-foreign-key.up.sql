@@ -0,0 +1,5 @@
+-- sensors of devices deleted before the constraint existed, their readings, calibrations and alerts go with them
+DELETE FROM "sensors" WHERE "device_id" NOT IN (SELECT "id" FROM "devices");
+
+-- a device with sensors can only be deleted together with them
+ALTER TABLE "sensors" ADD CONSTRAINT "sensors_device_id_fkey" FOREIGN KEY ("device_id") REFERENCES "devices" ("id");
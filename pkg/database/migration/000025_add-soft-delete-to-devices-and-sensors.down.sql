@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "sensors_deleted_at_idx";
+DROP INDEX IF EXISTS "devices_deleted_at_idx";
+
+DELETE FROM "sensors" WHERE "deleted_at" IS NOT NULL;
+DELETE FROM "devices" WHERE "deleted_at" IS NOT NULL;
+
+ALTER TABLE "sensors" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "devices" DROP COLUMN IF EXISTS "deleted_at";
@@ -0,0 +1,6 @@
+-- deleted devices and sensors stay in the trash until they are restored or purged
+ALTER TABLE "devices" ADD COLUMN "deleted_at" TIMESTAMPTZ;
+ALTER TABLE "sensors" ADD COLUMN "deleted_at" TIMESTAMPTZ;
+
+CREATE INDEX "devices_deleted_at_idx" ON "devices" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+CREATE INDEX "sensors_deleted_at_idx" ON "sensors" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE "sensors" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "devices" DROP COLUMN IF EXISTS "version";
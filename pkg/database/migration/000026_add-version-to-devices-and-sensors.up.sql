@@ -0,0 +1,3 @@
+-- incremented on every write, exposed as the ETag of the device or sensor
+ALTER TABLE "devices" ADD COLUMN "version" INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE "sensors" ADD COLUMN "version" INTEGER NOT NULL DEFAULT 1;
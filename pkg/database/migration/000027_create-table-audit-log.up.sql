@@ -0,0 +1,28 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- every create, update, delete, restore and purge of devices and sensors, changes maps each changed column
+-- to its old and new value, rows are never changed or deleted and outlive the resources they are about
+CREATE TABLE "audit_log" (
+  "id"            uuid DEFAULT uuid_generate_v4 () PRIMARY KEY,
+  "resource_type" VARCHAR(10) NOT NULL,
+  "resource_id"   uuid NOT NULL,
+  "action"        VARCHAR(10) NOT NULL,
+  "actor"         VARCHAR(100) NOT NULL,
+  "request_id"    TEXT,
+  "changes"       JSONB NOT NULL DEFAULT '{}',
+  "created_at"    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "audit_log_resource_idx" ON "audit_log" ("resource_type", "resource_id", "created_at" DESC);
+CREATE INDEX "audit_log_created_at_idx" ON "audit_log" ("created_at" DESC);
+CREATE INDEX "audit_log_actor_idx" ON "audit_log" ("actor", "created_at" DESC);
+CREATE INDEX "audit_log_request_id_idx" ON "audit_log" ("request_id");
+
+CREATE FUNCTION "audit_log_immutable" () RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit log entries can not be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_log_immutable" BEFORE UPDATE OR DELETE ON "audit_log"
+  FOR EACH ROW EXECUTE FUNCTION "audit_log_immutable" ();
@@ -0,0 +1,8 @@
+-- events are written in the transaction of the change causing them and removed once turned into webhook deliveries
+CREATE TABLE "event_outbox" (
+  "id"          BIGSERIAL PRIMARY KEY,
+  "type"        VARCHAR(100) NOT NULL,
+  "resource_id" TEXT NOT NULL,
+  "data"        JSONB,
+  "created_at"  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
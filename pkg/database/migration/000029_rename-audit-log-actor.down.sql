@@ -0,0 +1,2 @@
+ALTER INDEX "audit_log_claimed_actor_idx" RENAME TO "audit_log_actor_idx";
+ALTER TABLE "audit_log" RENAME COLUMN "claimed_actor" TO "actor";
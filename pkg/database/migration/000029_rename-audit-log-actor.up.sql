@@ -0,0 +1,3 @@
+-- the actor is the X-User header of the request, which is not authenticated, so it is only what the caller claims
+ALTER TABLE "audit_log" RENAME COLUMN "actor" TO "claimed_actor";
+ALTER INDEX "audit_log_actor_idx" RENAME TO "audit_log_claimed_actor_idx";
@@ -0,0 +1,487 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MAX_EXPRESSION_LENGTH keeps expressions small enough to evaluate on every reading.
+const MAX_EXPRESSION_LENGTH = 1000
+
+var (
+	ErrMissingValue = errors.New("missing value")
+
+	refRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	constants = map[string]float64{
+		"pi": math.Pi,
+		"e":  math.E,
+	}
+)
+
+type function struct {
+	minArgs int
+	maxArgs int // -1 for any number of arguments
+	call    func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"abs":   {1, 1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {1, 1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"exp":   {1, 1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"ln":    {1, 1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log10": {1, 1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"round": {1, 1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"pow":   {2, 2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min": {1, -1, func(a []float64) float64 {
+		v := a[0]
+		for _, x := range a[1:] {
+			v = math.Min(v, x)
+		}
+		return v
+	}},
+	"max": {1, -1, func(a []float64) float64 {
+		v := a[0]
+		for _, x := range a[1:] {
+			v = math.Max(v, x)
+		}
+		return v
+	}},
+}
+
+// Expr is a parsed arithmetic expression over sensor values. Sensors are referenced by ID in braces,
+// e.g. "({6f1c...} + {9a2e...}) / 2".
+type Expr struct {
+	source string
+	root   node
+	refs   []token
+}
+
+// Parse parses an expression with the operators + - * / ^, parentheses, numbers, the constants pi and e,
+// the functions abs, sqrt, exp, ln, log10, round, pow, min, max and sensor references.
+func Parse(source string) (*Expr, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, errors.New("expression is empty")
+	}
+	if len(source) > MAX_EXPRESSION_LENGTH {
+		return nil, fmt.Errorf("expression is longer than %d characters", MAX_EXPRESSION_LENGTH)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+	}
+
+	e := &Expr{source: source, root: root}
+	for _, t := range tokens {
+		if t.kind == tokenRef {
+			e.refs = append(e.refs, t)
+		}
+	}
+
+	return e, nil
+}
+
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Refs returns the referenced sensor IDs once each, in order of appearance.
+func (e *Expr) Refs() []string {
+	refs := []string{}
+	seen := map[string]bool{}
+	for _, t := range e.refs {
+		if !seen[t.text] {
+			seen[t.text] = true
+			refs = append(refs, t.text)
+		}
+	}
+
+	return refs
+}
+
+// Rename returns the source with references replaced by the mapped IDs, unmapped references are kept.
+func (e *Expr) Rename(ids map[string]string) string {
+	var b strings.Builder
+	last := 0
+	for _, t := range e.refs {
+		id, ok := ids[t.text]
+		if !ok {
+			continue
+		}
+		b.WriteString(e.source[last:t.pos])
+		b.WriteString("{" + id + "}")
+		last = t.end
+	}
+	b.WriteString(e.source[last:])
+
+	return b.String()
+}
+
+// Eval evaluates the expression with the values of the referenced sensors. A result that is not a finite
+// number, e.g. after a division by zero, is an error.
+func (e *Expr) Eval(values map[string]float64) (float64, error) {
+	v, err := e.root.eval(values)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errors.New("result is not a finite number")
+	}
+
+	return v, nil
+}
+
+// TOKENS
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenRef
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+	end   int
+}
+
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+
+	i := 0
+	for i < len(source) {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '{':
+			end := strings.IndexByte(source[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed sensor reference at position %d", i+1)
+			}
+			id := strings.TrimSpace(source[i+1 : i+end])
+			if !refRegex.MatchString(id) {
+				return nil, fmt.Errorf("invalid sensor reference %q at position %d", id, i+1)
+			}
+			tokens = append(tokens, token{kind: tokenRef, text: strings.ToLower(id), pos: i, end: i + end + 1})
+			i += end + 1
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			if i < len(source) && (source[i] == 'e' || source[i] == 'E') {
+				j := i + 1
+				if j < len(source) && (source[j] == '+' || source[j] == '-') {
+					j++
+				}
+				if j < len(source) && isDigit(source[j]) {
+					i = j
+					for i < len(source) && isDigit(source[i]) {
+						i++
+					}
+				}
+			}
+			value, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", source[start:i], start+1)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], value: value, pos: start, end: i})
+		case isLetter(c):
+			start := i
+			for i < len(source) && (isLetter(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(source[start:i]), pos: start, end: i})
+		case strings.IndexByte("+-*/^", c) >= 0:
+			tokens = append(tokens, token{kind: tokenOperator, text: string(c), pos: i, end: i + 1})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i, end: i + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i, end: i + 1})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i, end: i + 1})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, i+1)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, text: "end of expression", pos: len(source), end: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// PARSER
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOperator(ops string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && strings.Contains(ops, t.text)
+}
+
+// parseSum parses sum := product (("+" | "-") product)*
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+-") {
+		op := p.next().text[0]
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseProduct parses product := unary (("*" | "/") unary)*
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*/") {
+		op := p.next().text[0]
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseUnary parses unary := ("-" | "+") unary | power
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("+-") {
+		op := p.next().text[0]
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == '-' {
+			return negateNode{operand: operand}, nil
+		}
+		return operand, nil
+	}
+
+	return p.parsePower()
+}
+
+// parsePower parses power := primary ("^" unary)?, so 2^3^2 is 2^(3^2) and 2^-1 is allowed.
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		p.next()
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: '^', left: base, right: exponent}, nil
+	}
+
+	return base, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return numberNode{value: t.value}, nil
+	case tokenRef:
+		return refNode{id: t.text}, nil
+	case tokenLParen:
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d", closing.pos+1)
+		}
+		return inner, nil
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(t)
+		}
+		if value, ok := constants[t.text]; ok {
+			return numberNode{value: value}, nil
+		}
+		return nil, fmt.Errorf("unknown name %q at position %d", t.text, t.pos+1)
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos+1)
+	}
+	p.next()
+
+	args := []node{}
+	if p.peek().kind != tokenRParen {
+		for {
+			arg, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.kind != tokenRParen {
+		return nil, fmt.Errorf("expected \")\" at position %d", closing.pos+1)
+	}
+
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("function %q at position %d has %d arguments", name.text, name.pos+1, len(args))
+	}
+
+	return callNode{fn: fn, args: args}, nil
+}
+
+// NODES
+
+type node interface {
+	eval(values map[string]float64) (float64, error)
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n numberNode) eval(map[string]float64) (float64, error) {
+	return n.value, nil
+}
+
+type refNode struct {
+	id string
+}
+
+func (n refNode) eval(values map[string]float64) (float64, error) {
+	v, ok := values[n.id]
+	if !ok {
+		return 0, fmt.Errorf("%w of sensor %s", ErrMissingValue, n.id)
+	}
+	return v, nil
+}
+
+type negateNode struct {
+	operand node
+}
+
+func (n negateNode) eval(values map[string]float64) (float64, error) {
+	v, err := n.operand.eval(values)
+	return -v, err
+}
+
+type binaryNode struct {
+	op    byte
+	left  node
+	right node
+}
+
+func (n binaryNode) eval(values map[string]float64) (float64, error) {
+	l, err := n.left.eval(values)
+	if err != nil {
+		return 0, err
+	}
+	r, err := n.right.eval(values)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return l / r, nil
+	}
+
+	return math.Pow(l, r), nil
+}
+
+type callNode struct {
+	fn   function
+	args []node
+}
+
+func (n callNode) eval(values map[string]float64) (float64, error) {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(values)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+
+	return n.fn.call(args), nil
+}
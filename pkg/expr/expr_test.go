@@ -0,0 +1,298 @@
+package expr
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	sensorA = "6f1c2a3e-0b4d-4c5e-8f90-a1b2c3d4e5f6"
+	sensorB = "9a2e4b6c-1d3f-4a5b-9c7d-e8f901234567"
+	sensorC = "0d4c8e2a-7b6f-4e1d-a3c5-b9f8e7d6c5b4"
+)
+
+func TestEval(t *testing.T) {
+	values := map[string]float64{sensorA: 10, sensorB: 4}
+
+	tests := []struct {
+		source string
+		want   float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"8 / 4 / 2", 1},
+		{"2 * 3 ^ 2", 18},
+		{"2 ^ 3 ^ 2", 512},
+		{"-2 ^ 2", -4},
+		{"(-2) ^ 2", 4},
+		{"2 ^ -1", 0.5},
+		{"-3 + 5", 2},
+		{"--3", 3},
+		{"+3", 3},
+		{"2 * -3", -6},
+		{"-(1 + 2) * 2", -6},
+		{"1.5e2 + .5", 150.5},
+		{"round(pi * 100)", 314},
+		{"abs(-4) + sqrt(9)", 7},
+		{"pow(2, 10)", 1024},
+		{"min(3, 1, 2) + max(3, 1, 2)", 4},
+		{"ROUND(E)", 3},
+		{"({" + sensorA + "} + {" + sensorB + "}) / 2", 7},
+		{"-{" + sensorA + "} ^ 2", -100},
+		{"{" + strings.ToUpper(sensorB) + "} * 2", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			e, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := e.Eval(values)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	values := map[string]float64{sensorA: 10, sensorB: 0}
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"1 / 0", "division by zero"},
+		{"{" + sensorA + "} / {" + sensorB + "}", "division by zero"},
+		{"1 / (2 - 2)", "division by zero"},
+		{"ln(0)", "result is not a finite number"},
+		{"sqrt(-1)", "result is not a finite number"},
+		{"10 ^ 400", "result is not a finite number"},
+		{"{" + sensorC + "} + 1", "missing value of sensor " + sensorC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			e, err := Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			_, err = e.Eval(values)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Eval() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	e, _ := Parse("{" + sensorC + "}")
+	if _, err := e.Eval(values); !errors.Is(err, ErrMissingValue) {
+		t.Errorf("Eval() error = %v, want ErrMissingValue", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"", "expression is empty"},
+		{"   ", "expression is empty"},
+		{strings.Repeat("1+", 500) + "1", "expression is longer than 1000 characters"},
+		{"foo(1)", `unknown function "foo" at position 1`},
+		{"1 + median(1, 2)", `unknown function "median" at position 5`},
+		{"x + 1", `unknown name "x" at position 1`},
+		{"abs(1, 2)", `function "abs" at position 1 has 2 arguments`},
+		{"pow(2)", `function "pow" at position 1 has 1 arguments`},
+		{"min()", `function "min" at position 1 has 0 arguments`},
+		{"1 +", `unexpected "end of expression" at position 4`},
+		{"(1 + 2", `expected ")" at position 7`},
+		{"1 + 2)", `unexpected ")" at position 6`},
+		{"1 2", `unexpected "2" at position 3`},
+		{"* 2", `unexpected "*" at position 1`},
+		{"1 $ 2", `unexpected '$' at position 3`},
+		{"1..2", `invalid number "1..2" at position 1`},
+		{"{" + sensorA, "unclosed sensor reference at position 1"},
+		{"{sensor-1} + 1", `invalid sensor reference "sensor-1" at position 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := Parse(tt.source)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMaxLength(t *testing.T) {
+	source := strings.Repeat("1+", (MAX_EXPRESSION_LENGTH-1)/2) + "1"
+	if len(source) > MAX_EXPRESSION_LENGTH {
+		t.Fatalf("source has %d characters", len(source))
+	}
+
+	e, err := Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, _ := e.Eval(nil); got != float64((MAX_EXPRESSION_LENGTH-1)/2+1) {
+		t.Errorf("Eval() = %v, want %v", got, (MAX_EXPRESSION_LENGTH-1)/2+1)
+	}
+
+	if _, err := Parse(source + "+1"); err == nil {
+		t.Errorf("Parse() of %d characters succeeded", len(source)+2)
+	}
+}
+
+func TestRefs(t *testing.T) {
+	e, err := Parse("{" + sensorB + "} + {" + strings.ToUpper(sensorA) + "} * {" + sensorB + "}")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{sensorB, sensorA}
+	if got := e.Refs(); !slices.Equal(got, want) {
+		t.Errorf("Refs() = %v, want %v", got, want)
+	}
+
+	e, _ = Parse("1 + 2")
+	if got := e.Refs(); len(got) != 0 {
+		t.Errorf("Refs() = %v, want none", got)
+	}
+}
+
+func TestRename(t *testing.T) {
+	source := "({" + sensorA + "} + { " + strings.ToUpper(sensorB) + " }) / 2 - {" + sensorA + "}"
+
+	tests := []struct {
+		name string
+		ids  map[string]string
+		want string
+	}{
+		{
+			name: "every reference",
+			ids:  map[string]string{sensorA: "11111111-1111-1111-1111-111111111111", sensorB: "22222222-2222-2222-2222-222222222222"},
+			want: "({11111111-1111-1111-1111-111111111111} + {22222222-2222-2222-2222-222222222222}) / 2 - {11111111-1111-1111-1111-111111111111}",
+		},
+		{
+			name: "unmapped references are kept",
+			ids:  map[string]string{sensorB: sensorC},
+			want: "({" + sensorA + "} + {" + sensorC + "}) / 2 - {" + sensorA + "}",
+		},
+		{
+			name: "no mapping",
+			ids:  nil,
+			want: source,
+		},
+	}
+
+	e, err := Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := e.Rename(tt.ids)
+			if got != tt.want {
+				t.Errorf("Rename() = %q, want %q", got, tt.want)
+			}
+			if _, err := Parse(got); err != nil {
+				t.Errorf("Parse() of renamed expression error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		deps map[string][]string
+		want []string
+	}{
+		{
+			name: "self reference",
+			id:   "a",
+			deps: map[string][]string{"a": {"x", "a"}},
+			want: []string{"a", "a"},
+		},
+		{
+			name: "indirect",
+			id:   "a",
+			deps: map[string][]string{"a": {"b"}, "b": {"a"}},
+			want: []string{"a", "b", "a"},
+		},
+		{
+			name: "through several sensors",
+			id:   "a",
+			deps: map[string][]string{"a": {"x", "b"}, "b": {"c"}, "c": {"y", "a"}},
+			want: []string{"a", "b", "c", "a"},
+		},
+		{
+			name: "no cycle",
+			id:   "a",
+			deps: map[string][]string{"a": {"b", "c"}, "b": {"c"}, "c": {"x"}},
+			want: nil,
+		},
+		{
+			name: "cycle not through the sensor",
+			id:   "a",
+			deps: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}},
+			want: nil,
+		},
+		{
+			name: "no references",
+			id:   "a",
+			deps: map[string][]string{},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindCycle(tt.id, tt.deps)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FindCycle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrder(t *testing.T) {
+	// c = a + b, d = c * 2, e = b, f = x
+	deps := map[string][]string{
+		"c": {"a", "b"},
+		"d": {"c"},
+		"e": {"b"},
+		"f": {"x"},
+	}
+
+	tests := []struct {
+		changed []string
+		want    []string
+	}{
+		{[]string{"a"}, []string{"c", "d"}},
+		{[]string{"b"}, []string{"c", "d", "e"}},
+		{[]string{"c"}, []string{"d"}},
+		{[]string{"d"}, []string{}},
+		{[]string{"a", "x"}, []string{"c", "d", "f"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.changed, ","), func(t *testing.T) {
+			got := Order(tt.changed, deps)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
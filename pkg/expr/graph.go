@@ -0,0 +1,79 @@
+package expr
+
+import "sort"
+
+// FindCycle looks for a path from id back to itself through the references in deps, where deps maps
+// a virtual sensor ID to the sensor IDs its expression references. The cycle is returned starting and
+// ending with id, or nil if there is none.
+func FindCycle(id string, deps map[string][]string) []string {
+	visited := map[string]bool{}
+
+	var visit func(current string, path []string) []string
+	visit = func(current string, path []string) []string {
+		for _, ref := range deps[current] {
+			if ref == id {
+				return append(path, ref)
+			}
+			if visited[ref] {
+				continue
+			}
+			visited[ref] = true
+			if cycle := visit(ref, append(path, ref)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return visit(id, []string{id})
+}
+
+// Order returns the IDs in deps that depend directly or indirectly on one of the changed IDs, ordered so
+// that every ID comes after the IDs it references. deps must not have cycles.
+func Order(changed []string, deps map[string][]string) []string {
+	dependents := map[string][]string{}
+	for id, refs := range deps {
+		for _, ref := range refs {
+			dependents[ref] = append(dependents[ref], id)
+		}
+	}
+
+	affected := map[string]bool{}
+	queue := append([]string{}, changed...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[current] {
+			if !affected[dependent] {
+				affected[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	ordered := []string{}
+	done := map[string]bool{}
+	var visit func(id string)
+	visit = func(id string) {
+		if done[id] {
+			return
+		}
+		done[id] = true
+		for _, ref := range deps[id] {
+			if affected[ref] {
+				visit(ref)
+			}
+		}
+		ordered = append(ordered, id)
+	}
+	ids := []string{}
+	for id := range affected {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		visit(id)
+	}
+
+	return ordered
+}
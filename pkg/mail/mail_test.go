@@ -0,0 +1,102 @@
+package mail_test
+
+import (
+	"bytes"
+	"go-api/pkg/mail"
+	"go-api/pkg/mail/mailtest"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSMTPSend(t *testing.T) {
+	server, err := mailtest.NewServer()
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	defer server.Close()
+
+	msg := mail.Message{
+		From:    "alerts@example.com",
+		To:      []string{"jane@example.com", "john@example.com"},
+		Subject: "2 firing alerts – greenhouse",
+		Text:    "Soil moisture is low on sensor-1",
+		HTML:    "<p>Soil moisture is <b>low</b> on sensor-1</p>",
+	}
+
+	err = mail.NewSMTP(server.Host, server.Port, "", "").Send(msg)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("received %d messages, want 1", len(messages))
+	}
+	received := messages[0]
+
+	if received.From != msg.From {
+		t.Errorf("envelope from = %q, want %q", received.From, msg.From)
+	}
+	if !slices.Equal(received.To, msg.To) {
+		t.Errorf("envelope to = %v, want %v", received.To, msg.To)
+	}
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(received.Data))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	if got := parsed.Header.Get("From"); got != msg.From {
+		t.Errorf("From = %q, want %q", got, msg.From)
+	}
+	if got := parsed.Header.Get("To"); got != strings.Join(msg.To, ", ") {
+		t.Errorf("To = %q, want %q", got, strings.Join(msg.To, ", "))
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+	if _, err := parsed.Header.Date(); err != nil {
+		t.Errorf("Date is invalid: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", parsed.Header.Get("Content-Type"))
+	}
+
+	// multipart.Reader decodes quoted-printable parts
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	want := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+	for _, w := range want {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		if got := part.Header.Get("Content-Type"); got != w.contentType {
+			t.Errorf("part Content-Type = %q, want %q", got, w.contentType)
+		}
+
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+		if string(body) != w.body {
+			t.Errorf("%s body = %q, want %q", w.contentType, body, w.body)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("NextPart() error = %v, want io.EOF after two parts", err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package mailtest provides an in-process SMTP server for testing senders of email, like httptest does for HTTP.
+package mailtest
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is a message received by the Server, Data is the message as sent after the DATA command.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Server accepts every message sent to it without authentication or TLS, and keeps them in memory.
+type Server struct {
+	Host string
+	Port string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+
+	// Received gets every message once it is accepted.
+	Received chan Message
+}
+
+// NewServer starts a server listening on a random port of 127.0.0.1.
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	s := &Server{
+		Host:     host,
+		Port:     port,
+		listener: listener,
+		Received: make(chan Message, 100),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Close stops the server and waits for the open sessions to end.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// Messages returns the messages received so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message{}, s.messages...)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.session(conn)
+		}()
+	}
+}
+
+// session speaks enough SMTP for net/smtp.SendMail: EHLO, MAIL, RCPT, DATA, RSET, NOOP and QUIT.
+func (s *Server) session(conn net.Conn) {
+	c := textproto.NewConn(conn)
+	defer c.Close()
+
+	var msg Message
+	if c.PrintfLine("220 mailtest ready") != nil {
+		return
+	}
+
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			err = c.PrintfLine("250 mailtest")
+		case "MAIL":
+			msg = Message{From: address(arg)}
+			err = c.PrintfLine("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			err = c.PrintfLine("250 OK")
+		case "DATA":
+			if err = c.PrintfLine("354 End data with <CR><LF>.<CR><LF>"); err != nil {
+				return
+			}
+			msg.Data, err = c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			s.Received <- msg
+
+			err = c.PrintfLine("250 OK")
+		case "RSET":
+			msg = Message{}
+			err = c.PrintfLine("250 OK")
+		case "NOOP":
+			err = c.PrintfLine("250 OK")
+		case "QUIT":
+			c.PrintfLine("221 Bye")
+			return
+		default:
+			err = c.PrintfLine("502 Command not implemented")
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// address returns the address of a FROM:<address> or TO:<address> argument.
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
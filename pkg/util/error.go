@@ -8,12 +8,14 @@ import (
 )
 
 var (
-	ErrNotFound       = errors.New("not found")
-	ErrInvalidRequest = errors.New("invalid data")
-	ErrUnauthorized   = errors.New("unauthorized")
-	ErrPermission     = errors.New("permission denied")
-	ErrUnprocessable  = errors.New("unprocessable entity")
-	ErrInternalServer = errors.New("internal server error")
+	ErrNotFound           = errors.New("not found")
+	ErrInvalidRequest     = errors.New("invalid data")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrPermission         = errors.New("permission denied")
+	ErrUnprocessable      = errors.New("unprocessable entity")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrInternalServer     = errors.New("internal server error")
 )
 
 func NewErrNotFound(message string) error {
@@ -36,6 +38,14 @@ func NewErrUnprocessable(message string) error {
 	return fmt.Errorf("%s:%w", message, ErrUnprocessable)
 }
 
+func NewErrConflict(message string) error {
+	return fmt.Errorf("%s:%w", message, ErrConflict)
+}
+
+func NewErrPreconditionFailed(message string) error {
+	return fmt.Errorf("%s:%w", message, ErrPreconditionFailed)
+}
+
 func NewErrInternalServer(message string) error {
 	return fmt.Errorf("%s:%w", message, ErrInternalServer)
 }
@@ -60,6 +70,12 @@ func ErrStatusCode(err error) (int, string) {
 	case errors.Is(err, ErrUnprocessable):
 		return http.StatusUnprocessableEntity, errMessage
 
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict, errMessage
+
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, errMessage
+
 	case errors.Is(err, ErrInternalServer):
 		return http.StatusInternalServerError, errMessage
 
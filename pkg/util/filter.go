@@ -0,0 +1,45 @@
+package util
+
+import (
+	"go-api/internal/entities"
+	"net/url"
+	"strings"
+)
+
+const ATTRIBUTE_FILTER_PREFIX = "attr."
+
+// AttributeFilters collects attr.<key>=<value> query params into a key/value map.
+func AttributeFilters(q url.Values) map[string]string {
+	filters := map[string]string{}
+
+	for k, v := range q {
+		if !strings.HasPrefix(k, ATTRIBUTE_FILTER_PREFIX) || len(v) == 0 {
+			continue
+		}
+
+		key := strings.TrimPrefix(k, ATTRIBUTE_FILTER_PREFIX)
+		if key == "" {
+			continue
+		}
+		filters[key] = v[0]
+	}
+
+	return filters
+}
+
+// DeviceGroupListParams returns the device list filter selecting the members of a group,
+// the membership table for static groups and the saved filter for dynamic ones.
+func DeviceGroupListParams(group *entities.DeviceGroup) entities.GetDeviceListParams {
+	if group.Type != entities.DEVICE_GROUP_TYPE_DYNAMIC || group.Filter == nil {
+		return entities.GetDeviceListParams{GroupID: group.ID}
+	}
+
+	// the selector is validated when the group is saved
+	selector, _ := ParseLabelSelector(group.Filter.Selector)
+
+	return entities.GetDeviceListParams{
+		Search:     group.Filter.Search,
+		Attributes: group.Filter.Attributes,
+		Selector:   selector,
+	}
+}
@@ -0,0 +1,137 @@
+package util
+
+import (
+	"fmt"
+	"go-api/internal/entities"
+	"strconv"
+	"strings"
+)
+
+// MAX_GEO_RADIUS is the largest radius accepted by radius filters, in meters.
+const MAX_GEO_RADIUS = 1000000
+
+// ParseGeoFilter parses the near=lat,lng, radius=500m and bbox=minLng,minLat,maxLng,maxLat query params.
+// Every invalid param is reported, so the caller can return them all at once.
+func ParseGeoFilter(near, radius, bbox string) (entities.GeoFilter, []string) {
+	filter := entities.GeoFilter{}
+	errMessages := []string{}
+
+	if near != "" {
+		point, err := parseGeoPoint(near)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("near %s", err.Error()))
+		} else {
+			filter.Near = &point
+		}
+	}
+
+	if radius != "" {
+		meters, err := parseDistance(radius)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("radius %s", err.Error()))
+		} else if near == "" {
+			errMessages = append(errMessages, "radius requires near")
+		} else if filter.Near != nil {
+			filter.Radius = meters
+		}
+	}
+
+	if bbox != "" {
+		box, err := parseBoundingBox(bbox)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("bbox %s", err.Error()))
+		} else {
+			filter.BoundingBox = &box
+		}
+	}
+
+	return filter, errMessages
+}
+
+func parseGeoPoint(value string) (entities.GeoPoint, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return entities.GeoPoint{}, fmt.Errorf("must be lat,lng: %s", value)
+	}
+
+	lat, err := parseCoordinate(parts[0], 90)
+	if err != nil {
+		return entities.GeoPoint{}, fmt.Errorf("has invalid latitude: %s", parts[0])
+	}
+	lng, err := parseCoordinate(parts[1], 180)
+	if err != nil {
+		return entities.GeoPoint{}, fmt.Errorf("has invalid longitude: %s", parts[1])
+	}
+
+	return entities.GeoPoint{Latitude: lat, Longitude: lng}, nil
+}
+
+func parseBoundingBox(value string) (entities.BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return entities.BoundingBox{}, fmt.Errorf("must be minLng,minLat,maxLng,maxLat: %s", value)
+	}
+
+	coordinates := make([]float64, 4)
+	for i, part := range parts {
+		limit := 180.0
+		if i%2 == 1 {
+			limit = 90
+		}
+
+		c, err := parseCoordinate(part, limit)
+		if err != nil {
+			return entities.BoundingBox{}, fmt.Errorf("has invalid coordinate: %s", part)
+		}
+		coordinates[i] = c
+	}
+
+	if coordinates[1] > coordinates[3] {
+		return entities.BoundingBox{}, fmt.Errorf("min latitude is greater than max latitude: %s", value)
+	}
+
+	return entities.BoundingBox{
+		MinLongitude: coordinates[0],
+		MinLatitude:  coordinates[1],
+		MaxLongitude: coordinates[2],
+		MaxLatitude:  coordinates[3],
+	}, nil
+}
+
+func parseCoordinate(value string, limit float64) (float64, error) {
+	c, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	if c < -limit || c > limit {
+		return 0, fmt.Errorf("out of range")
+	}
+
+	return c, nil
+}
+
+// parseDistance parses a distance such as 500, 500m or 1.5km into meters.
+func parseDistance(value string) (float64, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(value, "km"):
+		unit = 1000
+		value = strings.TrimSuffix(value, "km")
+	case strings.HasSuffix(value, "m"):
+		value = strings.TrimSuffix(value, "m")
+	}
+
+	distance, err := strconv.ParseFloat(value, 64)
+	if err != nil || distance <= 0 {
+		return 0, fmt.Errorf("must be a positive distance such as 500m or 2km")
+	}
+
+	distance *= unit
+	if distance > MAX_GEO_RADIUS {
+		return 0, fmt.Errorf("must not exceed %dm", MAX_GEO_RADIUS)
+	}
+
+	return distance, nil
+}
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"fmt"
+	"go-api/internal/entities"
+	"regexp"
+	"strings"
+)
+
+const MAX_LABEL_LENGTH = 63
+
+var (
+	labelNameRegex  = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	labelValueRegex = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+	setRequirement  = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// ParseLabelSelector parses a selector such as "site=north,env!=test,tier in (a,b),!legacy".
+// Every invalid requirement is reported, so the caller can return them all at once.
+func ParseLabelSelector(selector string) ([]entities.LabelRequirement, []string) {
+	requirements := []entities.LabelRequirement{}
+	errMessages := []string{}
+
+	if strings.TrimSpace(selector) == "" {
+		return requirements, errMessages
+	}
+
+	for _, part := range splitSelector(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			errMessages = append(errMessages, "selector has an empty requirement")
+			continue
+		}
+
+		req, err := parseRequirement(part)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("selector %q %s", part, err.Error()))
+			continue
+		}
+		requirements = append(requirements, req)
+	}
+
+	return requirements, errMessages
+}
+
+// ValidateLabelKey checks a key in the form [prefix/]name.
+func ValidateLabelKey(key string) error {
+	name := key
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if prefix == "" || len(prefix) > 253 || !labelNameRegex.MatchString(prefix) {
+			return fmt.Errorf("has invalid key prefix %q", prefix)
+		}
+	}
+
+	if name == "" || len(name) > MAX_LABEL_LENGTH || !labelNameRegex.MatchString(name) {
+		return fmt.Errorf("has invalid key %q", key)
+	}
+
+	return nil
+}
+
+func ValidateLabelValue(value string) error {
+	if len(value) > MAX_LABEL_LENGTH || !labelValueRegex.MatchString(value) {
+		return fmt.Errorf("has invalid value %q", value)
+	}
+
+	return nil
+}
+
+func parseRequirement(part string) (entities.LabelRequirement, error) {
+	if m := setRequirement.FindStringSubmatch(part); m != nil {
+		return setLabelRequirement(m[1], entities.SelectorOperator(m[2]), m[3])
+	}
+
+	if strings.HasPrefix(part, "!") {
+		key := strings.TrimSpace(part[1:])
+		if err := ValidateLabelKey(key); err != nil {
+			return entities.LabelRequirement{}, err
+		}
+		return entities.LabelRequirement{Key: key, Operator: entities.SELECTOR_OPERATOR_NOT_EXISTS}, nil
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		i := strings.Index(part, op)
+		if i < 0 {
+			continue
+		}
+
+		key := strings.TrimSpace(part[:i])
+		value := strings.TrimSpace(part[i+len(op):])
+		if err := ValidateLabelKey(key); err != nil {
+			return entities.LabelRequirement{}, err
+		}
+		if err := ValidateLabelValue(value); err != nil {
+			return entities.LabelRequirement{}, err
+		}
+
+		operator := entities.SELECTOR_OPERATOR_EQUALS
+		if op == "!=" {
+			operator = entities.SELECTOR_OPERATOR_NOT_EQUALS
+		}
+		return entities.LabelRequirement{Key: key, Operator: operator, Values: []string{value}}, nil
+	}
+
+	if err := ValidateLabelKey(part); err != nil {
+		return entities.LabelRequirement{}, fmt.Errorf("is not a valid requirement")
+	}
+
+	return entities.LabelRequirement{Key: part, Operator: entities.SELECTOR_OPERATOR_EXISTS}, nil
+}
+
+func setLabelRequirement(key string, operator entities.SelectorOperator, valueList string) (entities.LabelRequirement, error) {
+	if err := ValidateLabelKey(key); err != nil {
+		return entities.LabelRequirement{}, err
+	}
+
+	values := []string{}
+	for _, v := range strings.Split(valueList, ",") {
+		v = strings.TrimSpace(v)
+		if err := ValidateLabelValue(v); err != nil {
+			return entities.LabelRequirement{}, err
+		}
+		values = append(values, v)
+	}
+
+	if len(values) == 1 && values[0] == "" {
+		return entities.LabelRequirement{}, fmt.Errorf("has an empty value set")
+	}
+
+	return entities.LabelRequirement{Key: key, Operator: operator, Values: values}, nil
+}
+
+// splitSelector splits requirements on commas that are not inside a value set.
+func splitSelector(selector string) []string {
+	parts := []string{}
+	depth := 0
+	start := 0
+
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, selector[start:])
+
+	return parts
+}
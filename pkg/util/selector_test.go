@@ -0,0 +1,116 @@
+package util
+
+import (
+	"go-api/internal/entities"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []entities.LabelRequirement
+	}{
+		{"", []entities.LabelRequirement{}},
+		{"   ", []entities.LabelRequirement{}},
+		{"site=north", []entities.LabelRequirement{
+			{Key: "site", Operator: entities.SELECTOR_OPERATOR_EQUALS, Values: []string{"north"}},
+		}},
+		{"site==north", []entities.LabelRequirement{
+			{Key: "site", Operator: entities.SELECTOR_OPERATOR_EQUALS, Values: []string{"north"}},
+		}},
+		{"site=", []entities.LabelRequirement{
+			{Key: "site", Operator: entities.SELECTOR_OPERATOR_EQUALS, Values: []string{""}},
+		}},
+		{"env!=test", []entities.LabelRequirement{
+			{Key: "env", Operator: entities.SELECTOR_OPERATOR_NOT_EQUALS, Values: []string{"test"}},
+		}},
+		{"tier in (a,b)", []entities.LabelRequirement{
+			{Key: "tier", Operator: entities.SELECTOR_OPERATOR_IN, Values: []string{"a", "b"}},
+		}},
+		{"tier notin (a)", []entities.LabelRequirement{
+			{Key: "tier", Operator: entities.SELECTOR_OPERATOR_NOT_IN, Values: []string{"a"}},
+		}},
+		{"tier notin (a,)", []entities.LabelRequirement{
+			{Key: "tier", Operator: entities.SELECTOR_OPERATOR_NOT_IN, Values: []string{"a", ""}},
+		}},
+		{"legacy", []entities.LabelRequirement{
+			{Key: "legacy", Operator: entities.SELECTOR_OPERATOR_EXISTS},
+		}},
+		{"!legacy", []entities.LabelRequirement{
+			{Key: "legacy", Operator: entities.SELECTOR_OPERATOR_NOT_EXISTS},
+		}},
+		{"example.com/team=ops", []entities.LabelRequirement{
+			{Key: "example.com/team", Operator: entities.SELECTOR_OPERATOR_EQUALS, Values: []string{"ops"}},
+		}},
+		{"site=north,env!=test,tier in (a,b),!legacy", []entities.LabelRequirement{
+			{Key: "site", Operator: entities.SELECTOR_OPERATOR_EQUALS, Values: []string{"north"}},
+			{Key: "env", Operator: entities.SELECTOR_OPERATOR_NOT_EQUALS, Values: []string{"test"}},
+			{Key: "tier", Operator: entities.SELECTOR_OPERATOR_IN, Values: []string{"a", "b"}},
+			{Key: "legacy", Operator: entities.SELECTOR_OPERATOR_NOT_EXISTS},
+		}},
+		// whitespace around keys, operators, values and requirements is ignored
+		{"  site = north , env != test ,tier  in(a , b ) , ! legacy ,owner", []entities.LabelRequirement{
+			{Key: "site", Operator: entities.SELECTOR_OPERATOR_EQUALS, Values: []string{"north"}},
+			{Key: "env", Operator: entities.SELECTOR_OPERATOR_NOT_EQUALS, Values: []string{"test"}},
+			{Key: "tier", Operator: entities.SELECTOR_OPERATOR_IN, Values: []string{"a", "b"}},
+			{Key: "legacy", Operator: entities.SELECTOR_OPERATOR_NOT_EXISTS},
+			{Key: "owner", Operator: entities.SELECTOR_OPERATOR_EXISTS},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, errs := ParseLabelSelector(tt.selector)
+			if len(errs) > 0 {
+				t.Fatalf("ParseLabelSelector() errors = %v", errs)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLabelSelector() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLabelSelectorErrors(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"site=north,,env=test", []string{"selector has an empty requirement"}},
+		{"site=north,", []string{"selector has an empty requirement"}},
+		{"-site=north", []string{`selector "-site=north" has invalid key "-site"`}},
+		{"=north", []string{`selector "=north" has invalid key ""`}},
+		{"si te=north", []string{`selector "si te=north" has invalid key "si te"`}},
+		{strings.Repeat("k", 64) + "=v", []string{`selector "` + strings.Repeat("k", 64) + `=v" has invalid key "` + strings.Repeat("k", 64) + `"`}},
+		{"/team=ops", []string{`selector "/team=ops" has invalid key prefix ""`}},
+		{"-example.com/team=ops", []string{`selector "-example.com/team=ops" has invalid key prefix "-example.com"`}},
+		{"example.com/=ops", []string{`selector "example.com/=ops" has invalid key "example.com/"`}},
+		{"site=north!", []string{`selector "site=north!" has invalid value "north!"`}},
+		{"site=-north", []string{`selector "site=-north" has invalid value "-north"`}},
+		{"site=" + strings.Repeat("v", 64), []string{`selector "site=` + strings.Repeat("v", 64) + `" has invalid value "` + strings.Repeat("v", 64) + `"`}},
+		{"env!=te st", []string{`selector "env!=te st" has invalid value "te st"`}},
+		{"tier in ()", []string{`selector "tier in ()" has an empty value set`}},
+		{"tier in (a,b c)", []string{`selector "tier in (a,b c)" has invalid value "b c"`}},
+		{"-tier in (a)", []string{`selector "-tier in (a)" has invalid key "-tier"`}},
+		{"!", []string{`selector "!" has invalid key ""`}},
+		{"!-legacy", []string{`selector "!-legacy" has invalid key "-legacy"`}},
+		{"legacy?", []string{`selector "legacy?" is not a valid requirement`}},
+		{"tier in a,b", []string{`selector "tier in a" is not a valid requirement`}},
+		// every invalid requirement is reported
+		{"-a=b,site=north,c=-d", []string{
+			`selector "-a=b" has invalid key "-a"`,
+			`selector "c=-d" has invalid value "-d"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			_, errs := ParseLabelSelector(tt.selector)
+			if !reflect.DeepEqual(errs, tt.want) {
+				t.Errorf("ParseLabelSelector() errors = %q, want %q", errs, tt.want)
+			}
+		})
+	}
+}
@@ -8,10 +8,14 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
-var uuidRegex *regexp.Regexp
+var (
+	uuidRegex *regexp.Regexp
+	slugRegex *regexp.Regexp
+)
 
 func init() {
 	uuidRegex, _ = regexp.Compile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	slugRegex, _ = regexp.Compile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 }
 
 func RegisterCustomValidator(validate *validator.Validate) {
@@ -29,6 +33,56 @@ func RegisterCustomValidator(validate *validator.Validate) {
 	if err != nil {
 		fmt.Println("Error registering custom validation :", err.Error())
 	}
+
+	err = validate.RegisterValidation("commandAckStatus", CommandAckStatus)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("labels", Labels)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("deviceGroupType", DeviceGroupType)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("slug", Slug)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("maintenanceRecurrence", MaintenanceRecurrence)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("calibrationKind", CalibrationKind)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("alertOperator", AlertOperator)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("alertSeverity", AlertSeverity)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("alertCondition", AlertCondition)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
+
+	err = validate.RegisterValidation("escalationGroupBy", EscalationGroupBy)
+	if err != nil {
+		fmt.Println("Error registering custom validation :", err.Error())
+	}
 }
 
 func ParseValidatorErr(err error) []string {
@@ -69,3 +123,103 @@ func SensorType(fl validator.FieldLevel) bool {
 
 	return false
 }
+
+func CommandAckStatus(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value != string(entities.COMMAND_STATUS_SUCCEEDED) && value != string(entities.COMMAND_STATUS_FAILED) {
+		return false
+	}
+	return true
+}
+
+func Labels(fl validator.FieldLevel) bool {
+	labels, ok := fl.Field().Interface().(map[string]string)
+	if !ok {
+		return false
+	}
+
+	for k, v := range labels {
+		if ValidateLabelKey(k) != nil || ValidateLabelValue(v) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func DeviceGroupType(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value != string(entities.DEVICE_GROUP_TYPE_STATIC) && value != string(entities.DEVICE_GROUP_TYPE_DYNAMIC) {
+		return false
+	}
+	return true
+}
+
+func Slug(fl validator.FieldLevel) bool {
+	return slugRegex.MatchString(fl.Field().String())
+}
+
+func MaintenanceRecurrence(fl validator.FieldLevel) bool {
+	value := entities.MaintenanceRecurrence(fl.Field().String())
+	if value != entities.MAINTENANCE_RECURRENCE_NONE &&
+		value != entities.MAINTENANCE_RECURRENCE_DAILY &&
+		value != entities.MAINTENANCE_RECURRENCE_WEEKLY {
+		return false
+	}
+	return true
+}
+
+func CalibrationKind(fl validator.FieldLevel) bool {
+	switch entities.CalibrationKind(fl.Field().String()) {
+	case entities.CALIBRATION_KIND_OFFSET,
+		entities.CALIBRATION_KIND_SCALE,
+		entities.CALIBRATION_KIND_POLYNOMIAL,
+		entities.CALIBRATION_KIND_MULTIPOINT:
+		return true
+	}
+	return false
+}
+
+func AlertOperator(fl validator.FieldLevel) bool {
+	switch entities.AlertOperator(fl.Field().String()) {
+	case entities.ALERT_OPERATOR_GT,
+		entities.ALERT_OPERATOR_GTE,
+		entities.ALERT_OPERATOR_LT,
+		entities.ALERT_OPERATOR_LTE:
+		return true
+	}
+	return false
+}
+
+func AlertSeverity(fl validator.FieldLevel) bool {
+	switch entities.AlertSeverity(fl.Field().String()) {
+	case entities.ALERT_SEVERITY_INFO,
+		entities.ALERT_SEVERITY_WARNING,
+		entities.ALERT_SEVERITY_CRITICAL:
+		return true
+	}
+	return false
+}
+
+func AlertCondition(fl validator.FieldLevel) bool {
+	switch entities.AlertCondition(fl.Field().String()) {
+	case entities.ALERT_CONDITION_THRESHOLD,
+		entities.ALERT_CONDITION_NO_DATA,
+		entities.ALERT_CONDITION_RATE_OF_CHANGE,
+		entities.ALERT_CONDITION_ANOMALY,
+		entities.ALERT_CONDITION_COMPOSITE:
+		return true
+	}
+	return false
+}
+
+func EscalationGroupBy(fl validator.FieldLevel) bool {
+	switch entities.EscalationGroupBy(fl.Field().String()) {
+	case entities.ESCALATION_GROUP_BY_RULE,
+		entities.ESCALATION_GROUP_BY_DEVICE,
+		entities.ESCALATION_GROUP_BY_SENSOR,
+		entities.ESCALATION_GROUP_BY_SEVERITY:
+		return true
+	}
+	return false
+}